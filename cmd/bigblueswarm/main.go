@@ -2,9 +2,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/app"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
@@ -25,6 +28,7 @@ var (
 	configPath             = ""
 	logLevel               = ""
 	logPath                = ""
+	logFormat              = ""
 	sentryDsn              = ""
 	sentryTraceSampleRates = float64(1.0)
 )
@@ -96,10 +100,18 @@ func initLog() (*os.File, error) {
 
 	log.SetReportCaller(true)
 
-	log.SetFormatter(&log.TextFormatter{
-		DisableColors: disableColors,
-		FullTimestamp: true,
-	})
+	switch logFormat {
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+		app.JSONLogEnabled = true
+	case "text":
+		log.SetFormatter(&log.TextFormatter{
+			DisableColors: disableColors,
+			FullTimestamp: true,
+		})
+	default:
+		return file, fmt.Errorf("unknown log format %s", logFormat)
+	}
 
 	return file, err
 }
@@ -107,6 +119,7 @@ func initLog() (*os.File, error) {
 func parseFlags() {
 	flag.StringVar(&configPath, "config", config.DefaultConfigPath(), "Config file path")
 	flag.StringVar(&logLevel, "log.level", log.InfoLevel.String(), "Log level. Default is debug for development")
+	flag.StringVar(&logFormat, "log.format", "text", "Log format. Either text or json")
 	flag.StringVar(&logPath, "log.path", "", "Log path. Specify a path to write into a file. By default BigBlueSwarm prints log in stdout")
 	flag.StringVar(&sentryDsn, "sentry.dsn", "", "Sentry DSN. fill it with a valid DSN to enable sentry error management")
 	flag.Float64Var(&sentryTraceSampleRates, "sentry.rates", float64(1.0), "Sentry trace sample rates. The sample rate for sampling traces in the range [0.0, 1.0].")
@@ -142,7 +155,9 @@ func run(conf *config.Config) error {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	err := app.NewServer(conf).Run()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	err := app.NewServer(conf).Run(ctx)
 
 	if err != nil {
 		return err
@@ -2,30 +2,39 @@
 package admin
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
 
 	"github.com/gin-gonic/gin"
-	log "github.com/sirupsen/logrus"
 )
 
 // Admin struct manager bigblueswarm administration
 type Admin struct {
 	InstanceManager InstanceManager
 	TenantManager   TenantManager
+	AuditManager    AuditManager
+	HistoryManager  MeetingHistoryManager
+	WebhookManager  WebhookManager
 	Balancer        balancer.Balancer
 	Config          *config.Config
 }
 
 // CreateAdmin creates a new admin based on given configuration
-func CreateAdmin(manager InstanceManager, tenantManager TenantManager, balancer balancer.Balancer, config *config.Config) *Admin {
+func CreateAdmin(manager InstanceManager, tenantManager TenantManager, auditManager AuditManager, historyManager MeetingHistoryManager, webhookManager WebhookManager, balancer balancer.Balancer, config *config.Config) *Admin {
 	return &Admin{
 		InstanceManager: manager,
 		TenantManager:   tenantManager,
+		AuditManager:    auditManager,
+		HistoryManager:  historyManager,
+		WebhookManager:  webhookManager,
 		Config:          config,
 		Balancer:        balancer,
 	}
@@ -36,7 +45,7 @@ func (a *Admin) ListInstances(c *gin.Context) {
 	instances, err := a.InstanceManager.ListInstances()
 	if err != nil {
 		e := fmt.Errorf("failed to list instances: %s", err)
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusInternalServerError, e.Error())
 		return
 	}
@@ -49,7 +58,7 @@ func (a *Admin) ClusterStatus(c *gin.Context) {
 	instances, err := a.InstanceManager.List()
 	if err != nil {
 		e := fmt.Errorf("failed to retrieve instances: %s", err.Error())
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusInternalServerError, e.Error())
 		return
 	}
@@ -57,7 +66,7 @@ func (a *Admin) ClusterStatus(c *gin.Context) {
 	status, err := a.Balancer.ClusterStatus(instances)
 	if err != nil {
 		e := fmt.Errorf("failed to retrieve balancer cluster status: %s", err)
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusInternalServerError, e.Error())
 		return
 	}
@@ -72,16 +81,25 @@ func (a *Admin) SetInstances(c *gin.Context) {
 	instanceList := &InstanceList{}
 	if err := c.ShouldBindJSON(instanceList); err != nil {
 		e := fmt.Errorf("body does not bind InstanceList object: %s", err)
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusBadRequest, e.Error())
 		return
 	}
 
+	before, err := a.InstanceManager.ListInstances()
+	if err != nil {
+		e := fmt.Errorf("failed to list instances: %s", err)
+		getLogger(c).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
 	if err := a.InstanceManager.SetInstances(instanceList.Instances); err != nil {
 		e := fmt.Errorf("failed to set instances in instance manager: %s", err)
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusInternalServerError, e.Error())
 	} else {
+		a.audit(c, AuditSetInstances, "instances", auditInstances(instancesMap(before)), auditInstances(instanceList.Instances))
 		c.AbortWithStatus(http.StatusCreated)
 	}
 }
@@ -93,12 +111,12 @@ func (a *Admin) CreateTenant(c *gin.Context) {
 	tenant := &Tenant{}
 	if err := c.ShouldBindJSON(tenant); err != nil {
 		e := fmt.Errorf("body does not bind Tenant object: %s", err)
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusBadRequest, e.Error())
 		return
 	}
 
-	logger := log.WithField("tenant", tenant.Spec.Host)
+	logger := getLogger(c).AddField("tenant", tenant.Spec.Host)
 	if tenant.Spec.Host == "" {
 		m := "failed to create tenant. Tenant spec host should not be null"
 		logger.Warn(m)
@@ -106,13 +124,27 @@ func (a *Admin) CreateTenant(c *gin.Context) {
 		return
 	}
 
+	before, err := a.TenantManager.GetTenant(tenant.Spec.Host)
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve tenant: %s", err)
+		logger.Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
 	if err := a.TenantManager.AddTenant(tenant); err != nil {
 		e := fmt.Errorf("failed to add tenant in tenant manager: %s", err)
 		logger.Error(e)
-		c.String(http.StatusInternalServerError, e.Error())
+		c.String(addTenantStatus(err), e.Error())
 		return
 	}
 
+	action := AuditCreateTenant
+	if before != nil {
+		action = AuditUpdateTenant
+	}
+
+	a.audit(c, action, tenantKey(tenant.Spec.Host), auditTenant(before), auditTenant(tenant))
 	logger.Info("tenant successfully created")
 	c.AbortWithStatus(http.StatusCreated)
 }
@@ -122,7 +154,7 @@ func (a *Admin) ListTenants(c *gin.Context) {
 	tenants, err := a.TenantManager.ListTenants()
 	if err != nil {
 		e := fmt.Errorf("unable to list all tenants: %s", err)
-		log.Error(e)
+		getLogger(c).Error(e)
 		c.String(http.StatusInternalServerError, e.Error())
 		return
 	}
@@ -140,12 +172,12 @@ func (a *Admin) DeleteTenant(c *gin.Context) {
 	hostname, exists := c.Params.Get("hostname")
 	if !exists || strings.TrimSpace(hostname) == "" {
 		m := "hostname not found or empty"
-		log.Warn(m)
+		getLogger(c).Warn(m)
 		c.String(http.StatusBadRequest, m)
 		return
 	}
 
-	logger := log.WithField("tenant", hostname)
+	logger := getLogger(c).AddField("tenant", hostname)
 	tenant, err := a.TenantManager.GetTenant(hostname)
 	if err != nil {
 		e := fmt.Errorf("failed to retrieve tenant: %s", err.Error())
@@ -166,6 +198,7 @@ func (a *Admin) DeleteTenant(c *gin.Context) {
 		logger.Error(m, err)
 		c.String(http.StatusInternalServerError, m)
 	} else {
+		a.audit(c, AuditDeleteTenant, tenantKey(hostname), auditTenant(tenant), nil)
 		logger.Info("tenant successfully deleted")
 		c.AbortWithStatus(http.StatusNoContent)
 	}
@@ -181,12 +214,12 @@ func (a *Admin) GetTenant(c *gin.Context) {
 	hostname, exists := c.Params.Get("hostname")
 	if !exists || strings.TrimSpace(hostname) == "" {
 		m := "hostname not found or empty"
-		log.Warn(m)
+		getLogger(c).Warn(m)
 		c.String(http.StatusBadRequest, m)
 		return
 	}
 
-	logger := log.WithField("tenant", hostname)
+	logger := getLogger(c).AddField("tenant", hostname)
 	tenant, err := a.TenantManager.GetTenant(hostname)
 	if err != nil {
 		m := "unable to retrieve tenant"
@@ -203,3 +236,204 @@ func (a *Admin) GetTenant(c *gin.Context) {
 
 	c.AbortWithStatusJSON(http.StatusOK, tenant)
 }
+
+// addTenantStatus returns the HTTP status of a tenant manager AddTenant error: a HTTP 400 - Bad Request if the tenant is
+// invalid, a HTTP 500 - Internal Server Error otherwise
+func addTenantStatus(err error) int {
+	var validationErr *TenantValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+func (a *Admin) getTenantFromParams(c *gin.Context) (*Tenant, bool) {
+	hostname, exists := c.Params.Get("hostname")
+	if !exists || strings.TrimSpace(hostname) == "" {
+		m := "hostname not found or empty"
+		getLogger(c).Warn(m)
+		c.String(http.StatusBadRequest, m)
+		return nil, false
+	}
+
+	logger := getLogger(c).AddField("tenant", hostname)
+	tenant, err := a.TenantManager.GetTenant(hostname)
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve tenant: %s", err.Error())
+		logger.Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return nil, false
+	}
+
+	if tenant == nil {
+		logger.Info("tenant not found")
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil, false
+	}
+
+	return tenant, true
+}
+
+// RotateSecret rotate a tenant secret. It takes an optional SecretRotation object in body.
+// The previous primary secret is kept as a secondary secret during the overlap duration
+func (a *Admin) RotateSecret(c *gin.Context) {
+	defer c.Request.Body.Close()
+
+	tenant, ok := a.getTenantFromParams(c)
+	if !ok {
+		return
+	}
+
+	logger := getLogger(c).AddField("tenant", tenant.Spec.Host)
+	rotation := &SecretRotation{}
+	if err := c.ShouldBindJSON(rotation); err != nil && !errors.Is(err, io.EOF) {
+		e := fmt.Errorf("body does not bind SecretRotation object: %s", err)
+		logger.Error(e)
+		c.String(http.StatusBadRequest, e.Error())
+		return
+	}
+
+	overlap := DefaultSecretOverlap
+	if rotation.Overlap != "" {
+		duration, err := time.ParseDuration(rotation.Overlap)
+		if err != nil {
+			e := fmt.Errorf("invalid overlap duration: %s", err)
+			logger.Error(e)
+			c.String(http.StatusBadRequest, e.Error())
+			return
+		}
+
+		if duration < 0 {
+			e := errors.New("overlap duration can't be negative")
+			logger.Error(e)
+			c.String(http.StatusBadRequest, e.Error())
+			return
+		}
+
+		overlap = duration
+	}
+
+	before := auditTenant(tenant)
+	secret, err := a.rotateSecret(tenant, rotation.Secret, overlap)
+	if err != nil {
+		e := fmt.Errorf("failed to rotate tenant secret: %s", err)
+		logger.Error(e)
+		c.String(addTenantStatus(err), e.Error())
+		return
+	}
+
+	a.audit(c, AuditRotateSecret, tenantKey(tenant.Spec.Host), before, auditTenant(tenant))
+
+	logger.WithField("fingerprint", secret.Fingerprint).Info("tenant secret successfully rotated")
+	c.JSON(http.StatusOK, secret)
+}
+
+// ListSecrets list a tenant accepted secrets fingerprints and reports how many requests used each secret
+func (a *Admin) ListSecrets(c *gin.Context) {
+	tenant, ok := a.getTenantFromParams(c)
+	if !ok {
+		return
+	}
+
+	usage, err := a.TenantManager.GetSecretUsage(tenant.Spec.Host)
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve tenant secrets usage: %s", err)
+		getLogger(c).AddField("tenant", tenant.Spec.Host).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	primary := SecretFingerprint(tenant.PrimarySecret(a.Config.BigBlueSwarm.Secret))
+	secrets := []SecretStatus{{
+		Fingerprint: primary,
+		Primary:     true,
+		Usage:       usage[primary].Count,
+		LastUsed:    usage[primary].LastUsed,
+	}}
+
+	now := time.Now()
+	for _, secondary := range tenant.Spec.SecondarySecrets {
+		if !secondary.ExpiresAt.After(now) {
+			continue
+		}
+
+		fingerprint := SecretFingerprint(secondary.Secret)
+		expiresAt := secondary.ExpiresAt
+		secrets = append(secrets, SecretStatus{
+			Fingerprint: fingerprint,
+			ExpiresAt:   &expiresAt,
+			Usage:       usage[fingerprint].Count,
+			LastUsed:    usage[fingerprint].LastUsed,
+		})
+	}
+
+	c.JSON(http.StatusOK, secrets)
+}
+
+// GetTenantUsage returns the tenant consumption over the current quota period
+func (a *Admin) GetTenantUsage(c *gin.Context) {
+	tenant, ok := a.getTenantFromParams(c)
+	if !ok {
+		return
+	}
+
+	usage, err := a.TenantManager.GetQuotaUsage(tenant, time.Now())
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve tenant quota usage: %s", err)
+		getLogger(c).AddField("tenant", tenant.Spec.Host).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+func queryInt(c *gin.Context, name string, defaultValue int64) (int64, error) {
+	value, exists := c.GetQuery(name)
+	if !exists {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %s parameter %s", name, value)
+	}
+
+	return parsed, nil
+}
+
+// ListAudit returns a page of the administrative changes audit records, most recent first. The page is selected using
+// the `offset` and `limit` query parameters
+func (a *Admin) ListAudit(c *gin.Context) {
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil {
+		getLogger(c).Warn(err)
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, err := queryInt(c, "limit", DefaultAuditLimit)
+	if err != nil || limit == 0 || limit > MaxAuditLimit {
+		m := fmt.Sprintf("limit parameter should be between 1 and %d", MaxAuditLimit)
+		getLogger(c).Warn(m)
+		c.String(http.StatusBadRequest, m)
+		return
+	}
+
+	records, total, err := a.AuditManager.List(offset, limit)
+	if err != nil {
+		e := fmt.Errorf("failed to list audit records: %s", err)
+		getLogger(c).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, &AuditList{
+		Kind:    "AuditList",
+		Total:   total,
+		Offset:  offset,
+		Limit:   limit,
+		Records: records,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
@@ -33,7 +34,7 @@ func toInstanceStatusArray(body []byte) []balancer.InstanceStatus {
 func TestListInstances(t *testing.T) {
 	url := "http://localhost/bigbluebutton"
 	var w *httptest.ResponseRecorder
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 
 	tests := []test.Test{
 		{
@@ -81,7 +82,7 @@ func TestListInstances(t *testing.T) {
 func TestClusterStatus(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 
 	host := "http://localhost/bigbluebutton"
 	cpu := 20.01
@@ -164,7 +165,8 @@ func TestClusterStatus(t *testing.T) {
 func TestSetInstances(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	var record *AuditRecord
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 
 	tests := []test.Test{
 		{
@@ -177,6 +179,24 @@ func TestSetInstances(t *testing.T) {
 				assert.Equal(t, "body does not bind InstanceList object: EOF", w.Body.String())
 			},
 		},
+		{
+			Name: "an error returned by InstanceManager when listing the current instances should return an internal server error",
+			Mock: func() {
+				request.AddRequestBody(c, `{
+	"kind": "InstanceList",
+	"instances": {
+		"http://bigbluebutton1": "secret1"
+	}
+}`)
+				ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
+					return nil, errors.New("instance manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.Equal(t, "failed to list instances: instance manager error", w.Body.String())
+			},
+		},
 		{
 			Name: "an error returned by InstanceManager should return an internal server error and an error",
 			Mock: func() {
@@ -186,6 +206,9 @@ func TestSetInstances(t *testing.T) {
 		"http://bigbluebutton1": "secret1"
 	}
 }`)
+				ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
+					return []api.BigBlueButtonInstance{}, nil
+				}
 				SetInstancesInstanceManagerMockFunc = func(instances map[string]string) error {
 					return errors.New("instance manager error")
 				}
@@ -196,7 +219,7 @@ func TestSetInstances(t *testing.T) {
 			},
 		},
 		{
-			Name: "a valid request should return a http 200 ok",
+			Name: "a valid request should return a http 200 ok and record the change",
 			Mock: func() {
 				request.AddRequestBody(c, `{
 	"kind": "InstanceList",
@@ -204,12 +227,26 @@ func TestSetInstances(t *testing.T) {
 		"http://bigbluebutton1": "secret1"
 	}
 }`)
+				c.Request.Header.Set(ActorHeader, "operator")
+				setIdentity(c, "admin-key:"+SecretFingerprint("api_key"))
+				ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
+					return []api.BigBlueButtonInstance{{URL: "http://bigbluebutton0", Secret: "secret0"}}, nil
+				}
 				SetInstancesInstanceManagerMockFunc = func(instances map[string]string) error {
 					return nil
 				}
+				RecordAuditManagerMockFunc = func(r *AuditRecord) error {
+					record = r
+					return nil
+				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusCreated, w.Code)
+				assert.Equal(t, AuditSetInstances, record.Action)
+				assert.Equal(t, "operator", record.Actor)
+				assert.Equal(t, "admin-key:"+SecretFingerprint("api_key"), record.Identity)
+				assert.Equal(t, map[string]string{"http://bigbluebutton0": SecretFingerprint("secret0")}, record.Before)
+				assert.Equal(t, map[string]string{"http://bigbluebutton1": SecretFingerprint("secret1")}, record.After)
 			},
 		},
 	}
@@ -228,7 +265,8 @@ func TestSetInstances(t *testing.T) {
 func TestCreateTenant(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	var record *AuditRecord
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 
 	tests := []test.Test{
 		{
@@ -251,6 +289,9 @@ func TestCreateTenant(t *testing.T) {
 	},
 	"instances": []
 }`)
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, nil
+				}
 				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
 					return errors.New("manager error")
 				}
@@ -261,7 +302,79 @@ func TestCreateTenant(t *testing.T) {
 			},
 		},
 		{
-			Name: "a valid request should return a 201 created status",
+			Name: "an invalid tenant should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.AddRequestBody(c, `{
+	"kind": "Tenant",
+	"spec": {
+  		"host": "localhost:8090"
+	},
+	"instances": []
+}`)
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, nil
+				}
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					return &TenantValidationError{Err: errors.New("alias bbb.localhost is already used by tenant other.localhost")}
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, "failed to add tenant in tenant manager: alias bbb.localhost is already used by tenant other.localhost", w.Body.String())
+			},
+		},
+		{
+			Name: "an error returned by tenant manager when retrieving the current tenant should return an internal server error",
+			Mock: func() {
+				request.AddRequestBody(c, `{
+	"kind": "Tenant",
+	"spec": {
+			"host": "localhost:8090"
+	},
+	"instances": []
+}`)
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.Equal(t, "failed to retrieve tenant: manager error", w.Body.String())
+			},
+		},
+		{
+			Name: "a valid request should return a 201 created status and record the creation",
+			Mock: func() {
+				request.AddRequestBody(c, `{
+	"kind": "Tenant",
+	"spec": {
+			"host": "localhost:8090",
+			"secret": "tenant_secret"
+	},
+	"instances": []
+}`)
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, nil
+				}
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					return nil
+				}
+				RecordAuditManagerMockFunc = func(r *AuditRecord) error {
+					record = r
+					return nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusCreated, w.Code)
+				assert.Equal(t, AuditCreateTenant, record.Action)
+				assert.Equal(t, "admin", record.Actor)
+				assert.Equal(t, "tenant:localhost:8090", record.Resource)
+				assert.Nil(t, record.Before.(*Tenant))
+				assert.Equal(t, SecretFingerprint("tenant_secret"), record.After.(*Tenant).Spec.Secret)
+			},
+		},
+		{
+			Name: "replacing an existing tenant should record an update",
 			Mock: func() {
 				request.AddRequestBody(c, `{
 	"kind": "Tenant",
@@ -270,12 +383,21 @@ func TestCreateTenant(t *testing.T) {
 	},
 	"instances": []
 }`)
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: hostname}}, nil
+				}
 				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
 					return nil
 				}
+				RecordAuditManagerMockFunc = func(r *AuditRecord) error {
+					record = r
+					return errors.New("audit error")
+				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusCreated, w.Code)
+				assert.Equal(t, AuditUpdateTenant, record.Action)
+				assert.Equal(t, "localhost:8090", record.Before.(*Tenant).Spec.Host)
 			},
 		},
 		{
@@ -308,7 +430,7 @@ func TestCreateTenant(t *testing.T) {
 func TestListTenantsHandler(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 
 	tests := []test.Test{
 		{
@@ -356,7 +478,7 @@ func TestListTenantsHandler(t *testing.T) {
 func TestDeleteHandler(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 
 	tests := []test.Test{
 		{
@@ -483,7 +605,7 @@ func TestGetConfigurtionHandler(t *testing.T) {
 			Bucket:       "bucket",
 		},
 	}
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, config)
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, config)
 
 	expected, err := json.Marshal(config)
 	if err != nil {
@@ -499,7 +621,7 @@ func TestGetConfigurtionHandler(t *testing.T) {
 func TestGetTenantHandler(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{})
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
 	tests := []test.Test{
 		{
 			Name: "an error returned by tenant manager should end with a HTTP 500 - Internal Server Error",
@@ -572,3 +694,296 @@ func TestGetTenantHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRotateSecretHandler(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	var stored *Tenant
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	hostnameParam := func() {
+		c.Params = gin.Params{
+			{
+				Key:   "hostname",
+				Value: "localhost",
+			},
+		}
+	}
+
+	tests := []test.Test{
+		{
+			Name: "no tenant found should return a HTTP 404 - Not Found error",
+			Mock: func() {
+				hostnameParam()
+				request.AddRequestBody(c, "")
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			Name: "an invalid overlap should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				hostnameParam()
+				request.AddRequestBody(c, `{"overlap": "invalid"}`)
+				GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return &Tenant{Spec: &TenantSpec{Host: "localhost", Secret: "old"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "a negative overlap should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				hostnameParam()
+				request.AddRequestBody(c, `{"overlap": "-1h"}`)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, "overlap duration can't be negative", w.Body.String())
+			},
+		},
+		{
+			Name: "an error returned by tenant manager while storing tenant should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				hostnameParam()
+				request.AddRequestBody(c, "")
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					return errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "an invalid tenant should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				hostnameParam()
+				request.AddRequestBody(c, "")
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					return &TenantValidationError{Err: errors.New("invalid webhook")}
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should rotate the secret and keep the previous one as secondary secret",
+			Mock: func() {
+				hostnameParam()
+				request.AddRequestBody(c, `{"secret": "new", "overlap": "1h"}`)
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					stored = tenant
+					return nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				secret := &TenantSecret{}
+				json.Unmarshal(w.Body.Bytes(), secret)
+				assert.Equal(t, "new", secret.Secret)
+				assert.Equal(t, SecretFingerprint("new"), secret.Fingerprint)
+				assert.Equal(t, "new", stored.Spec.Secret)
+				assert.Equal(t, "old", stored.Spec.SecondarySecrets[0].Secret)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			test.Mock()
+			admin.RotateSecret(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestListSecretsHandler(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	expiresAt := time.Now().Add(time.Hour)
+	GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+		return &Tenant{Spec: &TenantSpec{
+			Host:   "localhost",
+			Secret: "primary",
+			SecondarySecrets: []SecondarySecret{
+				{Secret: "secondary", ExpiresAt: expiresAt},
+				{Secret: "expired", ExpiresAt: time.Now().Add(-time.Hour)},
+			},
+		}}, nil
+	}
+
+	tests := []test.Test{
+		{
+			Name: "an error returned by tenant manager should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				GetSecretUsageTenantManagerMockFunc = func(hostname string) (map[string]SecretUsage, error) {
+					return nil, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should return the accepted secrets and their usage",
+			Mock: func() {
+				GetSecretUsageTenantManagerMockFunc = func(hostname string) (map[string]SecretUsage, error) {
+					return map[string]SecretUsage{
+						SecretFingerprint("primary"):   {Count: 10},
+						SecretFingerprint("secondary"): {Count: 2},
+					}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				secrets := []SecretStatus{}
+				json.Unmarshal(w.Body.Bytes(), &secrets)
+				assert.Equal(t, 2, len(secrets))
+				assert.True(t, secrets[0].Primary)
+				assert.Equal(t, int64(10), secrets[0].Usage)
+				assert.Equal(t, SecretFingerprint("secondary"), secrets[1].Fingerprint)
+				assert.Equal(t, int64(2), secrets[1].Usage)
+				assert.Equal(t, expiresAt.Unix(), secrets[1].ExpiresAt.Unix())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "hostname", Value: "localhost"}}
+			test.Mock()
+			admin.ListSecrets(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestGetTenantUsage(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+		return &Tenant{Spec: &TenantSpec{Host: "localhost"}}, nil
+	}
+
+	tests := []test.Test{
+		{
+			Name: "an error returned by tenant manager should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				GetQuotaUsageTenantManagerMockFunc = func(tenant *Tenant, now time.Time) (*QuotaUsage, error) {
+					return nil, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should return the tenant quota usage",
+			Mock: func() {
+				GetQuotaUsageTenantManagerMockFunc = func(tenant *Tenant, now time.Time) (*QuotaUsage, error) {
+					return &QuotaUsage{Period: QuotaPeriodMonth, Meetings: 3, ParticipantMinutes: 120}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				usage := &QuotaUsage{}
+				json.Unmarshal(w.Body.Bytes(), usage)
+				assert.Equal(t, int64(3), usage.Meetings)
+				assert.Equal(t, int64(120), usage.ParticipantMinutes)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "hostname", Value: "localhost"}}
+			test.Mock()
+			admin.GetTenantUsage(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestListAuditHandler(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+
+	tests := []test.Test{
+		{
+			Name: "an invalid limit should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "limit=1000")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "an invalid offset should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "offset=-1")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "an error returned by audit manager should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				request.SetRequestParams(c, "")
+				ListAuditManagerMockFunc = func(offset int64, limit int64) ([]AuditRecord, int64, error) {
+					return nil, 0, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should return the requested audit records page",
+			Mock: func() {
+				request.SetRequestParams(c, "offset=10&limit=5")
+				ListAuditManagerMockFunc = func(offset int64, limit int64) ([]AuditRecord, int64, error) {
+					assert.Equal(t, int64(10), offset)
+					assert.Equal(t, int64(5), limit)
+					return []AuditRecord{{ID: "id", Action: AuditDeleteTenant}}, 11, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				list := &AuditList{}
+				json.Unmarshal(w.Body.Bytes(), list)
+				assert.Equal(t, int64(11), list.Total)
+				assert.Equal(t, int64(10), list.Offset)
+				assert.Equal(t, int64(5), list.Limit)
+				assert.Equal(t, AuditDeleteTenant, list.Records[0].Action)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			test.Mock()
+			admin.ListAudit(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActorHeader is the header naming the actor of an administrative change. The actor is `admin` if the header is missing.
+// The header is informational only as it is not verified: the audit record identity is the authenticated identity
+const ActorHeader = "X-Actor"
+
+const (
+	// AuditSetInstances is the audit action of an instance list replacement
+	AuditSetInstances = "instances.set"
+	// AuditCreateTenant is the audit action of a tenant creation
+	AuditCreateTenant = "tenant.create"
+	// AuditUpdateTenant is the audit action of a tenant replacement
+	AuditUpdateTenant = "tenant.update"
+	// AuditDeleteTenant is the audit action of a tenant deletion
+	AuditDeleteTenant = "tenant.delete"
+	// AuditRotateSecret is the audit action of a tenant secret rotation
+	AuditRotateSecret = "tenant.rotate_secret"
+	// AuditRetryWebhookDelivery is the audit action of a dead tenant webhook delivery retry
+	AuditRetryWebhookDelivery = "webhook_delivery.retry"
+)
+
+const (
+	// DefaultAuditLimit is the default number of audit records returned by page
+	DefaultAuditLimit int64 = 50
+	// MaxAuditLimit is the maximum number of audit records returned by page
+	MaxAuditLimit int64 = 500
+)
+
+const maxActorLength = 128
+
+// auditActor returns the actor of the request. Requests authenticated with a tenant api key are made by the tenant
+func auditActor(c *gin.Context) string {
+	if tenant, exists := c.Get("tenant"); exists {
+		if t, ok := tenant.(*Tenant); ok && t != nil && t.Spec != nil {
+			return "tenant:" + t.Spec.Host
+		}
+	}
+
+	if c.Request == nil {
+		return "admin"
+	}
+
+	actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+	if actor == "" {
+		return "admin"
+	}
+
+	if len(actor) > maxActorLength {
+		actor = actor[:maxActorLength]
+	}
+
+	return actor
+}
+
+// auditIdentity returns the authenticated identity of the request, or an empty string if the request is not
+// authenticated
+func auditIdentity(c *gin.Context) string {
+	return c.GetString("identity")
+}
+
+// auditTenant returns a copy of the tenant whose secrets are replaced by their fingerprints
+func auditTenant(tenant *Tenant) *Tenant {
+	if tenant == nil || tenant.Spec == nil {
+		return tenant
+	}
+
+	spec := *tenant.Spec
+	if spec.Secret != "" {
+		spec.Secret = SecretFingerprint(spec.Secret)
+	}
+
+	if spec.APIKey != "" {
+		spec.APIKey = SecretFingerprint(spec.APIKey)
+	}
+
+	spec.SecondarySecrets = []SecondarySecret{}
+	for _, secret := range tenant.Spec.SecondarySecrets {
+		spec.SecondarySecrets = append(spec.SecondarySecrets, SecondarySecret{
+			Secret:    SecretFingerprint(secret.Secret),
+			ExpiresAt: secret.ExpiresAt,
+		})
+	}
+
+	spec.Webhooks = []*TenantWebhook{}
+	for _, webhook := range tenant.Spec.Webhooks {
+		if webhook == nil {
+			continue
+		}
+
+		audited := *webhook
+		if audited.Secret != "" {
+			audited.Secret = SecretFingerprint(audited.Secret)
+		}
+
+		spec.Webhooks = append(spec.Webhooks, &audited)
+	}
+
+	audited := *tenant
+	audited.Spec = &spec
+	return &audited
+}
+
+// auditInstances returns the instances secrets fingerprints indexed by instance url
+func auditInstances(instances map[string]string) map[string]string {
+	fingerprints := map[string]string{}
+	for url, secret := range instances {
+		fingerprints[url] = SecretFingerprint(secret)
+	}
+
+	return fingerprints
+}
+
+func instancesMap(instances []api.BigBlueButtonInstance) map[string]string {
+	m := map[string]string{}
+	for _, instance := range instances {
+		m[instance.URL] = instance.Secret
+	}
+
+	return m
+}
+
+// audit records an administrative change. A failure is logged but does not fail the request as the change is
+// already applied
+func (a *Admin) audit(c *gin.Context, action string, resource string, before interface{}, after interface{}) {
+	record := &AuditRecord{
+		ID:       uuid.New().String(),
+		Time:     time.Now().UTC(),
+		Actor:    auditActor(c),
+		Identity: auditIdentity(c),
+		Action:   action,
+		Resource: resource,
+		Before:   before,
+		After:    after,
+	}
+
+	if c.Request != nil {
+		record.SourceIP = c.ClientIP()
+	}
+
+	if err := a.AuditManager.Record(record); err != nil {
+		getLogger(c).Dup().WithField("audit_action", action).Errorln("failed to record audit record", err)
+	}
+}
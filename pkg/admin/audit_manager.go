@@ -0,0 +1,129 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/go-redis/redis/v8"
+)
+
+// AuditKey is the key of the list storing the audit records, most recent first
+const AuditKey = "audit"
+
+// AuditManager stores the administrative changes audit records
+type AuditManager interface {
+	// Record stores the audit record and exports it to the audit sinks
+	Record(record *AuditRecord) error
+	// List returns a page of the audit records, most recent first, and the total number of records
+	List(offset int64, limit int64) ([]AuditRecord, int64, error)
+}
+
+// AuditSink exports the audit records out of the audit store
+type AuditSink interface {
+	Export(record *AuditRecord) error
+}
+
+// RedisAuditManager is the redis implementation of AuditManager
+type RedisAuditManager struct {
+	RDB        *redis.Client
+	MaxRecords int64
+	Sinks      []AuditSink
+}
+
+// NewAuditManager initialize a new audit manager from the audit configuration
+func NewAuditManager(redis redis.Client, conf *config.AuditConfig) AuditManager {
+	sinks := []AuditSink{}
+	if conf.File != "" {
+		sinks = append(sinks, &FileAuditSink{Path: conf.File})
+	}
+
+	return &RedisAuditManager{
+		RDB:        &redis,
+		MaxRecords: conf.MaxRecords,
+		Sinks:      sinks,
+	}
+}
+
+// Record stores the audit record and exports it to the audit sinks. The oldest records are removed once the maximum
+// number of records is reached
+func (m *RedisAuditManager) Record(record *AuditRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %s", err)
+	}
+
+	ctx := context.Background()
+	pipe := m.RDB.TxPipeline()
+	pipe.LPush(ctx, AuditKey, value)
+	if m.MaxRecords > 0 {
+		pipe.LTrim(ctx, AuditKey, 0, m.MaxRecords-1)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store audit record: %s", err)
+	}
+
+	for _, sink := range m.Sinks {
+		if err := sink.Export(record); err != nil {
+			return fmt.Errorf("failed to export audit record: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// List returns a page of the audit records, most recent first, and the total number of records
+func (m *RedisAuditManager) List(offset int64, limit int64) ([]AuditRecord, int64, error) {
+	ctx := context.Background()
+	total, err := m.RDB.LLen(ctx, AuditKey).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit records: %s", err)
+	}
+
+	values, err := m.RDB.LRange(ctx, AuditKey, offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit records: %s", err)
+	}
+
+	records := []AuditRecord{}
+	for _, value := range values {
+		record := AuditRecord{}
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil, 0, fmt.Errorf("failed to unmarshal audit record: %s", err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, total, nil
+}
+
+// FileAuditSink appends the audit records to a file, one JSON record per line
+type FileAuditSink struct {
+	Path  string
+	mutex sync.Mutex
+}
+
+// Export appends the audit record to the file
+func (s *FileAuditSink) Export(record *AuditRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	file, err := os.OpenFile(s.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+	_, err = file.Write(append(value, '\n'))
+	return err
+}
@@ -0,0 +1,22 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+// AuditManagerMock is a mock implementation of the AuditManager interface
+type AuditManagerMock struct{}
+
+var (
+	// RecordAuditManagerMockFunc is the function that will be called when the mock audit manager is used
+	RecordAuditManagerMockFunc func(record *AuditRecord) error
+	// ListAuditManagerMockFunc is the function that will be called when the mock audit manager is used
+	ListAuditManagerMockFunc func(offset int64, limit int64) ([]AuditRecord, int64, error)
+)
+
+// Record is a mock implementation that stores an audit record
+func (m *AuditManagerMock) Record(record *AuditRecord) error {
+	return RecordAuditManagerMockFunc(record)
+}
+
+// List is a mock implementation that returns a page of audit records
+func (m *AuditManagerMock) List(offset int64, limit int64) ([]AuditRecord, int64, error) {
+	return ListAuditManagerMockFunc(offset, limit)
+}
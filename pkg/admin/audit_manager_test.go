@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisAuditManager(t *testing.T) {
+	mr := miniredis.RunT(t)
+	file := filepath.Join(t.TempDir(), "audit.log")
+	manager := NewAuditManager(*redis.NewClient(&redis.Options{Addr: mr.Addr()}), &config.AuditConfig{
+		MaxRecords: 3,
+		File:       file,
+	})
+
+	for i := 0; i < 4; i++ {
+		assert.Nil(t, manager.Record(&AuditRecord{ID: fmt.Sprint(i), Action: AuditCreateTenant}))
+	}
+
+	t.Run("records should be listed most recent first up to the maximum number of records", func(t *testing.T) {
+		records, total, err := manager.List(0, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, 2, len(records))
+		assert.Equal(t, "3", records[0].ID)
+		assert.Equal(t, "2", records[1].ID)
+
+		records, _, err = manager.List(2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, "1", records[0].ID)
+	})
+
+	t.Run("all records should be exported to the file sink", func(t *testing.T) {
+		f, err := os.Open(file)
+		assert.Nil(t, err)
+		defer f.Close()
+
+		ids := []string{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			record := AuditRecord{}
+			assert.Nil(t, json.Unmarshal(scanner.Bytes(), &record))
+			ids = append(ids, record.ID)
+		}
+
+		assert.Equal(t, []string{"0", "1", "2", "3"}, ids)
+	})
+
+	t.Run("a redis error should be returned", func(t *testing.T) {
+		mr.SetError("redis error")
+		defer mr.SetError("")
+		assert.NotNil(t, manager.Record(&AuditRecord{}))
+		_, _, err := manager.List(0, 1)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditTenant(t *testing.T) {
+	assert.Nil(t, auditTenant(nil))
+
+	expiresAt := time.Now()
+	tenant := &Tenant{Kind: "Tenant", Spec: &TenantSpec{
+		Host:             "localhost",
+		Secret:           "secret",
+		APIKey:           "api_key",
+		SecondarySecrets: []SecondarySecret{{Secret: "previous", ExpiresAt: expiresAt}},
+		Webhooks:         []*TenantWebhook{{URL: "https://example.com/hook", Secret: "webhook"}},
+	}}
+
+	audited := auditTenant(tenant)
+	assert.Equal(t, "localhost", audited.Spec.Host)
+	assert.Equal(t, SecretFingerprint("secret"), audited.Spec.Secret)
+	assert.Equal(t, SecretFingerprint("api_key"), audited.Spec.APIKey)
+	assert.Equal(t, SecondarySecret{Secret: SecretFingerprint("previous"), ExpiresAt: expiresAt}, audited.Spec.SecondarySecrets[0])
+	assert.Equal(t, "secret", tenant.Spec.Secret)
+	assert.Equal(t, "previous", tenant.Spec.SecondarySecrets[0].Secret)
+	assert.Equal(t, SecretFingerprint("webhook"), audited.Spec.Webhooks[0].Secret)
+	assert.Equal(t, "webhook", tenant.Spec.Webhooks[0].Secret)
+}
+
+func TestAuditActor(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.Equal(t, "admin", auditActor(c))
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+	assert.Equal(t, "admin", auditActor(c))
+
+	c.Request.Header.Set(ActorHeader, " operator ")
+	assert.Equal(t, "operator", auditActor(c))
+
+	setTenant(c, &Tenant{Spec: &TenantSpec{Host: "localhost"}})
+	assert.Equal(t, "tenant:localhost", auditActor(c))
+}
@@ -2,29 +2,90 @@
 package admin
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+
 	"github.com/gin-gonic/gin"
-	log "github.com/sirupsen/logrus"
 )
 
 // APIKeyValidation check that the request contains an api key provided by Authorization header
 func (a *Admin) APIKeyValidation(c *gin.Context) {
 	auth := c.Request.Header.Get("Authorization")
 	auth = strings.TrimSpace(auth)
-	logger := log.WithField("auth", auth)
+	logger := getLogger(c).Dup().WithField("auth", auth)
 	if auth == "" {
 		logger.Warn("auth key can't be an empty string")
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	if auth != a.Config.Admin.APIKey {
+	if !APIKeyMatches(auth, a.Config.Admin.APIKey) {
 		logger.Error("auth key does not match the configured admin key")
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
+	setIdentity(c, "admin-key:"+SecretFingerprint(auth))
 	c.Next()
 }
+
+// TenantAPIKeyValidation check that the request contains the requesting tenant api key provided by Authorization header.
+// The tenant is resolved from the request hostname and stored in the request context.
+func (a *Admin) TenantAPIKeyValidation(c *gin.Context) {
+	auth := strings.TrimSpace(c.Request.Header.Get("Authorization"))
+	hostname := utils.GetHost(c)
+	logger := getLogger(c).AddField("tenant", hostname)
+	if auth == "" {
+		logger.Warn("tenant auth key can't be an empty string")
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	tenant, err := a.TenantManager.ResolveTenant(hostname)
+	if err != nil {
+		logger.Error("failed to retrieve tenant: ", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if tenant == nil || tenant.Spec.APIKey == "" || !APIKeyMatches(auth, tenant.Spec.APIKey) {
+		logger.Error("auth key does not match the tenant api key")
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	setTenant(c, tenant)
+	setIdentity(c, "tenant-key:"+SecretFingerprint(auth))
+	c.Next()
+}
+
+// APIKeyMatches tells if the provided api key matches the expected one. The keys are compared in constant time so the
+// response time does not leak the expected key
+func APIKeyMatches(provided string, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
+
+func setTenant(c *gin.Context, tenant *Tenant) {
+	c.Set("tenant", tenant)
+}
+
+// setIdentity stores the authenticated identity of the request: the fingerprint of the api key used to authenticate
+func setIdentity(c *gin.Context, identity string) {
+	c.Set("identity", identity)
+}
+
+func getTenant(c *gin.Context) *Tenant {
+	return c.MustGet("tenant").(*Tenant)
+}
+
+// setLogger stores the request logger in the request context. The logged action is the requested admin route
+var setLogger = utils.RequestLoggerMiddleware(func(c *gin.Context) string {
+	return c.Request.Method + " " + c.FullPath()
+})
+
+func getLogger(c *gin.Context) *utils.RequestLogger {
+	return utils.GetRequestLogger(c)
+}
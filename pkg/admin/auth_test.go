@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -18,7 +19,7 @@ import (
 func TestApiKeyValidation(t *testing.T) {
 	var w *httptest.ResponseRecorder
 	var c *gin.Context
-	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &balancer.Mock{}, &config.Config{Admin: config.AdminConfig{APIKey: test.DefaultAPIKey()}})
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{Admin: config.AdminConfig{APIKey: test.DefaultAPIKey()}})
 	tests := []test.Test{
 		{
 			Name: "An empty api key should returns an unauthorized error",
@@ -36,6 +37,17 @@ func TestApiKeyValidation(t *testing.T) {
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusUnauthorized, w.Code)
+				assert.Equal(t, "", auditIdentity(c))
+			},
+		},
+		{
+			Name: "A valid api key should set the admin key identity in the request context",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", test.DefaultAPIKey())
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "admin-key:"+SecretFingerprint(test.DefaultAPIKey()), auditIdentity(c))
 			},
 		},
 	}
@@ -50,3 +62,103 @@ func TestApiKeyValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIKeyMatches(t *testing.T) {
+	assert.True(t, APIKeyMatches("api_key", "api_key"))
+	assert.False(t, APIKeyMatches("api_ke", "api_key"))
+	assert.False(t, APIKeyMatches("", "api_key"))
+}
+
+func TestTenantAPIKeyValidation(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	tests := []test.Test{
+		{
+			Name: "An empty api key should returns an unauthorized error",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", "")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			Name: "An error returned by tenant manager should returns an internal server error",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", "tenant_key")
+				request.SetRequestHost(c, "localhost")
+				ResolveTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, errors.New("tenant manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "An unknown tenant should returns an unauthorized error",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", "tenant_key")
+				request.SetRequestHost(c, "localhost")
+				ResolveTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return nil, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			Name: "A tenant without api key should returns an unauthorized error",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", "tenant_key")
+				request.SetRequestHost(c, "localhost")
+				ResolveTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return &Tenant{Spec: &TenantSpec{Host: "localhost"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			Name: "An invalid api key should returns an unauthorized error",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", "invalid_key")
+				request.SetRequestHost(c, "localhost")
+				ResolveTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return &Tenant{Spec: &TenantSpec{Host: "localhost", APIKey: "tenant_key"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			Name: "A valid api key should set the tenant in the request context",
+			Mock: func() {
+				request.SetRequestHeader(c, "Authorization", "tenant_key")
+				request.SetRequestHost(c, "localhost")
+				ResolveTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+					return &Tenant{Spec: &TenantSpec{Host: "localhost", APIKey: "tenant_key"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "localhost", getTenant(c).Spec.Host)
+				assert.Equal(t, "tenant-key:"+SecretFingerprint("tenant_key"), auditIdentity(c))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			test.Mock()
+			admin.TenantAPIKeyValidation(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
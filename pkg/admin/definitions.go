@@ -1,6 +1,8 @@
 // Package admin manages the bigblueswarm admin part
 package admin
 
+import "time"
+
 // InstanceList represent the kind InstanceList configuration struct file
 type InstanceList struct {
 	Kind      string            `yaml:"kind" json:"kind"`
@@ -9,10 +11,74 @@ type InstanceList struct {
 
 // TenantSpec represents the tenant spec configuration
 type TenantSpec struct {
-	Host         string `yaml:"host,omitempty" json:"host,omitempty"`
-	Secret       string `yaml:"secret,omitempty" json:"secret,omitempty"`
-	MeetingsPool *int64 `yaml:"meeting_pool,omitempty" json:"meeting_pool,omitempty"`
-	UserPool     *int64 `yaml:"user_pool,omitempty" json:"user_pool,omitempty"`
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+	// Aliases are additional hosts serving the tenant. An alias starting with `*.` is a wildcard matching any subdomain
+	Aliases      []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	Secret       string   `yaml:"secret,omitempty" json:"secret,omitempty"`
+	MeetingsPool *int64   `yaml:"meeting_pool,omitempty" json:"meeting_pool,omitempty"`
+	UserPool     *int64   `yaml:"user_pool,omitempty" json:"user_pool,omitempty"`
+	APIKey       string   `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	// SecondarySecrets are secrets still accepted until their expiration date. They allow rotating a secret without breaking integrations.
+	SecondarySecrets []SecondarySecret `yaml:"secondary_secrets,omitempty" json:"secondary_secrets,omitempty"`
+	// CreateParameters are the create API parameters applied to every meeting created by the tenant
+	CreateParameters *CreateParameters `yaml:"create_parameters,omitempty" json:"create_parameters,omitempty"`
+	// Quotas are the tenant consumption limits over a period
+	Quotas *TenantQuotas `yaml:"quotas,omitempty" json:"quotas,omitempty"`
+	// MaxMeetingDuration is the maximum meeting duration in minutes. Longer meetings are ended by BigBlueSwarm
+	MaxMeetingDuration *int64 `yaml:"max_meeting_duration,omitempty" json:"max_meeting_duration,omitempty"`
+	// IdleTimeout is the duration in minutes after which a meeting without participants is ended by BigBlueSwarm
+	IdleTimeout *int64 `yaml:"idle_timeout,omitempty" json:"idle_timeout,omitempty"`
+	// RateLimits are the BigBlueButton API rate limits indexed by action. The `*` entry applies to actions without limit
+	RateLimits map[string]*RateLimit `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
+	// MeetingIDNamespace is the way meeting identifiers are namespaced on the instances: `prefix` or `hash`.
+	// Meeting identifiers are not namespaced if empty
+	MeetingIDNamespace string `yaml:"meeting_id_namespace,omitempty" json:"meeting_id_namespace,omitempty"`
+	// Webhooks are the endpoints receiving the tenant events
+	Webhooks []*TenantWebhook `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+}
+
+// TenantWebhook represents an endpoint receiving the tenant events. Events are signed with the webhook secret, or the
+// tenant primary secret if empty
+type TenantWebhook struct {
+	URL    string `yaml:"url" json:"url"`
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+	// Events are the delivered event types. A type ending with `.*` matches all the types of a category, `meeting.*`
+	// for instance. All the tenant events are delivered if empty
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"`
+}
+
+// RateLimit represents a token bucket rate limit. The bucket is refilled with Requests tokens every Period and holds
+// at most Burst tokens
+type RateLimit struct {
+	Requests int64 `yaml:"requests" json:"requests"`
+	// Period is the refill period. Default is 1s
+	Period string `yaml:"period,omitempty" json:"period,omitempty"`
+	// Burst is the bucket capacity. Default is the requests count
+	Burst int64 `yaml:"burst,omitempty" json:"burst,omitempty"`
+}
+
+// TenantQuotas represents the tenant time-based quotas. The consumption is reset at the beginning of each period.
+type TenantQuotas struct {
+	// Period is the quota period: day, week or month. Default is month
+	Period             string `yaml:"period,omitempty" json:"period,omitempty"`
+	Meetings           *int64 `yaml:"meetings,omitempty" json:"meetings,omitempty"`
+	ParticipantMinutes *int64 `yaml:"participant_minutes,omitempty" json:"participant_minutes,omitempty"`
+}
+
+// CreateParameters represents the tenant create API parameters policy. Parameters use the BigBlueButton create API names.
+type CreateParameters struct {
+	// Defaults are applied only if the caller does not provide the parameter
+	Defaults map[string]string `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	// Enforced are applied regardless of the caller parameters
+	Enforced map[string]string `yaml:"enforced,omitempty" json:"enforced,omitempty"`
+	// Forbidden are removed from the caller parameters
+	Forbidden []string `yaml:"forbidden,omitempty" json:"forbidden,omitempty"`
+}
+
+// SecondarySecret represents a tenant secret accepted until its expiration date
+type SecondarySecret struct {
+	Secret    string    `yaml:"secret" json:"secret"`
+	ExpiresAt time.Time `yaml:"expires_at" json:"expires_at"`
 }
 
 // Tenant represents the kind Tenant configuration struct file
@@ -31,6 +97,160 @@ type TenantList struct {
 
 // TenantListObject represents a Tenant in a TenantList
 type TenantListObject struct {
-	Hostname      string `json:"hostname"`
-	InstanceCount int    `json:"instance_count"`
+	Hostname      string   `json:"hostname"`
+	Aliases       []string `json:"aliases,omitempty"`
+	InstanceCount int      `json:"instance_count"`
+}
+
+// TenantMeeting represents a meeting exposed by the tenant API
+type TenantMeeting struct {
+	MeetingID        string `json:"meeting_id"`
+	MeetingName      string `json:"meeting_name"`
+	Instance         string `json:"instance"`
+	CreateTime       string `json:"create_time"`
+	Running          bool   `json:"running"`
+	Recording        bool   `json:"recording"`
+	ParticipantCount int    `json:"participant_count"`
+	ModeratorCount   int    `json:"moderator_count"`
+}
+
+// TenantRecording represents a recording exposed by the tenant API
+type TenantRecording struct {
+	RecordID     string `json:"record_id"`
+	MeetingID    string `json:"meeting_id"`
+	Name         string `json:"name"`
+	Instance     string `json:"instance"`
+	Published    bool   `json:"published"`
+	State        string `json:"state"`
+	StartTime    int    `json:"start_time"`
+	EndTime      int    `json:"end_time"`
+	Participants int    `json:"participants"`
+}
+
+// TenantUsage represents the current tenant consumption
+type TenantUsage struct {
+	Hostname     string      `json:"hostname"`
+	Meetings     int64       `json:"meetings"`
+	Participants int64       `json:"participants"`
+	MeetingsPool *int64      `json:"meeting_pool,omitempty"`
+	UserPool     *int64      `json:"user_pool,omitempty"`
+	Quota        *QuotaUsage `json:"quota,omitempty"`
+}
+
+// QuotaUsage represents the tenant consumption over the current quota period
+type QuotaUsage struct {
+	Period                  string    `json:"period"`
+	Start                   time.Time `json:"start"`
+	End                     time.Time `json:"end"`
+	Meetings                int64     `json:"meetings"`
+	ParticipantMinutes      int64     `json:"participant_minutes"`
+	MeetingsQuota           *int64    `json:"meetings_quota,omitempty"`
+	ParticipantMinutesQuota *int64    `json:"participant_minutes_quota,omitempty"`
+}
+
+// TenantSecret represents the response of a tenant secret rotation
+type TenantSecret struct {
+	Hostname    string `json:"hostname"`
+	Secret      string `json:"secret"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// SecretRotation represents a secret rotation request
+type SecretRotation struct {
+	// Secret is the new primary secret. A random secret is generated if empty
+	Secret string `json:"secret,omitempty"`
+	// Overlap is the duration during which the previous primary secret is still accepted
+	Overlap string `json:"overlap,omitempty"`
+}
+
+// SecretStatus represents a tenant secret and its usage. The secret value is never exposed, only its fingerprint
+type SecretStatus struct {
+	Fingerprint string     `json:"fingerprint"`
+	Primary     bool       `json:"primary"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Usage       int64      `json:"usage"`
+	LastUsed    *time.Time `json:"last_used,omitempty"`
+}
+
+// SecretUsage represents the usage of a secret identified by its fingerprint
+type SecretUsage struct {
+	Count    int64
+	LastUsed *time.Time
+}
+
+// AuditRecord represents an administrative change. Before and After are the changed resource states, secrets being
+// replaced by their fingerprints. Actor is the caller declared actor while Identity is the authenticated identity
+type AuditRecord struct {
+	ID       string      `json:"id"`
+	Time     time.Time   `json:"time"`
+	Actor    string      `json:"actor"`
+	Identity string      `json:"identity"`
+	SourceIP string      `json:"source_ip"`
+	Action   string      `json:"action"`
+	Resource string      `json:"resource"`
+	Before   interface{} `json:"before"`
+	After    interface{} `json:"after"`
+}
+
+// AuditList represents a page of the audit records, most recent first
+type AuditList struct {
+	Kind    string        `json:"kind"`
+	Total   int64         `json:"total"`
+	Offset  int64         `json:"offset"`
+	Limit   int64         `json:"limit"`
+	Records []AuditRecord `json:"records"`
+}
+
+// MeetingRecord represents a meeting history record. MeetingID is the meeting identifier on the instance and EndTime
+// is nil while the meeting is running
+type MeetingRecord struct {
+	ID               string     `json:"id"`
+	MeetingID        string     `json:"meeting_id"`
+	Tenant           string     `json:"tenant"`
+	Instance         string     `json:"instance"`
+	StartTime        time.Time  `json:"start_time"`
+	EndTime          *time.Time `json:"end_time,omitempty"`
+	PeakParticipants int64      `json:"peak_participants"`
+	Recording        bool       `json:"recording"`
+}
+
+// MeetingHistoryFilter selects the meetings history records started in the [From, To] range. All tenants are selected
+// if Tenant is empty
+type MeetingHistoryFilter struct {
+	Tenant string
+	From   time.Time
+	To     time.Time
+}
+
+// MeetingHistory represents the meetings history records matching a filter, ordered by start time
+type MeetingHistory struct {
+	Kind     string          `json:"kind"`
+	Tenant   string          `json:"tenant,omitempty"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Meetings []MeetingRecord `json:"meetings"`
+}
+
+// WebhookDelivery represents the delivery of an event to a tenant webhook. Event is the JSON encoded event
+type WebhookDelivery struct {
+	ID            string     `json:"id"`
+	Tenant        string     `json:"tenant"`
+	URL           string     `json:"url"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Event         string     `json:"event"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// WebhookDeliveryList represents the tenant webhook deliveries, most recent first
+type WebhookDeliveryList struct {
+	Kind       string            `json:"kind"`
+	Tenant     string            `json:"tenant"`
+	Status     string            `json:"status,omitempty"`
+	Deliveries []WebhookDelivery `json:"deliveries"`
 }
@@ -0,0 +1,163 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultHistoryRange is the meetings history range returned when the `from` query parameter is missing
+const DefaultHistoryRange = 30 * 24 * time.Hour
+
+const dateLayout = "2006-01-02"
+
+var historyCSVHeader = []string{
+	"id",
+	"meeting_id",
+	"tenant",
+	"instance",
+	"start_time",
+	"end_time",
+	"duration_seconds",
+	"peak_participants",
+	"recording",
+}
+
+// queryTime parses a RFC3339 time or a date query parameter. A date ending a range includes the whole day
+func queryTime(c *gin.Context, name string, defaultValue time.Time, end bool) (time.Time, error) {
+	value, exists := c.GetQuery(name)
+	if !exists || value == "" {
+		return defaultValue, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter %s, expecting a RFC3339 time or a YYYY-MM-DD date", name, value)
+	}
+
+	if end {
+		return parsed.Add(24*time.Hour - time.Millisecond), nil
+	}
+
+	return parsed, nil
+}
+
+func historyFilter(c *gin.Context) (*MeetingHistoryFilter, error) {
+	to, err := queryTime(c, "to", time.Now().UTC(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := queryTime(c, "from", to.Add(-DefaultHistoryRange), false)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.After(to) {
+		return nil, fmt.Errorf("from parameter %s is after to parameter %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	return &MeetingHistoryFilter{
+		Tenant: c.Query("tenant"),
+		From:   from,
+		To:     to,
+	}, nil
+}
+
+func meetingRecordCSV(record MeetingRecord) []string {
+	end := ""
+	duration := ""
+	if record.EndTime != nil {
+		end = record.EndTime.Format(time.RFC3339)
+		duration = strconv.FormatInt(int64(record.EndTime.Sub(record.StartTime).Seconds()), 10)
+	}
+
+	return []string{
+		record.ID,
+		record.MeetingID,
+		record.Tenant,
+		record.Instance,
+		record.StartTime.Format(time.RFC3339),
+		end,
+		duration,
+		strconv.FormatInt(record.PeakParticipants, 10),
+		strconv.FormatBool(record.Recording),
+	}
+}
+
+func meetingHistoryCSV(records []MeetingRecord) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write(historyCSVHeader); err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if err := writer.Write(meetingRecordCSV(record)); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// ListMeetingsHistory returns the meetings history records started in the `from` and `to` query parameters range, 30
+// days until now by default, optionally filtered by the `tenant` query parameter. The records are exported as CSV if
+// the `format` query parameter is `csv`
+func (a *Admin) ListMeetingsHistory(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		m := fmt.Sprintf("invalid format parameter %s, expecting json or csv", format)
+		getLogger(c).Warn(m)
+		c.String(http.StatusBadRequest, m)
+		return
+	}
+
+	filter, err := historyFilter(c)
+	if err != nil {
+		getLogger(c).Warn(err)
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := a.HistoryManager.List(filter)
+	if err != nil {
+		e := fmt.Errorf("failed to list meetings history: %s", err)
+		getLogger(c).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, &MeetingHistory{
+			Kind:     "MeetingHistory",
+			Tenant:   filter.Tenant,
+			From:     filter.From,
+			To:       filter.To,
+			Meetings: records,
+		})
+		return
+	}
+
+	data, err := meetingHistoryCSV(records)
+	if err != nil {
+		e := fmt.Errorf("failed to export meetings history: %s", err)
+		getLogger(c).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="meetings_history.csv"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
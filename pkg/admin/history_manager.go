@@ -0,0 +1,286 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// HistoryIndexKey is the key of the sorted set indexing all the meetings history records by start time
+const HistoryIndexKey = "history:meetings"
+
+const historyMeetingPrefix = "history:meeting:"
+const historyTenantPrefix = "history:tenant:"
+const historyRunningPrefix = "history:running:"
+
+// MeetingHistoryManager stores the meetings history records
+type MeetingHistoryManager interface {
+	// Record creates the meeting history record or updates the running meeting record with a new snapshot. The peak
+	// participants is the highest participants count recorded and the recording flag is set once a snapshot is recording
+	Record(meeting *MeetingRecord) error
+	// End sets the end time of the tenant running meeting record
+	End(tenant string, meetingID string, end time.Time) error
+	// Reconcile ends the tenant running meetings records that are no longer running on the instances and returns their
+	// meeting identifiers. Records started during the grace period are kept as their meeting may not be listed yet by
+	// their instance
+	Reconcile(tenant string, running []string, now time.Time, grace time.Duration) ([]string, error)
+	// List returns the meetings history records matching the filter, ordered by start time
+	List(filter *MeetingHistoryFilter) ([]MeetingRecord, error)
+	// Purge removes the meetings history records started before the given time and returns the number of removed records
+	Purge(before time.Time) (int64, error)
+}
+
+// RedisMeetingHistoryManager is the redis implementation of MeetingHistoryManager. Records are stored in hashes indexed
+// by start time in a global and a tenant sorted sets. Running meetings records are indexed by meeting identifier so
+// end calls can find the current record of a meeting identifier reused across meetings.
+//
+// The history scripts access records keys read while they run (previous, ended and purged records) which can't be
+// declared in KEYS: they require a single Redis node and are not compatible with Redis Cluster
+type RedisMeetingHistoryManager struct {
+	RDB *redis.Client
+}
+
+// NewMeetingHistoryManager creates a new MeetingHistoryManager
+func NewMeetingHistoryManager(redis redis.Client) MeetingHistoryManager {
+	return &RedisMeetingHistoryManager{
+		RDB: &redis,
+	}
+}
+
+// HistoryMeetingKey returns the key of a meeting history record
+func HistoryMeetingKey(id string) string {
+	return historyMeetingPrefix + id
+}
+
+// HistoryTenantKey returns the key of the sorted set indexing the tenant meetings history records by start time
+func HistoryTenantKey(tenant string) string {
+	return historyTenantPrefix + tenant
+}
+
+// HistoryRunningKey returns the key of the hash indexing the tenant running meetings records by meeting identifier
+func HistoryRunningKey(tenant string) string {
+	return historyRunningPrefix + tenant
+}
+
+var recordMeetingScript = redis.NewScript(`
+if redis.call('HSETNX', KEYS[1], 'start_time', ARGV[5]) == 1 then
+	local previous = redis.call('HGET', KEYS[4], ARGV[2])
+	if previous and previous ~= ARGV[1] then
+		redis.call('HSETNX', ARGV[8] .. previous, 'end_time', ARGV[5])
+	end
+
+	redis.call('HSET', KEYS[1], 'id', ARGV[1], 'meeting_id', ARGV[2], 'tenant', ARGV[3], 'instance', ARGV[4], 'peak_participants', 0)
+	redis.call('ZADD', KEYS[2], ARGV[5], ARGV[1])
+	redis.call('ZADD', KEYS[3], ARGV[5], ARGV[1])
+	redis.call('HSET', KEYS[4], ARGV[2], ARGV[1])
+end
+
+if tonumber(ARGV[6]) > tonumber(redis.call('HGET', KEYS[1], 'peak_participants') or '0') then
+	redis.call('HSET', KEYS[1], 'peak_participants', ARGV[6])
+end
+
+if ARGV[7] == '1' then
+	redis.call('HSET', KEYS[1], 'recording', 1)
+end
+
+return 1
+`)
+
+var endMeetingScript = redis.NewScript(`
+local id = redis.call('HGET', KEYS[1], ARGV[1])
+if not id then
+	return 0
+end
+
+redis.call('HSETNX', ARGV[3] .. id, 'end_time', ARGV[2])
+redis.call('HDEL', KEYS[1], ARGV[1])
+return 1
+`)
+
+var reconcileHistoryScript = redis.NewScript(`
+local running = {}
+for i = 4, #ARGV do
+	running[ARGV[i]] = true
+end
+
+local ended = {}
+local meetings = redis.call('HGETALL', KEYS[1])
+for i = 1, #meetings, 2 do
+	local meeting, id = meetings[i], meetings[i + 1]
+	if not running[meeting] then
+		local start = tonumber(redis.call('HGET', ARGV[3] .. id, 'start_time') or '0')
+		if start < tonumber(ARGV[2]) then
+			redis.call('HSETNX', ARGV[3] .. id, 'end_time', ARGV[1])
+			redis.call('HDEL', KEYS[1], meeting)
+			table.insert(ended, meeting)
+		end
+	end
+end
+
+return ended
+`)
+
+var purgeHistoryScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[1])
+for _, id in ipairs(ids) do
+	local fields = redis.call('HMGET', ARGV[2] .. id, 'tenant', 'meeting_id')
+	if fields[1] then
+		redis.call('ZREM', ARGV[3] .. fields[1], id)
+		if fields[2] and redis.call('HGET', ARGV[4] .. fields[1], fields[2]) == id then
+			redis.call('HDEL', ARGV[4] .. fields[1], fields[2])
+		end
+	end
+
+	redis.call('DEL', ARGV[2] .. id)
+	redis.call('ZREM', KEYS[1], id)
+end
+
+return #ids
+`)
+
+func boolArg(value bool) string {
+	if value {
+		return "1"
+	}
+
+	return "0"
+}
+
+// Record creates the meeting history record or updates the running meeting record with a new snapshot. A new record of
+// a meeting identifier ends the previous record of the same meeting identifier
+func (m *RedisMeetingHistoryManager) Record(meeting *MeetingRecord) error {
+	keys := []string{
+		HistoryMeetingKey(meeting.ID),
+		HistoryIndexKey,
+		HistoryTenantKey(meeting.Tenant),
+		HistoryRunningKey(meeting.Tenant),
+	}
+
+	args := []interface{}{
+		meeting.ID,
+		meeting.MeetingID,
+		meeting.Tenant,
+		meeting.Instance,
+		meeting.StartTime.UnixMilli(),
+		meeting.PeakParticipants,
+		boolArg(meeting.Recording),
+		historyMeetingPrefix,
+	}
+
+	if err := recordMeetingScript.Run(context.Background(), m.RDB, keys, args...).Err(); err != nil {
+		return fmt.Errorf("failed to record meeting %s: %s", meeting.ID, err)
+	}
+
+	return nil
+}
+
+// End sets the end time of the tenant running meeting record. Nothing is done if the meeting does not have a running record
+func (m *RedisMeetingHistoryManager) End(tenant string, meetingID string, end time.Time) error {
+	keys := []string{HistoryRunningKey(tenant)}
+	err := endMeetingScript.Run(context.Background(), m.RDB, keys, meetingID, end.UnixMilli(), historyMeetingPrefix).Err()
+	if err != nil {
+		return fmt.Errorf("failed to end meeting %s record: %s", meetingID, err)
+	}
+
+	return nil
+}
+
+// Reconcile ends the tenant running meetings records that are no longer running on the instances and returns their
+// meeting identifiers. Records started during the grace period are kept as their meeting may not be listed yet by
+// their instance
+func (m *RedisMeetingHistoryManager) Reconcile(tenant string, running []string, now time.Time, grace time.Duration) ([]string, error) {
+	keys := []string{HistoryRunningKey(tenant)}
+	args := []interface{}{now.UnixMilli(), now.Add(-grace).UnixMilli(), historyMeetingPrefix}
+	for _, meetingID := range running {
+		args = append(args, meetingID)
+	}
+
+	ended, err := reconcileHistoryScript.Run(context.Background(), m.RDB, keys, args...).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile tenant %s meetings history: %s", tenant, err)
+	}
+
+	return ended, nil
+}
+
+// List returns the meetings history records matching the filter, ordered by start time
+func (m *RedisMeetingHistoryManager) List(filter *MeetingHistoryFilter) ([]MeetingRecord, error) {
+	ctx := context.Background()
+	index := HistoryIndexKey
+	if filter.Tenant != "" {
+		index = HistoryTenantKey(filter.Tenant)
+	}
+
+	ids, err := m.RDB.ZRangeByScore(ctx, index, &redis.ZRangeBy{
+		Min: strconv.FormatInt(filter.From.UnixMilli(), 10),
+		Max: strconv.FormatInt(filter.To.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list meetings history: %s", err)
+	}
+
+	records := []MeetingRecord{}
+	if len(ids) == 0 {
+		return records, nil
+	}
+
+	pipe := m.RDB.Pipeline()
+	cmds := []*redis.StringStringMapCmd{}
+	for _, id := range ids {
+		cmds = append(cmds, pipe.HGetAll(ctx, HistoryMeetingKey(id)))
+	}
+
+	if _, err := pipe.Exec(ctx); utils.ComputeErr(err) != nil {
+		return nil, fmt.Errorf("failed to retrieve meetings history records: %s", err)
+	}
+
+	for _, cmd := range cmds {
+		if fields := cmd.Val(); len(fields) > 0 {
+			records = append(records, meetingRecordFromFields(fields))
+		}
+	}
+
+	return records, nil
+}
+
+// Purge removes the meetings history records started before the given time and returns the number of removed records
+func (m *RedisMeetingHistoryManager) Purge(before time.Time) (int64, error) {
+	keys := []string{HistoryIndexKey}
+	args := []interface{}{before.UnixMilli(), historyMeetingPrefix, historyTenantPrefix, historyRunningPrefix}
+	count, err := purgeHistoryScript.Run(context.Background(), m.RDB, keys, args...).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge meetings history: %s", err)
+	}
+
+	return count, nil
+}
+
+func meetingRecordFromFields(fields map[string]string) MeetingRecord {
+	record := MeetingRecord{
+		ID:        fields["id"],
+		MeetingID: fields["meeting_id"],
+		Tenant:    fields["tenant"],
+		Instance:  fields["instance"],
+		Recording: fields["recording"] == "1",
+	}
+
+	if start, err := strconv.ParseInt(fields["start_time"], 10, 64); err == nil {
+		record.StartTime = time.UnixMilli(start).UTC()
+	}
+
+	if end, err := strconv.ParseInt(fields["end_time"], 10, 64); err == nil {
+		endTime := time.UnixMilli(end).UTC()
+		record.EndTime = &endTime
+	}
+
+	if peak, err := strconv.ParseInt(fields["peak_participants"], 10, 64); err == nil {
+		record.PeakParticipants = peak
+	}
+
+	return record
+}
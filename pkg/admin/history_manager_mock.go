@@ -0,0 +1,45 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import "time"
+
+// MeetingHistoryManagerMock is a mock implementation of the MeetingHistoryManager interface
+type MeetingHistoryManagerMock struct{}
+
+var (
+	// RecordMeetingHistoryManagerMockFunc is the function that will be called when the mock meeting history manager is used
+	RecordMeetingHistoryManagerMockFunc func(meeting *MeetingRecord) error
+	// EndMeetingHistoryManagerMockFunc is the function that will be called when the mock meeting history manager is used
+	EndMeetingHistoryManagerMockFunc func(tenant string, meetingID string, end time.Time) error
+	// ReconcileMeetingHistoryManagerMockFunc is the function that will be called when the mock meeting history manager is used
+	ReconcileMeetingHistoryManagerMockFunc func(tenant string, running []string, now time.Time, grace time.Duration) ([]string, error)
+	// ListMeetingHistoryManagerMockFunc is the function that will be called when the mock meeting history manager is used
+	ListMeetingHistoryManagerMockFunc func(filter *MeetingHistoryFilter) ([]MeetingRecord, error)
+	// PurgeMeetingHistoryManagerMockFunc is the function that will be called when the mock meeting history manager is used
+	PurgeMeetingHistoryManagerMockFunc func(before time.Time) (int64, error)
+)
+
+// Record is a mock implementation that records a meeting snapshot
+func (m *MeetingHistoryManagerMock) Record(meeting *MeetingRecord) error {
+	return RecordMeetingHistoryManagerMockFunc(meeting)
+}
+
+// End is a mock implementation that ends a meeting record
+func (m *MeetingHistoryManagerMock) End(tenant string, meetingID string, end time.Time) error {
+	return EndMeetingHistoryManagerMockFunc(tenant, meetingID, end)
+}
+
+// Reconcile is a mock implementation that ends the meetings records no longer running
+func (m *MeetingHistoryManagerMock) Reconcile(tenant string, running []string, now time.Time, grace time.Duration) ([]string, error) {
+	return ReconcileMeetingHistoryManagerMockFunc(tenant, running, now, grace)
+}
+
+// List is a mock implementation that returns the meetings history records matching the filter
+func (m *MeetingHistoryManagerMock) List(filter *MeetingHistoryFilter) ([]MeetingRecord, error) {
+	return ListMeetingHistoryManagerMockFunc(filter)
+}
+
+// Purge is a mock implementation that removes the old meetings history records
+func (m *MeetingHistoryManagerMock) Purge(before time.Time) (int64, error) {
+	return PurgeMeetingHistoryManagerMockFunc(before)
+}
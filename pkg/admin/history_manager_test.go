@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisMeetingHistoryManager(t *testing.T) {
+	mr := miniredis.RunT(t)
+	manager := NewMeetingHistoryManager(*redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	start := time.Date(2022, time.March, 17, 15, 0, 0, 0, time.UTC)
+	all := &MeetingHistoryFilter{From: start.Add(-time.Hour), To: start.Add(time.Hour)}
+
+	record := func(id string, meetingID string, tenant string, startTime time.Time, participants int64, recording bool) {
+		assert.Nil(t, manager.Record(&MeetingRecord{
+			ID:               id,
+			MeetingID:        meetingID,
+			Tenant:           tenant,
+			Instance:         "http://localhost/bigbluebutton",
+			StartTime:        startTime,
+			PeakParticipants: participants,
+			Recording:        recording,
+		}))
+	}
+
+	t.Run("snapshots should update the peak participants and the recording flag", func(t *testing.T) {
+		record("first-1", "first", "localhost", start, 0, false)
+		record("first-1", "first", "localhost", start, 5, true)
+		record("first-1", "first", "localhost", start, 3, false)
+
+		records, err := manager.List(all)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, "first", records[0].MeetingID)
+		assert.Equal(t, "localhost", records[0].Tenant)
+		assert.Equal(t, start, records[0].StartTime)
+		assert.Equal(t, int64(5), records[0].PeakParticipants)
+		assert.True(t, records[0].Recording)
+		assert.Nil(t, records[0].EndTime)
+	})
+
+	t.Run("end should set the end time of the running meeting record", func(t *testing.T) {
+		end := start.Add(10 * time.Minute)
+		assert.Nil(t, manager.End("localhost", "first", end))
+		assert.Nil(t, manager.End("localhost", "unknown", end))
+
+		records, _ := manager.List(all)
+		assert.Equal(t, end, *records[0].EndTime)
+		assert.False(t, mr.Exists(HistoryRunningKey("localhost")))
+	})
+
+	t.Run("a new record of a meeting identifier should end the previous record", func(t *testing.T) {
+		record("second-1", "second", "localhost", start.Add(time.Minute), 0, false)
+		record("second-2", "second", "localhost", start.Add(2*time.Minute), 0, false)
+
+		records, _ := manager.List(all)
+		assert.Equal(t, 3, len(records))
+		assert.Equal(t, "second-1", records[1].ID)
+		assert.Equal(t, start.Add(2*time.Minute), *records[1].EndTime)
+		assert.Nil(t, records[2].EndTime)
+	})
+
+	t.Run("reconcile should end the records no longer running out of the grace period", func(t *testing.T) {
+		record("third-1", "third", "localhost", start.Add(3*time.Minute), 0, false)
+		record("other-1", "other", "other.host", start.Add(4*time.Minute), 0, false)
+		now := start.Add(5 * time.Minute)
+		ended, err := manager.Reconcile("localhost", []string{}, now, 2*time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"second"}, ended)
+
+		records, _ := manager.List(&MeetingHistoryFilter{Tenant: "localhost", From: all.From, To: all.To})
+		assert.Equal(t, 4, len(records))
+		assert.Equal(t, now, *records[2].EndTime)
+		assert.Nil(t, records[3].EndTime)
+	})
+
+	t.Run("list should filter records by start time", func(t *testing.T) {
+		records, err := manager.List(&MeetingHistoryFilter{From: start.Add(4 * time.Minute), To: start.Add(time.Hour)})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, "other-1", records[0].ID)
+	})
+
+	t.Run("purge should remove the records started before the given time", func(t *testing.T) {
+		count, err := manager.Purge(start.Add(3 * time.Minute))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.False(t, mr.Exists(HistoryMeetingKey("first-1")))
+
+		records, _ := manager.List(all)
+		assert.Equal(t, 2, len(records))
+		assert.Equal(t, "third-1", records[0].ID)
+	})
+}
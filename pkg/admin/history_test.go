@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/test_utils/pkg/request"
+	"github.com/bigblueswarm/test_utils/pkg/test"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMeetingsHistory(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	start := time.Date(2022, time.March, 17, 15, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	records := []MeetingRecord{
+		{
+			ID:               "first-1",
+			MeetingID:        "first",
+			Tenant:           "localhost",
+			Instance:         "http://localhost/bigbluebutton",
+			StartTime:        start,
+			EndTime:          &end,
+			PeakParticipants: 4,
+			Recording:        true,
+		},
+		{
+			ID:        "second-1",
+			MeetingID: "second",
+			Tenant:    "localhost",
+			Instance:  "http://localhost/bigbluebutton",
+			StartTime: start,
+		},
+	}
+
+	tests := []test.Test{
+		{
+			Name: "an invalid format should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "format=xml")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "an invalid date should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "from=yesterday")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "a from date after the to date should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "from=2022-03-18&to=2022-03-17")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "an error returned by history manager should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				request.SetRequestParams(c, "")
+				ListMeetingHistoryManagerMockFunc = func(filter *MeetingHistoryFilter) ([]MeetingRecord, error) {
+					return nil, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should return the meetings history matching the filter",
+			Mock: func() {
+				request.SetRequestParams(c, "tenant=localhost&from=2022-03-17&to=2022-03-17")
+				ListMeetingHistoryManagerMockFunc = func(filter *MeetingHistoryFilter) ([]MeetingRecord, error) {
+					assert.Equal(t, "localhost", filter.Tenant)
+					assert.Equal(t, time.Date(2022, time.March, 17, 0, 0, 0, 0, time.UTC), filter.From)
+					assert.Equal(t, time.Date(2022, time.March, 17, 23, 59, 59, 999000000, time.UTC), filter.To)
+					return records, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				history := &MeetingHistory{}
+				json.Unmarshal(w.Body.Bytes(), history)
+				assert.Equal(t, "localhost", history.Tenant)
+				assert.Equal(t, 2, len(history.Meetings))
+				assert.Equal(t, int64(4), history.Meetings[0].PeakParticipants)
+			},
+		},
+		{
+			Name: "a missing range should default to the last 30 days",
+			Mock: func() {
+				request.SetRequestParams(c, "")
+				ListMeetingHistoryManagerMockFunc = func(filter *MeetingHistoryFilter) ([]MeetingRecord, error) {
+					assert.Equal(t, DefaultHistoryRange, filter.To.Sub(filter.From))
+					assert.WithinDuration(t, time.Now(), filter.To, time.Minute)
+					return []MeetingRecord{}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			Name: "a csv format should export the meetings history as CSV",
+			Mock: func() {
+				request.SetRequestParams(c, "format=csv&from=2022-03-17T00:00:00Z")
+				ListMeetingHistoryManagerMockFunc = func(filter *MeetingHistoryFilter) ([]MeetingRecord, error) {
+					return records, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+				expected := "id,meeting_id,tenant,instance,start_time,end_time,duration_seconds,peak_participants,recording\n" +
+					"first-1,first,localhost,http://localhost/bigbluebutton,2022-03-17T15:00:00Z,2022-03-17T15:01:30Z,90,4,true\n" +
+					"second-1,second,localhost,http://localhost/bigbluebutton,2022-03-17T15:00:00Z,,,0,false\n"
+				assert.Equal(t, expected, w.Body.String())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			test.Mock()
+			admin.ListMeetingsHistory(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
@@ -28,12 +28,15 @@ func TestMain(m *testing.M) {
 
 	instanceManager = NewInstanceManager(*client)
 	tenantManager = NewTenantManager(*client)
+	RecordAuditManagerMockFunc = func(record *AuditRecord) error {
+		return nil
+	}
 
 	router = gin.Default()
 	config := &config.Config{Admin: config.AdminConfig{
 		APIKey: test.DefaultAPIKey(),
 	}}
-	CreateAdmin(instanceManager, tenantManager, &balancer.Mock{}, config)
+	CreateAdmin(instanceManager, tenantManager, NewAuditManager(*client, &config.Admin.Audit), NewMeetingHistoryManager(*client), &WebhookManagerMock{}, &balancer.Mock{}, config)
 
 	status := m.Run()
 	if err := redisMock.ExpectationsWereMet(); err != nil {
@@ -13,6 +13,9 @@ func (a *Admin) Routes() *[]api.EndpointGroup {
 		{
 			Path: "/admin",
 			Endpoints: []interface{}{
+				api.Endpoint{
+					Handler: setLogger,
+				},
 				api.Endpoint{
 					Handler: a.APIKeyValidation,
 				},
@@ -54,6 +57,31 @@ func (a *Admin) Routes() *[]api.EndpointGroup {
 											Method:  http.MethodGet,
 											Handler: a.GetTenant,
 										},
+										api.Endpoint{
+											Path:    "/secrets",
+											Method:  http.MethodGet,
+											Handler: a.ListSecrets,
+										},
+										api.Endpoint{
+											Path:    "/secrets",
+											Method:  http.MethodPost,
+											Handler: a.RotateSecret,
+										},
+										api.Endpoint{
+											Path:    "/usage",
+											Method:  http.MethodGet,
+											Handler: a.GetTenantUsage,
+										},
+										api.Endpoint{
+											Path:    "/webhooks",
+											Method:  http.MethodGet,
+											Handler: a.ListWebhookDeliveries,
+										},
+										api.Endpoint{
+											Path:    "/webhooks/:id/retry",
+											Method:  http.MethodPost,
+											Handler: a.RetryWebhookDelivery,
+										},
 									},
 								},
 							},
@@ -68,6 +96,58 @@ func (a *Admin) Routes() *[]api.EndpointGroup {
 							Method:  http.MethodGet,
 							Handler: a.GetConfiguration,
 						},
+						api.Endpoint{
+							Path:    "/audit",
+							Method:  http.MethodGet,
+							Handler: a.ListAudit,
+						},
+						api.Endpoint{
+							Path:    "/history",
+							Method:  http.MethodGet,
+							Handler: a.ListMeetingsHistory,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TenantRoutes returns the tenant self-service routes
+func (a *Admin) TenantRoutes() *[]api.EndpointGroup {
+	return &[]api.EndpointGroup{
+		{
+			Path: "/tenant",
+			Endpoints: []interface{}{
+				api.Endpoint{
+					Handler: setLogger,
+				},
+				api.Endpoint{
+					Handler: a.TenantAPIKeyValidation,
+				},
+				api.EndpointGroup{
+					Path: "/api",
+					Endpoints: []interface{}{
+						api.Endpoint{
+							Path:    "/meetings",
+							Method:  http.MethodGet,
+							Handler: a.TenantMeetings,
+						},
+						api.Endpoint{
+							Path:    "/recordings",
+							Method:  http.MethodGet,
+							Handler: a.TenantRecordings,
+						},
+						api.Endpoint{
+							Path:    "/usage",
+							Method:  http.MethodGet,
+							Handler: a.TenantUsage,
+						},
+						api.Endpoint{
+							Path:    "/secret",
+							Method:  http.MethodPost,
+							Handler: a.RotateTenantSecret,
+						},
 					},
 				},
 			},
@@ -1,6 +1,23 @@
 // Package admin manages the bigblueswarm admin part
 package admin
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	neturl "net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+)
+
+// DefaultSecretOverlap is the default duration during which a rotated secret is still accepted
+const DefaultSecretOverlap = 24 * time.Hour
+
 // HasMeetingPool check if tenant as a meeting pool constraint
 func (t *Tenant) HasMeetingPool() bool {
 	return t.Spec.MeetingsPool != nil
@@ -10,3 +27,464 @@ func (t *Tenant) HasMeetingPool() bool {
 func (t *Tenant) HasUserPool() bool {
 	return t.Spec.UserPool != nil
 }
+
+// SecretFingerprint returns a short identifier of a secret that can safely be logged or exposed
+func SecretFingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// PrimarySecret returns the tenant primary secret. It returns the default secret if the tenant does not define its own secret
+func (t *Tenant) PrimarySecret(defaultSecret string) string {
+	if t.Spec.Secret != "" {
+		return t.Spec.Secret
+	}
+
+	return defaultSecret
+}
+
+// Secrets returns all secrets accepted at the given time. The primary secret is always the first one
+func (t *Tenant) Secrets(defaultSecret string, now time.Time) []string {
+	secrets := []string{t.PrimarySecret(defaultSecret)}
+	for _, secondary := range t.Spec.SecondarySecrets {
+		if secondary.ExpiresAt.After(now) {
+			secrets = append(secrets, secondary.Secret)
+		}
+	}
+
+	return secrets
+}
+
+// RotateSecret set a new primary secret. The previous primary secret becomes a secondary secret accepted during the overlap duration.
+// Expired secondary secrets are removed.
+func (t *Tenant) RotateSecret(secret string, defaultSecret string, overlap time.Duration, now time.Time) {
+	secondaries := []SecondarySecret{}
+	for _, secondary := range t.Spec.SecondarySecrets {
+		if secondary.ExpiresAt.After(now) && secondary.Secret != secret {
+			secondaries = append(secondaries, secondary)
+		}
+	}
+
+	if previous := t.PrimarySecret(defaultSecret); previous != "" && previous != secret && overlap > 0 {
+		secondaries = append(secondaries, SecondarySecret{
+			Secret:    previous,
+			ExpiresAt: now.Add(overlap),
+		})
+	}
+
+	t.Spec.Secret = secret
+	t.Spec.SecondarySecrets = secondaries
+}
+
+// NormalizeAlias normalize an alias or a hostname for alias matching
+func NormalizeAlias(alias string) string {
+	return strings.ToLower(strings.TrimSpace(alias))
+}
+
+// IsWildcard check if alias is a wildcard alias like *.example.com
+func IsWildcard(alias string) bool {
+	return strings.HasPrefix(alias, "*.")
+}
+
+// ValidateAlias check that an alias is an exact host or a wildcard alias
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("alias should not be an empty string")
+	}
+
+	if strings.Count(alias, "*") > 1 || (strings.Contains(alias, "*") && !IsWildcard(alias)) || alias == "*." {
+		return fmt.Errorf("invalid alias %s: a wildcard alias should be formatted as *.example.com", alias)
+	}
+
+	return nil
+}
+
+// Aliases returns the tenant normalized aliases
+func (t *Tenant) Aliases() []string {
+	aliases := []string{}
+	for _, alias := range t.Spec.Aliases {
+		aliases = append(aliases, NormalizeAlias(alias))
+	}
+
+	return aliases
+}
+
+// MatchWildcard check if the host matches the wildcard alias. The wildcard matches any subdomain, at any depth.
+// The host port is ignored unless the wildcard contains a port
+func MatchWildcard(wildcard string, host string) bool {
+	if !strings.Contains(wildcard, ":") {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+
+	return strings.HasSuffix(host, strings.TrimPrefix(wildcard, "*"))
+}
+
+// ResolveWildcard returns the tenant owning the most specific wildcard matching the host. Wildcards is a map of wildcard
+// aliases to tenant hosts. When several wildcards have the same specificity, the lowest one in lexicographic order wins
+func ResolveWildcard(wildcards map[string]string, host string) string {
+	matches := []string{}
+	for wildcard := range wildcards {
+		if MatchWildcard(wildcard, host) {
+			matches = append(matches, wildcard)
+		}
+	}
+
+	if len(matches) == 0 {
+		return ""
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i]) != len(matches[j]) {
+			return len(matches[i]) > len(matches[j])
+		}
+
+		return matches[i] < matches[j]
+	})
+
+	return wildcards[matches[0]]
+}
+
+func isReservedParameter(key string) bool {
+	return key == "checksum" || key == "meta_"+api.TenantMetadata || key == "meta_"+api.MeetingIDMetadata
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyCreateParameters apply the tenant create parameters policy on the create call parameters. Forbidden parameters are
+// removed first, then default parameters are added if absent and enforced parameters override the caller values.
+// The tenant metadata and the checksum can't be altered by the policy.
+func (t *Tenant) ApplyCreateParameters(checksum *api.Checksum) {
+	policy := t.Spec.CreateParameters
+	if policy == nil {
+		return
+	}
+
+	for _, key := range policy.Forbidden {
+		if !isReservedParameter(key) {
+			checksum.DelParam(key)
+		}
+	}
+
+	for _, key := range sortedKeys(policy.Defaults) {
+		if !isReservedParameter(key) && !checksum.HasParam(key) {
+			checksum.SetParam(key, policy.Defaults[key])
+		}
+	}
+
+	for _, key := range sortedKeys(policy.Enforced) {
+		if !isReservedParameter(key) {
+			checksum.SetParam(key, policy.Enforced[key])
+		}
+	}
+}
+
+const (
+	// QuotaPeriodDay is a daily quota period
+	QuotaPeriodDay = "day"
+	// QuotaPeriodWeek is a weekly quota period, starting on monday
+	QuotaPeriodWeek = "week"
+	// QuotaPeriodMonth is a monthly quota period. It is the default quota period
+	QuotaPeriodMonth = "month"
+)
+
+// HasQuotas check if tenant has at least one time-based quota
+func (t *Tenant) HasQuotas() bool {
+	return t.Spec.Quotas != nil && (t.Spec.Quotas.Meetings != nil || t.Spec.Quotas.ParticipantMinutes != nil)
+}
+
+// ValidateQuotas check the tenant quotas configuration
+func (t *Tenant) ValidateQuotas() error {
+	if t.Spec.Quotas == nil {
+		return nil
+	}
+
+	switch t.Spec.Quotas.Period {
+	case "", QuotaPeriodDay, QuotaPeriodWeek, QuotaPeriodMonth:
+		return nil
+	default:
+		return fmt.Errorf("invalid quota period %s: period should be day, week or month", t.Spec.Quotas.Period)
+	}
+}
+
+// QuotaPeriod returns the tenant quota period name and the current period bounds. Periods are computed in UTC
+func (t *Tenant) QuotaPeriod(now time.Time) (string, time.Time, time.Time) {
+	period := QuotaPeriodMonth
+	if t.Spec.Quotas != nil && t.Spec.Quotas.Period != "" {
+		period = t.Spec.Quotas.Period
+	}
+
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case QuotaPeriodDay:
+		return period, day, day.AddDate(0, 0, 1)
+	case QuotaPeriodWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return period, start, start.AddDate(0, 0, 7)
+	default:
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return QuotaPeriodMonth, start, start.AddDate(0, 1, 0)
+	}
+}
+
+// MeetingsExhausted check if the meetings quota is exhausted
+func (u *QuotaUsage) MeetingsExhausted() bool {
+	return u.MeetingsQuota != nil && u.Meetings >= *u.MeetingsQuota
+}
+
+// ParticipantMinutesExhausted check if the participant minutes quota is exhausted
+func (u *QuotaUsage) ParticipantMinutesExhausted() bool {
+	return u.ParticipantMinutesQuota != nil && u.ParticipantMinutes >= *u.ParticipantMinutesQuota
+}
+
+func minutes(value *int64) time.Duration {
+	if value == nil || *value <= 0 {
+		return 0
+	}
+
+	return time.Duration(*value) * time.Minute
+}
+
+// MaxMeetingDuration returns the tenant maximum meeting duration. It returns 0 if meetings duration is not limited
+func (t *Tenant) MaxMeetingDuration() time.Duration {
+	return minutes(t.Spec.MaxMeetingDuration)
+}
+
+// IdleTimeout returns the duration after which a meeting without participants is ended. It returns 0 if idle meetings
+// are not ended
+func (t *Tenant) IdleTimeout() time.Duration {
+	return minutes(t.Spec.IdleTimeout)
+}
+
+// ValidateMeetingPolicy check the tenant maximum meeting duration and idle timeout configuration
+func (t *Tenant) ValidateMeetingPolicy() error {
+	if t.Spec.MaxMeetingDuration != nil && *t.Spec.MaxMeetingDuration < 0 {
+		return fmt.Errorf("invalid max meeting duration %d: duration should be positive", *t.Spec.MaxMeetingDuration)
+	}
+
+	if t.Spec.IdleTimeout != nil && *t.Spec.IdleTimeout < 0 {
+		return fmt.Errorf("invalid idle timeout %d: timeout should be positive", *t.Spec.IdleTimeout)
+	}
+
+	return nil
+}
+
+// ApplyMaxMeetingDuration set the create call duration parameter so the meeting does not last longer than the tenant
+// maximum meeting duration. A shorter duration provided by the caller is kept
+func (t *Tenant) ApplyMaxMeetingDuration(checksum *api.Checksum) {
+	max := t.MaxMeetingDuration()
+	if max == 0 {
+		return
+	}
+
+	maxMinutes := int64(max / time.Minute)
+	if value, exists := checksum.GetParam("duration"); exists {
+		// BigBlueButton considers a zero duration as unlimited
+		if duration, err := strconv.ParseInt(value, 10, 64); err == nil && duration > 0 && duration <= maxMinutes {
+			return
+		}
+	}
+
+	checksum.SetParam("duration", strconv.FormatInt(maxMinutes, 10))
+}
+
+// MeetingDurationExceeded check if a meeting created at the given time exceeds the tenant maximum meeting duration
+func (t *Tenant) MeetingDurationExceeded(createTime time.Time, now time.Time) bool {
+	max := t.MaxMeetingDuration()
+	return max != 0 && now.Sub(createTime) >= max
+}
+
+// IdleTimeoutExceeded check if a meeting without participants since the given time exceeds the tenant idle timeout
+func (t *Tenant) IdleTimeoutExceeded(idleSince time.Time, now time.Time) bool {
+	timeout := t.IdleTimeout()
+	return timeout != 0 && now.Sub(idleSince) >= timeout
+}
+
+// RateLimitWildcard is the rate limits entry applied to the actions without a dedicated rate limit
+const RateLimitWildcard = "*"
+
+// DefaultRateLimitPeriod is the rate limit refill period used when none is configured
+const DefaultRateLimitPeriod = time.Second
+
+// RateLimit returns the rate limit applied to the given action. It returns nil if the action is not rate limited
+func (t *Tenant) RateLimit(action string) *RateLimit {
+	if limit, ok := t.Spec.RateLimits[action]; ok {
+		return limit
+	}
+
+	return t.Spec.RateLimits[RateLimitWildcard]
+}
+
+// ValidateRateLimits check the tenant rate limits configuration
+func (t *Tenant) ValidateRateLimits() error {
+	for action, limit := range t.Spec.RateLimits {
+		if limit == nil || limit.Requests <= 0 {
+			return fmt.Errorf("invalid %s rate limit: requests should be greater than 0", action)
+		}
+
+		if limit.Burst < 0 {
+			return fmt.Errorf("invalid %s rate limit: burst should be positive", action)
+		}
+
+		if limit.Period != "" {
+			period, err := time.ParseDuration(limit.Period)
+			if err != nil || period <= 0 {
+				return fmt.Errorf("invalid %s rate limit period %s", action, limit.Period)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Interval returns the rate limit refill period
+func (r *RateLimit) Interval() time.Duration {
+	if period, err := time.ParseDuration(r.Period); err == nil && period > 0 {
+		return period
+	}
+
+	return DefaultRateLimitPeriod
+}
+
+// Capacity returns the rate limit bucket capacity
+func (r *RateLimit) Capacity() int64 {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+
+	return r.Requests
+}
+
+const (
+	// MeetingIDNamespacePrefix prefixes the meeting identifiers with the tenant host
+	MeetingIDNamespacePrefix = "prefix"
+	// MeetingIDNamespaceHash replaces the meeting identifiers by a hash of the tenant host and the meeting identifier
+	MeetingIDNamespaceHash = "hash"
+)
+
+// HasMeetingIDNamespace check if the tenant meeting identifiers are namespaced on the instances
+func (t *Tenant) HasMeetingIDNamespace() bool {
+	return t.Spec.MeetingIDNamespace != ""
+}
+
+// ValidateMeetingIDNamespace check the tenant meeting identifier namespace mode
+func (t *Tenant) ValidateMeetingIDNamespace() error {
+	switch t.Spec.MeetingIDNamespace {
+	case "", MeetingIDNamespacePrefix, MeetingIDNamespaceHash:
+		return nil
+	default:
+		return fmt.Errorf("invalid meeting id namespace %s: namespace should be %s or %s", t.Spec.MeetingIDNamespace, MeetingIDNamespacePrefix, MeetingIDNamespaceHash)
+	}
+}
+
+func (t *Tenant) meetingIDPrefix() string {
+	return t.Spec.Host + "_"
+}
+
+// NamespaceMeetingID returns the meeting identifier used on the instances for the given tenant meeting identifier
+func (t *Tenant) NamespaceMeetingID(meetingID string) string {
+	switch t.Spec.MeetingIDNamespace {
+	case MeetingIDNamespacePrefix:
+		return t.meetingIDPrefix() + meetingID
+	case MeetingIDNamespaceHash:
+		sum := sha256.Sum256([]byte(t.meetingIDPrefix() + meetingID))
+		return hex.EncodeToString(sum[:])
+	default:
+		return meetingID
+	}
+}
+
+// StripMeetingID returns the tenant meeting identifier of a prefixed meeting identifier. Hashed and unknown meeting
+// identifiers are returned as is
+func (t *Tenant) StripMeetingID(meetingID string) string {
+	if t.Spec.MeetingIDNamespace != MeetingIDNamespacePrefix {
+		return meetingID
+	}
+
+	return strings.TrimPrefix(meetingID, t.meetingIDPrefix())
+}
+
+// Match check if the webhook delivers the given event type
+func (w *TenantWebhook) Match(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, filter := range w.Events {
+		if filter == eventType || (strings.HasSuffix(filter, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookSecret returns the secret signing the webhook events
+func (w *TenantWebhook) WebhookSecret(tenant *Tenant, defaultSecret string) string {
+	if w.Secret != "" {
+		return w.Secret
+	}
+
+	return tenant.PrimarySecret(defaultSecret)
+}
+
+// Webhooks returns the tenant webhooks delivering the given event type
+func (t *Tenant) Webhooks(eventType string) []*TenantWebhook {
+	webhooks := []*TenantWebhook{}
+	for _, webhook := range t.Spec.Webhooks {
+		if webhook != nil && webhook.Match(eventType) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks
+}
+
+// Webhook returns the tenant webhook posting to the given url. It returns nil if the tenant has no such webhook
+func (t *Tenant) Webhook(url string) *TenantWebhook {
+	for _, webhook := range t.Spec.Webhooks {
+		if webhook != nil && webhook.URL == url {
+			return webhook
+		}
+	}
+
+	return nil
+}
+
+// ValidateWebhooks check the tenant webhooks configuration
+func (t *Tenant) ValidateWebhooks() error {
+	urls := map[string]bool{}
+	for _, webhook := range t.Spec.Webhooks {
+		if webhook == nil {
+			return fmt.Errorf("invalid webhook: webhook should not be empty")
+		}
+
+		u, err := neturl.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url %s: url should be an absolute http or https url", webhook.URL)
+		}
+
+		if urls[webhook.URL] {
+			return fmt.Errorf("invalid webhook url %s: url is defined twice", webhook.URL)
+		}
+
+		urls[webhook.URL] = true
+		for _, filter := range webhook.Events {
+			if filter == "" || strings.Count(filter, "*") > 1 || (strings.Contains(filter, "*") && !strings.HasSuffix(filter, ".*")) {
+				return fmt.Errorf("invalid webhook %s event filter %s", webhook.URL, filter)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const secretLength = 32
+
+// GenerateSecret generates a new random url safe secret
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (a *Admin) tenantInstances(tenant *Tenant) ([]api.BigBlueButtonInstance, error) {
+	instances, err := a.InstanceManager.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenant.Instances) == 0 {
+		return instances, nil
+	}
+
+	filtered := []api.BigBlueButtonInstance{}
+	for _, instance := range instances {
+		if utils.ArrayContainsString(tenant.Instances, instance.URL) {
+			filtered = append(filtered, instance)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (a *Admin) listTenantMeetings(tenant *Tenant) ([]TenantMeeting, error) {
+	instances, err := a.tenantInstances(tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tenant instances: %s", err)
+	}
+
+	meetings := []TenantMeeting{}
+	for _, instance := range instances {
+		response, err := instance.GetMeetings()
+		if err != nil {
+			log.WithField("instance", instance.URL).Errorln("failed to retrieve meetings from instance", err)
+			continue
+		}
+
+		for _, meeting := range response.Meetings {
+			if meeting.Tenant() != tenant.Spec.Host {
+				continue
+			}
+
+			meetings = append(meetings, TenantMeeting{
+				MeetingID:        meeting.MeetingID,
+				MeetingName:      meeting.MeetingName,
+				Instance:         instance.URL,
+				CreateTime:       meeting.CreateTime,
+				Running:          meeting.Running,
+				Recording:        meeting.Recording,
+				ParticipantCount: meeting.ParticipantCount,
+				ModeratorCount:   meeting.ModeratorCount,
+			})
+		}
+	}
+
+	return meetings, nil
+}
+
+// TenantMeetings list the requesting tenant meetings
+func (a *Admin) TenantMeetings(c *gin.Context) {
+	tenant := getTenant(c)
+	meetings, err := a.listTenantMeetings(tenant)
+	if err != nil {
+		e := fmt.Errorf("failed to list tenant meetings: %s", err)
+		getLogger(c).AddField("tenant", tenant.Spec.Host).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, meetings)
+}
+
+// TenantRecordings list the requesting tenant recordings
+func (a *Admin) TenantRecordings(c *gin.Context) {
+	tenant := getTenant(c)
+	logger := getLogger(c).AddField("tenant", tenant.Spec.Host)
+	instances, err := a.tenantInstances(tenant)
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve tenant instances: %s", err)
+		logger.Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	recordings := []TenantRecording{}
+	for _, instance := range instances {
+		response, err := instance.GetRecordings("")
+		if err != nil {
+			logger.WithField("instance", instance.URL).Errorln("failed to retrieve recordings from instance", err)
+			continue
+		}
+
+		for _, recording := range response.Recordings {
+			if recording.Tenant() != tenant.Spec.Host {
+				continue
+			}
+
+			recordings = append(recordings, TenantRecording{
+				RecordID:     recording.RecordID,
+				MeetingID:    recording.MeetingID,
+				Name:         recording.Name,
+				Instance:     instance.URL,
+				Published:    recording.Published,
+				State:        recording.State,
+				StartTime:    recording.StartTime,
+				EndTime:      recording.EndTime,
+				Participants: recording.Participants,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, recordings)
+}
+
+// TenantUsage returns the requesting tenant current usage
+func (a *Admin) TenantUsage(c *gin.Context) {
+	tenant := getTenant(c)
+	meetings, err := a.listTenantMeetings(tenant)
+	if err != nil {
+		e := fmt.Errorf("failed to compute tenant usage: %s", err)
+		getLogger(c).AddField("tenant", tenant.Spec.Host).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	usage := &TenantUsage{
+		Hostname:     tenant.Spec.Host,
+		Meetings:     int64(len(meetings)),
+		MeetingsPool: tenant.Spec.MeetingsPool,
+		UserPool:     tenant.Spec.UserPool,
+	}
+
+	for _, meeting := range meetings {
+		usage.Participants += int64(meeting.ParticipantCount)
+	}
+
+	quota, err := a.TenantManager.GetQuotaUsage(tenant, time.Now())
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve tenant quota usage: %s", err)
+		getLogger(c).AddField("tenant", tenant.Spec.Host).Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	usage.Quota = quota
+
+	c.JSON(http.StatusOK, usage)
+}
+
+func (a *Admin) rotateSecret(tenant *Tenant, secret string, overlap time.Duration) (*TenantSecret, error) {
+	if secret == "" {
+		generated, err := GenerateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate tenant secret: %s", err)
+		}
+
+		secret = generated
+	}
+
+	tenant.RotateSecret(secret, a.Config.BigBlueSwarm.Secret, overlap, time.Now())
+	if err := a.TenantManager.AddTenant(tenant); err != nil {
+		return nil, fmt.Errorf("failed to store tenant secret: %w", err)
+	}
+
+	return &TenantSecret{
+		Hostname:    tenant.Spec.Host,
+		Secret:      secret,
+		Fingerprint: SecretFingerprint(secret),
+	}, nil
+}
+
+// RotateTenantSecret generates a new secret for the requesting tenant and returns it.
+// The previous secret is still accepted during the default overlap duration.
+func (a *Admin) RotateTenantSecret(c *gin.Context) {
+	tenant := getTenant(c)
+	logger := getLogger(c).AddField("tenant", tenant.Spec.Host)
+	before := auditTenant(tenant)
+	secret, err := a.rotateSecret(tenant, "", DefaultSecretOverlap)
+	if err != nil {
+		e := fmt.Errorf("failed to rotate tenant secret: %s", err)
+		logger.Error(e)
+		c.String(addTenantStatus(err), e.Error())
+		return
+	}
+
+	a.audit(c, AuditRotateSecret, tenantKey(tenant.Spec.Host), before, auditTenant(tenant))
+
+	logger.WithField("fingerprint", secret.Fingerprint).Info("tenant secret successfully rotated")
+	c.JSON(http.StatusOK, secret)
+}
@@ -0,0 +1,222 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+
+	"github.com/bigblueswarm/test_utils/pkg/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const tenantMeetingsResponse = `<response>
+	<returncode>SUCCESS</returncode>
+	<meetings>
+		<meeting>
+			<meetingID>tenant_meeting</meetingID>
+			<participantCount>3</participantCount>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+		<meeting>
+			<meetingID>other_meeting</meetingID>
+			<participantCount>5</participantCount>
+			<metadata><bigblueswarm-tenant>other.localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+	</meetings>
+</response>`
+
+const tenantRecordingsResponse = `<response>
+	<returncode>SUCCESS</returncode>
+	<recordings>
+		<recording>
+			<recordID>tenant_recording</recordID>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</recording>
+		<recording>
+			<recordID>other_recording</recordID>
+			<metadata><bigblueswarm-tenant>other.localhost</bigblueswarm-tenant></metadata>
+		</recording>
+	</recordings>
+</response>`
+
+func instanceResponse(body string) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil
+	}
+}
+
+func tenantAPIContext(w *httptest.ResponseRecorder, tenant *Tenant) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	setTenant(c, tenant)
+	return c
+}
+
+func defaultTenantInstances() ([]api.BigBlueButtonInstance, error) {
+	return []api.BigBlueButtonInstance{
+		{URL: "http://localhost/bigbluebutton", Secret: test.DefaultSecret()},
+		{URL: "http://other/bigbluebutton", Secret: test.DefaultSecret()},
+	}, nil
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	assert.Nil(t, err)
+	second, _ := GenerateSecret()
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestTenantMeetings(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	restclient.Client = &restclient.Mock{}
+	tenant := &Tenant{Spec: &TenantSpec{Host: "localhost"}, Instances: []string{"http://localhost/bigbluebutton"}}
+
+	tests := []test.Test{
+		{
+			Name: "an error returned by instance manager should return an internal server error",
+			Mock: func() {
+				ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
+					return nil, errors.New("instance manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "only the tenant meetings on tenant instances should be returned",
+			Mock: func() {
+				ListInstancesInstanceManagerMockFunc = defaultTenantInstances
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					assert.True(t, strings.HasPrefix(req.URL.String(), "http://localhost/bigbluebutton"))
+					return instanceResponse(tenantMeetingsResponse)(req)
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				meetings := []TenantMeeting{}
+				json.Unmarshal(w.Body.Bytes(), &meetings)
+				assert.Equal(t, 1, len(meetings))
+				assert.Equal(t, "tenant_meeting", meetings[0].MeetingID)
+				assert.Equal(t, "http://localhost/bigbluebutton", meetings[0].Instance)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c := tenantAPIContext(w, tenant)
+			test.Mock()
+			admin.TenantMeetings(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestTenantRecordings(t *testing.T) {
+	w := httptest.NewRecorder()
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	restclient.Client = &restclient.Mock{}
+	ListInstancesInstanceManagerMockFunc = defaultTenantInstances
+	restclient.RestClientMockDoFunc = instanceResponse(tenantRecordingsResponse)
+
+	c := tenantAPIContext(w, &Tenant{Spec: &TenantSpec{Host: "localhost"}})
+	admin.TenantRecordings(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	recordings := []TenantRecording{}
+	json.Unmarshal(w.Body.Bytes(), &recordings)
+	assert.Equal(t, 2, len(recordings))
+	for _, recording := range recordings {
+		assert.Equal(t, "tenant_recording", recording.RecordID)
+	}
+}
+
+func TestTenantUsage(t *testing.T) {
+	w := httptest.NewRecorder()
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	restclient.Client = &restclient.Mock{}
+	ListInstancesInstanceManagerMockFunc = defaultTenantInstances
+	restclient.RestClientMockDoFunc = instanceResponse(tenantMeetingsResponse)
+	pool := int64(10)
+	GetQuotaUsageTenantManagerMockFunc = func(tenant *Tenant, now time.Time) (*QuotaUsage, error) {
+		return &QuotaUsage{Period: QuotaPeriodMonth, Meetings: 4}, nil
+	}
+
+	c := tenantAPIContext(w, &Tenant{Spec: &TenantSpec{Host: "localhost", MeetingsPool: &pool}})
+	admin.TenantUsage(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	usage := &TenantUsage{}
+	json.Unmarshal(w.Body.Bytes(), usage)
+	assert.Equal(t, int64(2), usage.Meetings)
+	assert.Equal(t, int64(6), usage.Participants)
+	assert.Equal(t, pool, *usage.MeetingsPool)
+	assert.Nil(t, usage.UserPool)
+	assert.Equal(t, int64(4), usage.Quota.Meetings)
+}
+
+func TestRotateTenantSecret(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	var stored *Tenant
+
+	tests := []test.Test{
+		{
+			Name: "an error returned by tenant manager should return an internal server error",
+			Mock: func() {
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					return errors.New("tenant manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should store and return the new secret",
+			Mock: func() {
+				AddTenantTenantManagerMockFunc = func(tenant *Tenant) error {
+					stored = tenant
+					return nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				secret := &TenantSecret{}
+				json.Unmarshal(w.Body.Bytes(), secret)
+				assert.Equal(t, "localhost", secret.Hostname)
+				assert.NotEqual(t, "old_secret", secret.Secret)
+				assert.Equal(t, secret.Secret, stored.Spec.Secret)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c := tenantAPIContext(w, &Tenant{Spec: &TenantSpec{Host: "localhost", Secret: "old_secret"}})
+			test.Mock()
+			admin.RotateTenantSecret(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 	"github.com/go-redis/redis/v8"
@@ -14,6 +16,22 @@ import (
 
 const tenantPrefix = "tenant:%s"
 
+const secretUsagePrefix = "secret_usage:%s"
+
+const tenantAliasPrefix = "tenant_alias:%s"
+
+const quotaUsagePrefix = "quota_usage:%s:%s"
+
+const quotaSlotPrefix = "quota_slot:%s:%d"
+
+const (
+	meetingsUsageField           = "meetings"
+	participantSecondsUsageField = "participant_seconds"
+)
+
+// TenantWildcards is the key of the hash storing tenants wildcard aliases
+const TenantWildcards = "tenant_wildcards"
+
 // TenantManager is a struct manager bigblueswarm tenants
 type TenantManager interface {
 	// AddTenant add a tenant in the manager
@@ -22,8 +40,39 @@ type TenantManager interface {
 	ListTenants() ([]TenantListObject, error)
 	// DeleteTenant delete a specific tenant based on tenant hostname
 	DeleteTenant(hostname string) error
-	// GetTenant retrieve a tenant from a hostname
+	// GetTenant retrieve a tenant from its primary hostname
 	GetTenant(hostname string) (*Tenant, error)
+	// ResolveTenant retrieve the tenant serving a request hostname. The hostname is resolved using the tenant primary
+	// host first, then the tenant exact aliases and finally the most specific tenant wildcard alias
+	ResolveTenant(hostname string) (*Tenant, error)
+	// TrackSecretUsage record that count requests were authenticated using the secret identified by the fingerprint, the
+	// last one at lastUsed
+	TrackSecretUsage(hostname string, fingerprint string, count int64, lastUsed time.Time) error
+	// GetSecretUsage retrieve the tenant secrets usage indexed by secret fingerprint
+	GetSecretUsage(hostname string) (map[string]SecretUsage, error)
+	// ReserveMeetingUsage account a meeting creation in the tenant current quota period if the meetings quota is not
+	// exhausted. It returns false if the quota is exhausted
+	ReserveMeetingUsage(tenant *Tenant, now time.Time) (bool, error)
+	// ReleaseMeetingUsage cancel a meeting creation reserved in the tenant quota period of now
+	ReleaseMeetingUsage(tenant *Tenant, now time.Time) error
+	// AddParticipantUsage account participant seconds in the tenant current quota period. The usage is accounted only once
+	// per tenant and slot so several BigBlueSwarm instances can poll the meetings at the same time
+	AddParticipantUsage(tenant *Tenant, seconds int64, slot time.Time, interval time.Duration) error
+	// GetQuotaUsage retrieve the tenant consumption over the current quota period
+	GetQuotaUsage(tenant *Tenant, now time.Time) (*QuotaUsage, error)
+}
+
+// TenantValidationError is returned by AddTenant when the tenant spec is invalid, as opposed to a storage failure
+type TenantValidationError struct {
+	Err error
+}
+
+func (e *TenantValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TenantValidationError) Unwrap() error {
+	return e.Err
 }
 
 // RedisTenantManager is the redis implementation of TenantManager
@@ -42,10 +91,91 @@ func tenantKey(key string) string {
 	return fmt.Sprintf(tenantPrefix, key)
 }
 
-// AddTenant store tenant in redis
+func tenantAliasKey(alias string) string {
+	return fmt.Sprintf(tenantAliasPrefix, alias)
+}
+
+func (r *RedisTenantManager) checkAliases(tenant *Tenant) error {
+	for _, alias := range tenant.Aliases() {
+		if err := ValidateAlias(alias); err != nil {
+			return &TenantValidationError{Err: err}
+		}
+
+		var owner string
+		var err error
+		if IsWildcard(alias) {
+			owner, err = r.RDB.HGet(context.Background(), TenantWildcards, alias).Result()
+		} else {
+			owner, err = r.RDB.Get(context.Background(), tenantAliasKey(alias)).Result()
+		}
+
+		if utils.ComputeErr(err) != nil {
+			return fmt.Errorf("failed to check alias %s: %s", alias, err)
+		}
+
+		if owner != "" && owner != tenant.Spec.Host {
+			return &TenantValidationError{Err: fmt.Errorf("alias %s is already used by tenant %s", alias, owner)}
+		}
+
+		if IsWildcard(alias) {
+			continue
+		}
+
+		exists, err := r.RDB.Exists(context.Background(), tenantKey(alias)).Result()
+		if utils.ComputeErr(err) != nil {
+			return fmt.Errorf("failed to check alias %s: %s", alias, err)
+		}
+
+		if exists > 0 {
+			return &TenantValidationError{Err: fmt.Errorf("alias %s is already a tenant host", alias)}
+		}
+	}
+
+	return nil
+}
+
+func (r *RedisTenantManager) removeAliases(aliases []string) error {
+	for _, alias := range aliases {
+		var err error
+		if IsWildcard(alias) {
+			_, err = r.RDB.HDel(context.Background(), TenantWildcards, alias).Result()
+		} else {
+			_, err = r.RDB.Del(context.Background(), tenantAliasKey(alias)).Result()
+		}
+
+		if utils.ComputeErr(err) != nil {
+			return fmt.Errorf("failed to remove alias %s: %s", alias, err)
+		}
+	}
+
+	return nil
+}
+
+// AddTenant store tenant in redis. Tenant aliases are indexed so the tenant document is never duplicated
 func (r *RedisTenantManager) AddTenant(tenant *Tenant) error {
 	if tenant.Spec.Host == "" {
-		return errors.New("tenant host chould not be nil or empty string")
+		return &TenantValidationError{Err: errors.New("tenant host chould not be nil or empty string")}
+	}
+
+	for _, validate := range []func() error{
+		tenant.ValidateQuotas,
+		tenant.ValidateMeetingPolicy,
+		tenant.ValidateRateLimits,
+		tenant.ValidateMeetingIDNamespace,
+		tenant.ValidateWebhooks,
+	} {
+		if err := validate(); err != nil {
+			return &TenantValidationError{Err: err}
+		}
+	}
+
+	if err := r.checkAliases(tenant); err != nil {
+		return err
+	}
+
+	previous, err := r.GetTenant(tenant.Spec.Host)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve previous tenant version: %s", err)
 	}
 
 	value, err := yaml.Marshal(tenant)
@@ -54,7 +184,37 @@ func (r *RedisTenantManager) AddTenant(tenant *Tenant) error {
 	}
 
 	_, rErr := r.RDB.Set(context.Background(), tenantKey(tenant.Spec.Host), string(value), 0).Result()
-	return utils.ComputeErr(rErr)
+	if utils.ComputeErr(rErr) != nil {
+		return rErr
+	}
+
+	if previous != nil {
+		stale := []string{}
+		for _, alias := range previous.Aliases() {
+			if !utils.ArrayContainsString(tenant.Aliases(), alias) {
+				stale = append(stale, alias)
+			}
+		}
+
+		if err := r.removeAliases(stale); err != nil {
+			return err
+		}
+	}
+
+	for _, alias := range tenant.Aliases() {
+		var err error
+		if IsWildcard(alias) {
+			_, err = r.RDB.HSet(context.Background(), TenantWildcards, alias, tenant.Spec.Host).Result()
+		} else {
+			_, err = r.RDB.Set(context.Background(), tenantAliasKey(alias), tenant.Spec.Host, 0).Result()
+		}
+
+		if utils.ComputeErr(err) != nil {
+			return fmt.Errorf("failed to store alias %s: %s", alias, err)
+		}
+	}
+
+	return nil
 }
 
 // ListTenants list all tenants in the system
@@ -69,6 +229,7 @@ func (r *RedisTenantManager) ListTenants() ([]TenantListObject, error) {
 
 		list = append(list, TenantListObject{
 			Hostname:      tenant.Spec.Host,
+			Aliases:       tenant.Spec.Aliases,
 			InstanceCount: len(tenant.Instances),
 		})
 
@@ -79,11 +240,22 @@ func (r *RedisTenantManager) ListTenants() ([]TenantListObject, error) {
 
 // DeleteTenant delete a specific tenant based on tenant hostname
 func (r *RedisTenantManager) DeleteTenant(hostname string) error {
-	_, err := r.RDB.Del(context.Background(), tenantKey(hostname)).Result()
+	tenant, err := r.GetTenant(hostname)
+	if err != nil {
+		return err
+	}
+
+	if tenant != nil {
+		if err := r.removeAliases(tenant.Aliases()); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.RDB.Del(context.Background(), tenantKey(hostname)).Result()
 	return utils.ComputeErr(err)
 }
 
-// GetTenant retrieve a tenant from a hostname
+// GetTenant retrieve a tenant from its primary hostname
 func (r *RedisTenantManager) GetTenant(hostname string) (*Tenant, error) {
 	res, err := r.RDB.Get(context.Background(), tenantKey(hostname)).Result()
 	if utils.ComputeErr(err) != nil {
@@ -101,3 +273,185 @@ func (r *RedisTenantManager) GetTenant(hostname string) (*Tenant, error) {
 
 	return &tenant, nil
 }
+
+// ResolveTenant retrieve the tenant serving a request hostname. The hostname is resolved using the tenant primary
+// host first, then the tenant exact aliases and finally the most specific tenant wildcard alias
+func (r *RedisTenantManager) ResolveTenant(hostname string) (*Tenant, error) {
+	tenant, err := r.GetTenant(hostname)
+	if err != nil || tenant != nil {
+		return tenant, err
+	}
+
+	host := NormalizeAlias(hostname)
+	owner, err := r.RDB.Get(context.Background(), tenantAliasKey(host)).Result()
+	if utils.ComputeErr(err) != nil {
+		return nil, fmt.Errorf("failed to resolve tenant alias: %s", err)
+	}
+
+	if owner == "" {
+		wildcards, err := r.RDB.HGetAll(context.Background(), TenantWildcards).Result()
+		if utils.ComputeErr(err) != nil {
+			return nil, fmt.Errorf("failed to resolve tenant wildcard: %s", err)
+		}
+
+		owner = ResolveWildcard(wildcards, host)
+	}
+
+	if owner == "" {
+		return nil, nil
+	}
+
+	return r.GetTenant(owner)
+}
+
+func secretUsageKey(hostname string) string {
+	return fmt.Sprintf(secretUsagePrefix, hostname)
+}
+
+func lastUsedField(fingerprint string) string {
+	return fingerprint + ":last_used"
+}
+
+// TrackSecretUsage record that count requests were authenticated using the secret identified by the fingerprint, the
+// last one at lastUsed
+func (r *RedisTenantManager) TrackSecretUsage(hostname string, fingerprint string, count int64, lastUsed time.Time) error {
+	key := secretUsageKey(hostname)
+	pipe := r.RDB.TxPipeline()
+	pipe.HIncrBy(context.Background(), key, fingerprint, count)
+	pipe.HSet(context.Background(), key, lastUsedField(fingerprint), lastUsed.Unix())
+	_, err := pipe.Exec(context.Background())
+	return utils.ComputeErr(err)
+}
+
+// GetSecretUsage retrieve the tenant secrets usage indexed by secret fingerprint
+func (r *RedisTenantManager) GetSecretUsage(hostname string) (map[string]SecretUsage, error) {
+	values, err := r.RDB.HGetAll(context.Background(), secretUsageKey(hostname)).Result()
+	if utils.ComputeErr(err) != nil {
+		return nil, err
+	}
+
+	usage := map[string]SecretUsage{}
+	for field, value := range values {
+		if strings.HasSuffix(field, ":last_used") {
+			continue
+		}
+
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid usage value for secret %s: %s", field, err)
+		}
+
+		secretUsage := SecretUsage{Count: count}
+		if lastUsed, err := strconv.ParseInt(values[lastUsedField(field)], 10, 64); err == nil {
+			t := time.Unix(lastUsed, 0)
+			secretUsage.LastUsed = &t
+		}
+
+		usage[field] = secretUsage
+	}
+
+	return usage, nil
+}
+
+func quotaUsageKey(tenant *Tenant, now time.Time) (string, time.Time) {
+	_, start, end := tenant.QuotaPeriod(now)
+	// usage is kept during one more period so the previous period consumption remains available
+	return fmt.Sprintf(quotaUsagePrefix, tenant.Spec.Host, start.Format("2006-01-02")), end.Add(end.Sub(start))
+}
+
+func (r *RedisTenantManager) incrementQuotaUsage(tenant *Tenant, field string, value int64, now time.Time) error {
+	key, expiration := quotaUsageKey(tenant, now)
+	pipe := r.RDB.TxPipeline()
+	pipe.HIncrBy(context.Background(), key, field, value)
+	pipe.ExpireAt(context.Background(), key, expiration)
+	_, err := pipe.Exec(context.Background())
+	return utils.ComputeErr(err)
+}
+
+var reserveMeetingUsageScript = redis.NewScript(`
+local quota = tonumber(ARGV[2])
+if quota >= 0 and tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0') >= quota then
+	return 0
+end
+
+redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+redis.call('EXPIREAT', KEYS[1], ARGV[3])
+return 1
+`)
+
+// ReserveMeetingUsage account a meeting creation in the tenant current quota period if the meetings quota is not
+// exhausted. The quota is checked and the usage incremented atomically so concurrent creations can't exceed the quota.
+// It returns false if the quota is exhausted
+func (r *RedisTenantManager) ReserveMeetingUsage(tenant *Tenant, now time.Time) (bool, error) {
+	quota := int64(-1)
+	if tenant.Spec.Quotas != nil && tenant.Spec.Quotas.Meetings != nil {
+		quota = *tenant.Spec.Quotas.Meetings
+	}
+
+	key, expiration := quotaUsageKey(tenant, now)
+	reserved, err := reserveMeetingUsageScript.Run(context.Background(), r.RDB, []string{key}, meetingsUsageField, quota, expiration.Unix()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return reserved == 1, nil
+}
+
+// ReleaseMeetingUsage cancel a meeting creation reserved in the tenant quota period of now
+func (r *RedisTenantManager) ReleaseMeetingUsage(tenant *Tenant, now time.Time) error {
+	return r.incrementQuotaUsage(tenant, meetingsUsageField, -1, now)
+}
+
+// AddParticipantUsage account participant seconds in the tenant current quota period. The usage is accounted only once
+// per tenant and slot so several BigBlueSwarm instances can poll the meetings at the same time
+func (r *RedisTenantManager) AddParticipantUsage(tenant *Tenant, seconds int64, slot time.Time, interval time.Duration) error {
+	slotKey := fmt.Sprintf(quotaSlotPrefix, tenant.Spec.Host, slot.Unix())
+	first, err := r.RDB.SetNX(context.Background(), slotKey, 1, 2*interval).Result()
+	if utils.ComputeErr(err) != nil {
+		return err
+	}
+
+	if !first || seconds == 0 {
+		return nil
+	}
+
+	return r.incrementQuotaUsage(tenant, participantSecondsUsageField, seconds, slot)
+}
+
+// GetQuotaUsage retrieve the tenant consumption over the current quota period
+func (r *RedisTenantManager) GetQuotaUsage(tenant *Tenant, now time.Time) (*QuotaUsage, error) {
+	key, _ := quotaUsageKey(tenant, now)
+	values, err := r.RDB.HGetAll(context.Background(), key).Result()
+	if utils.ComputeErr(err) != nil {
+		return nil, err
+	}
+
+	period, start, end := tenant.QuotaPeriod(now)
+	usage := &QuotaUsage{
+		Period: period,
+		Start:  start,
+		End:    end,
+	}
+
+	if tenant.Spec.Quotas != nil {
+		usage.MeetingsQuota = tenant.Spec.Quotas.Meetings
+		usage.ParticipantMinutesQuota = tenant.Spec.Quotas.ParticipantMinutes
+	}
+
+	if value, ok := values[meetingsUsageField]; ok {
+		if usage.Meetings, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid meetings usage value: %s", err)
+		}
+	}
+
+	if value, ok := values[participantSecondsUsageField]; ok {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid participant usage value: %s", err)
+		}
+
+		usage.ParticipantMinutes = seconds / 60
+	}
+
+	return usage, nil
+}
@@ -1,6 +1,8 @@
 // Package admin manages the bigblueswarm admin part
 package admin
 
+import "time"
+
 // TenantManagerMock is a mock implementation of the TenantManager interface.
 type TenantManagerMock struct{}
 
@@ -13,6 +15,20 @@ var (
 	DeleteTenantTenantManagerMockFunc func(hostname string) error
 	// GetTenantTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
 	GetTenantTenantManagerMockFunc func(hostname string) (*Tenant, error)
+	// ResolveTenantTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	ResolveTenantTenantManagerMockFunc func(hostname string) (*Tenant, error)
+	// TrackSecretUsageTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	TrackSecretUsageTenantManagerMockFunc func(hostname string, fingerprint string, count int64, lastUsed time.Time) error
+	// GetSecretUsageTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	GetSecretUsageTenantManagerMockFunc func(hostname string) (map[string]SecretUsage, error)
+	// ReserveMeetingUsageTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	ReserveMeetingUsageTenantManagerMockFunc func(tenant *Tenant, now time.Time) (bool, error)
+	// ReleaseMeetingUsageTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	ReleaseMeetingUsageTenantManagerMockFunc func(tenant *Tenant, now time.Time) error
+	// AddParticipantUsageTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	AddParticipantUsageTenantManagerMockFunc func(tenant *Tenant, seconds int64, slot time.Time, interval time.Duration) error
+	// GetQuotaUsageTenantManagerMockFunc is the function that will be called when the mock tenant manager is used
+	GetQuotaUsageTenantManagerMockFunc func(tenant *Tenant, now time.Time) (*QuotaUsage, error)
 )
 
 // AddTenant is a mock implementation that add a tenant
@@ -34,3 +50,38 @@ func (t *TenantManagerMock) DeleteTenant(hostname string) error {
 func (t *TenantManagerMock) GetTenant(hostname string) (*Tenant, error) {
 	return GetTenantTenantManagerMockFunc(hostname)
 }
+
+// ResolveTenant is a mock implementation that resolve a tenant from a request hostname
+func (t *TenantManagerMock) ResolveTenant(hostname string) (*Tenant, error) {
+	return ResolveTenantTenantManagerMockFunc(hostname)
+}
+
+// TrackSecretUsage is a mock implementation that record a secret usage
+func (t *TenantManagerMock) TrackSecretUsage(hostname string, fingerprint string, count int64, lastUsed time.Time) error {
+	return TrackSecretUsageTenantManagerMockFunc(hostname, fingerprint, count, lastUsed)
+}
+
+// GetSecretUsage is a mock implementation that retrieve a tenant secrets usage
+func (t *TenantManagerMock) GetSecretUsage(hostname string) (map[string]SecretUsage, error) {
+	return GetSecretUsageTenantManagerMockFunc(hostname)
+}
+
+// ReserveMeetingUsage is a mock implementation that reserve a meeting creation
+func (t *TenantManagerMock) ReserveMeetingUsage(tenant *Tenant, now time.Time) (bool, error) {
+	return ReserveMeetingUsageTenantManagerMockFunc(tenant, now)
+}
+
+// ReleaseMeetingUsage is a mock implementation that cancel a meeting creation reservation
+func (t *TenantManagerMock) ReleaseMeetingUsage(tenant *Tenant, now time.Time) error {
+	return ReleaseMeetingUsageTenantManagerMockFunc(tenant, now)
+}
+
+// AddParticipantUsage is a mock implementation that account participant seconds
+func (t *TenantManagerMock) AddParticipantUsage(tenant *Tenant, seconds int64, slot time.Time, interval time.Duration) error {
+	return AddParticipantUsageTenantManagerMockFunc(tenant, seconds, slot, interval)
+}
+
+// GetQuotaUsage is a mock implementation that retrieve a tenant quota usage
+func (t *TenantManagerMock) GetQuotaUsage(tenant *Tenant, now time.Time) (*QuotaUsage, error) {
+	return GetQuotaUsageTenantManagerMockFunc(tenant, now)
+}
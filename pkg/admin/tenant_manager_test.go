@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/bigblueswarm/test_utils/pkg/test"
 	"github.com/go-redis/redis/v8"
@@ -40,6 +41,7 @@ func TestAddTenant(t *testing.T) {
 						"http://localhost/bigbluebutton",
 					},
 				}
+				redisMock.ExpectGet(fmt.Sprintf("tenant:%s", host)).RedisNil()
 				if out, err := yaml.Marshal(tenant); err == nil {
 					mock := redisMock.ExpectSet(fmt.Sprintf("tenant:%s", host), string(out), 0)
 					mock.SetVal("")
@@ -66,6 +68,7 @@ func TestAddTenant(t *testing.T) {
 						"http://localhost/bigbluebutton",
 					},
 				}
+				redisMock.ExpectGet(fmt.Sprintf("tenant:%s", host)).RedisNil()
 				if out, err := yaml.Marshal(tenant); err == nil {
 					redisMock.ExpectSet(fmt.Sprintf("tenant:%s", host), string(out), 0).SetVal("")
 				} else {
@@ -145,6 +148,7 @@ func TestDeleteTenant(t *testing.T) {
 		{
 			Name: "an error returned by redis should return an error",
 			Mock: func() {
+				redisMock.ExpectGet("tenant:localhost").RedisNil()
 				mock := redisMock.ExpectDel("tenant:localhost")
 				mock.SetVal(0)
 				mock.SetErr(errors.New("redis error"))
@@ -156,6 +160,7 @@ func TestDeleteTenant(t *testing.T) {
 		{
 			Name: "a valid request should remove tenant from redis and return no error",
 			Mock: func() {
+				redisMock.ExpectGet("tenant:localhost").RedisNil()
 				redisMock.ExpectDel("tenant:localhost").SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
@@ -234,3 +239,319 @@ func TestGetTenant(t *testing.T) {
 		})
 	}
 }
+
+func TestTrackSecretUsage(t *testing.T) {
+	tests := []test.Test{
+		{
+			Name: "an error returned by redis should return an error",
+			Mock: func() {
+				redisMock.ExpectTxPipeline()
+				redisMock.ExpectHIncrBy("secret_usage:localhost", "fingerprint", 3).SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			Name: "a valid request should increment secret usage and return no error",
+			Mock: func() {
+				redisMock.ExpectTxPipeline()
+				redisMock.ExpectHIncrBy("secret_usage:localhost", "fingerprint", 3).SetVal(3)
+				redisMock.ExpectHSet("secret_usage:localhost", "fingerprint:last_used", int64(1650000000)).SetVal(1)
+				redisMock.ExpectTxPipelineExec()
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Mock()
+			err := tenantManager.TrackSecretUsage("localhost", "fingerprint", 3, time.Unix(1650000000, 0))
+			test.Validator(t, nil, err)
+			redisMock.ClearExpect()
+		})
+	}
+}
+
+func TestGetSecretUsage(t *testing.T) {
+	tests := []test.Test{
+		{
+			Name: "an error returned by redis should return an error",
+			Mock: func() {
+				redisMock.ExpectHGetAll("secret_usage:localhost").SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			Name: "a valid request should return the usage indexed by fingerprint",
+			Mock: func() {
+				redisMock.ExpectHGetAll("secret_usage:localhost").SetVal(map[string]string{
+					"fingerprint":           "3",
+					"fingerprint:last_used": "1666000000",
+				})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				usage := value.(map[string]SecretUsage)
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(usage))
+				assert.Equal(t, int64(3), usage["fingerprint"].Count)
+				assert.Equal(t, int64(1666000000), usage["fingerprint"].LastUsed.Unix())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Mock()
+			usage, err := tenantManager.GetSecretUsage("localhost")
+			test.Validator(t, usage, err)
+		})
+	}
+}
+
+func TestAddTenantAliases(t *testing.T) {
+	var tenant *Tenant
+	tests := []test.Test{
+		{
+			Name: "an alias used by another tenant should return an error",
+			Mock: func() {
+				tenant = &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: "localhost", Aliases: []string{"bbb.localhost"}}}
+				redisMock.ExpectGet("tenant_alias:bbb.localhost").SetVal("other.localhost")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.IsType(t, &TenantValidationError{}, err)
+			},
+		},
+		{
+			Name: "an alias that is already a tenant host should return an error",
+			Mock: func() {
+				tenant = &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: "localhost", Aliases: []string{"bbb.localhost"}}}
+				redisMock.ExpectGet("tenant_alias:bbb.localhost").RedisNil()
+				redisMock.ExpectExists("tenant:bbb.localhost").SetVal(1)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.IsType(t, &TenantValidationError{}, err)
+			},
+		},
+		{
+			Name: "an invalid wildcard alias should return an error",
+			Mock: func() {
+				tenant = &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: "localhost", Aliases: []string{"bbb.*.localhost"}}}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			Name: "aliases should be indexed and stale aliases removed",
+			Mock: func() {
+				tenant = &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: "localhost", Aliases: []string{"BBB.localhost", "*.localhost"}}}
+				previous := &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: "localhost", Aliases: []string{"old.localhost"}}}
+				redisMock.ExpectGet("tenant_alias:bbb.localhost").RedisNil()
+				redisMock.ExpectExists("tenant:bbb.localhost").SetVal(0)
+				redisMock.ExpectHGet(TenantWildcards, "*.localhost").RedisNil()
+				previousOut, _ := yaml.Marshal(previous)
+				redisMock.ExpectGet("tenant:localhost").SetVal(string(previousOut))
+				out, _ := yaml.Marshal(tenant)
+				redisMock.ExpectSet("tenant:localhost", string(out), 0).SetVal("OK")
+				redisMock.ExpectDel("tenant_alias:old.localhost").SetVal(1)
+				redisMock.ExpectSet("tenant_alias:bbb.localhost", "localhost", 0).SetVal("OK")
+				redisMock.ExpectHSet(TenantWildcards, "*.localhost", "localhost").SetVal(1)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Nil(t, redisMock.ExpectationsWereMet())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Mock()
+			err := tenantManager.AddTenant(tenant)
+			test.Validator(t, nil, err)
+			redisMock.ClearExpect()
+		})
+	}
+}
+
+func TestResolveTenant(t *testing.T) {
+	var host string
+	tenant := &Tenant{Kind: "Tenant", Spec: &TenantSpec{Host: "localhost"}}
+	out, _ := yaml.Marshal(tenant)
+	tests := []test.Test{
+		{
+			Name: "a tenant primary host should resolve the tenant",
+			Mock: func() {
+				host = "localhost"
+				redisMock.ExpectGet("tenant:localhost").SetVal(string(out))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "localhost", value.(*Tenant).Spec.Host)
+			},
+		},
+		{
+			Name: "an exact alias should resolve the tenant",
+			Mock: func() {
+				host = "BBB.localhost"
+				redisMock.ExpectGet("tenant:BBB.localhost").RedisNil()
+				redisMock.ExpectGet("tenant_alias:bbb.localhost").SetVal("localhost")
+				redisMock.ExpectGet("tenant:localhost").SetVal(string(out))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "localhost", value.(*Tenant).Spec.Host)
+			},
+		},
+		{
+			Name: "a wildcard alias should resolve the tenant",
+			Mock: func() {
+				host = "bbb.example.com"
+				redisMock.ExpectGet("tenant:bbb.example.com").RedisNil()
+				redisMock.ExpectGet("tenant_alias:bbb.example.com").RedisNil()
+				redisMock.ExpectHGetAll(TenantWildcards).SetVal(map[string]string{"*.example.com": "localhost"})
+				redisMock.ExpectGet("tenant:localhost").SetVal(string(out))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, "localhost", value.(*Tenant).Spec.Host)
+			},
+		},
+		{
+			Name: "an unknown host should return nil for tenant and error",
+			Mock: func() {
+				host = "unknown"
+				redisMock.ExpectGet("tenant:unknown").RedisNil()
+				redisMock.ExpectGet("tenant_alias:unknown").RedisNil()
+				redisMock.ExpectHGetAll(TenantWildcards).SetVal(map[string]string{"*.example.com": "localhost"})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Nil(t, value)
+			},
+		},
+		{
+			Name: "an error returned by redis should return the error",
+			Mock: func() {
+				host = "bbb.localhost"
+				redisMock.ExpectGet("tenant:bbb.localhost").RedisNil()
+				redisMock.ExpectGet("tenant_alias:bbb.localhost").SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Mock()
+			tenant, err := tenantManager.ResolveTenant(host)
+			if tenant == nil {
+				test.Validator(t, nil, err)
+			} else {
+				test.Validator(t, tenant, err)
+			}
+			redisMock.ClearExpect()
+		})
+	}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	now := time.Date(2022, time.March, 17, 15, 4, 5, 0, time.UTC)
+	quota := int64(100)
+	tenant := &Tenant{Spec: &TenantSpec{Host: "localhost", Quotas: &TenantQuotas{Meetings: &quota}}}
+	key := "quota_usage:localhost:2022-03-01"
+	expiration := time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("ReserveMeetingUsage should increment the current period meetings until the quota is reached", func(t *testing.T) {
+		redisMock.ExpectEvalSha(reserveMeetingUsageScript.Hash(), []string{key}, "meetings", quota, expiration.Unix()).SetVal(int64(1))
+		reserved, err := tenantManager.ReserveMeetingUsage(tenant, now)
+		assert.Nil(t, err)
+		assert.True(t, reserved)
+
+		redisMock.ExpectEvalSha(reserveMeetingUsageScript.Hash(), []string{key}, "meetings", quota, expiration.Unix()).SetVal(int64(0))
+		reserved, err = tenantManager.ReserveMeetingUsage(tenant, now)
+		assert.Nil(t, err)
+		assert.False(t, reserved)
+		assert.Nil(t, redisMock.ExpectationsWereMet())
+		redisMock.ClearExpect()
+	})
+
+	t.Run("ReserveMeetingUsage should not limit a tenant without meetings quota", func(t *testing.T) {
+		unlimited := &Tenant{Spec: &TenantSpec{Host: "localhost"}}
+		redisMock.ExpectEvalSha(reserveMeetingUsageScript.Hash(), []string{key}, "meetings", int64(-1), expiration.Unix()).SetVal(int64(1))
+		reserved, err := tenantManager.ReserveMeetingUsage(unlimited, now)
+		assert.Nil(t, err)
+		assert.True(t, reserved)
+		assert.Nil(t, redisMock.ExpectationsWereMet())
+		redisMock.ClearExpect()
+	})
+
+	t.Run("ReserveMeetingUsage should return an error if redis throws an error", func(t *testing.T) {
+		redisMock.ExpectEvalSha(reserveMeetingUsageScript.Hash(), []string{key}, "meetings", quota, expiration.Unix()).SetErr(errors.New("redis error"))
+		_, err := tenantManager.ReserveMeetingUsage(tenant, now)
+		assert.NotNil(t, err)
+		redisMock.ClearExpect()
+	})
+
+	t.Run("ReleaseMeetingUsage should decrement the current period meetings", func(t *testing.T) {
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectHIncrBy(key, "meetings", -1).SetVal(0)
+		redisMock.ExpectExpireAt(key, expiration).SetVal(true)
+		redisMock.ExpectTxPipelineExec()
+		assert.Nil(t, tenantManager.ReleaseMeetingUsage(tenant, now))
+		assert.Nil(t, redisMock.ExpectationsWereMet())
+		redisMock.ClearExpect()
+	})
+
+	t.Run("AddParticipantUsage should increment participant seconds once per slot", func(t *testing.T) {
+		redisMock.ExpectSetNX(fmt.Sprintf("quota_slot:localhost:%d", now.Unix()), 1, 2*time.Minute).SetVal(true)
+		redisMock.ExpectTxPipeline()
+		redisMock.ExpectHIncrBy(key, "participant_seconds", 600).SetVal(600)
+		redisMock.ExpectExpireAt(key, expiration).SetVal(true)
+		redisMock.ExpectTxPipelineExec()
+		assert.Nil(t, tenantManager.AddParticipantUsage(tenant, 600, now, time.Minute))
+
+		redisMock.ExpectSetNX(fmt.Sprintf("quota_slot:localhost:%d", now.Unix()), 1, 2*time.Minute).SetVal(false)
+		assert.Nil(t, tenantManager.AddParticipantUsage(tenant, 600, now, time.Minute))
+		assert.Nil(t, redisMock.ExpectationsWereMet())
+		redisMock.ClearExpect()
+	})
+
+	t.Run("GetQuotaUsage should return the current period usage", func(t *testing.T) {
+		redisMock.ExpectHGetAll(key).SetVal(map[string]string{"meetings": "12", "participant_seconds": "7260"})
+		usage, err := tenantManager.GetQuotaUsage(tenant, now)
+		assert.Nil(t, err)
+		assert.Equal(t, QuotaPeriodMonth, usage.Period)
+		assert.Equal(t, int64(12), usage.Meetings)
+		assert.Equal(t, int64(121), usage.ParticipantMinutes)
+		assert.Equal(t, quota, *usage.MeetingsQuota)
+		assert.Nil(t, usage.ParticipantMinutesQuota)
+		redisMock.ClearExpect()
+	})
+
+	t.Run("GetQuotaUsage should return an error if redis returns an error", func(t *testing.T) {
+		redisMock.ExpectHGetAll(key).SetErr(errors.New("redis error"))
+		_, err := tenantManager.GetQuotaUsage(tenant, now)
+		assert.NotNil(t, err)
+		redisMock.ClearExpect()
+	})
+
+	t.Run("AddTenant should reject an invalid quota period", func(t *testing.T) {
+		err := tenantManager.AddTenant(&Tenant{Spec: &TenantSpec{Host: "localhost", Quotas: &TenantQuotas{Period: "year"}}})
+		assert.IsType(t, &TenantValidationError{}, err)
+	})
+
+	t.Run("AddTenant should reject a negative idle timeout", func(t *testing.T) {
+		timeout := int64(-1)
+		err := tenantManager.AddTenant(&Tenant{Spec: &TenantSpec{Host: "localhost", IdleTimeout: &timeout}})
+		assert.NotNil(t, err)
+	})
+}
@@ -2,7 +2,9 @@ package admin
 
 import (
 	"testing"
+	"time"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +39,326 @@ func TestHasUserPool(t *testing.T) {
 		assert.True(t, tenant.HasUserPool())
 	})
 }
+
+func TestSecretFingerprint(t *testing.T) {
+	assert.Equal(t, 12, len(SecretFingerprint("secret")))
+	assert.Equal(t, SecretFingerprint("secret"), SecretFingerprint("secret"))
+	assert.NotEqual(t, SecretFingerprint("secret"), SecretFingerprint("other_secret"))
+}
+
+func TestSecrets(t *testing.T) {
+	now := time.Now()
+	tenant := &Tenant{
+		Spec: &TenantSpec{},
+	}
+
+	t.Run("tenant without secret should use the default secret", func(t *testing.T) {
+		assert.Equal(t, []string{"default"}, tenant.Secrets("default", now))
+	})
+
+	t.Run("expired secondary secrets should not be returned", func(t *testing.T) {
+		tenant.Spec.Secret = "primary"
+		tenant.Spec.SecondarySecrets = []SecondarySecret{
+			{Secret: "valid", ExpiresAt: now.Add(time.Hour)},
+			{Secret: "expired", ExpiresAt: now.Add(-time.Hour)},
+		}
+		assert.Equal(t, []string{"primary", "valid"}, tenant.Secrets("default", now))
+	})
+}
+
+func TestTenantRotateSecret(t *testing.T) {
+	now := time.Now()
+
+	t.Run("previous primary secret should become a secondary secret", func(t *testing.T) {
+		tenant := &Tenant{
+			Spec: &TenantSpec{
+				Secret: "old",
+				SecondarySecrets: []SecondarySecret{
+					{Secret: "expired", ExpiresAt: now.Add(-time.Hour)},
+				},
+			},
+		}
+		tenant.RotateSecret("new", "default", time.Hour, now)
+		assert.Equal(t, "new", tenant.Spec.Secret)
+		assert.Equal(t, []SecondarySecret{{Secret: "old", ExpiresAt: now.Add(time.Hour)}}, tenant.Spec.SecondarySecrets)
+	})
+
+	t.Run("default secret should become a secondary secret if tenant does not have its own secret", func(t *testing.T) {
+		tenant := &Tenant{Spec: &TenantSpec{}}
+		tenant.RotateSecret("new", "default", time.Hour, now)
+		assert.Equal(t, []string{"new", "default"}, tenant.Secrets("default", now))
+	})
+
+	t.Run("a zero overlap should not keep the previous secret", func(t *testing.T) {
+		tenant := &Tenant{Spec: &TenantSpec{Secret: "old"}}
+		tenant.RotateSecret("new", "default", 0, now)
+		assert.Empty(t, tenant.Spec.SecondarySecrets)
+	})
+}
+
+func TestValidateAlias(t *testing.T) {
+	for _, alias := range []string{"example.com", "*.example.com", "localhost:8090"} {
+		assert.Nil(t, ValidateAlias(alias), alias)
+	}
+
+	for _, alias := range []string{"", "*.", "*example.com", "a.*.example.com", "*.*.example.com"} {
+		assert.NotNil(t, ValidateAlias(alias), alias)
+	}
+}
+
+func TestTenantAliases(t *testing.T) {
+	tenant := &Tenant{Spec: &TenantSpec{Aliases: []string{" Example.COM ", "*.Example.com"}}}
+	assert.Equal(t, []string{"example.com", "*.example.com"}, tenant.Aliases())
+}
+
+func TestMatchWildcard(t *testing.T) {
+	assert.True(t, MatchWildcard("*.example.com", "bbb.example.com"))
+	assert.True(t, MatchWildcard("*.example.com", "a.b.example.com"))
+	assert.True(t, MatchWildcard("*.example.com", "bbb.example.com:8090"))
+	assert.False(t, MatchWildcard("*.example.com", "example.com"))
+	assert.False(t, MatchWildcard("*.example.com", "bbb.otherexample.com"))
+	assert.True(t, MatchWildcard("*.example.com:8090", "bbb.example.com:8090"))
+	assert.False(t, MatchWildcard("*.example.com:8090", "bbb.example.com:8091"))
+}
+
+func TestResolveWildcard(t *testing.T) {
+	wildcards := map[string]string{
+		"*.example.com":       "example",
+		"*.sub.example.com":   "sub",
+		"*.a.sub.example.com": "a",
+		"*.b.sub.example.com": "b",
+	}
+
+	assert.Equal(t, "example", ResolveWildcard(wildcards, "bbb.example.com"))
+	assert.Equal(t, "sub", ResolveWildcard(wildcards, "bbb.sub.example.com"))
+	assert.Equal(t, "a", ResolveWildcard(wildcards, "bbb.a.sub.example.com"))
+	assert.Equal(t, "", ResolveWildcard(wildcards, "bbb.other.com"))
+	assert.Equal(t, "", ResolveWildcard(map[string]string{}, "bbb.example.com"))
+}
+
+func TestApplyCreateParameters(t *testing.T) {
+	t.Run("a tenant without create parameters should not alter the parameters", func(t *testing.T) {
+		checksum := &api.Checksum{Params: "name=test&record=true"}
+		(&Tenant{Spec: &TenantSpec{}}).ApplyCreateParameters(checksum)
+		assert.Equal(t, "name=test&record=true", checksum.Params)
+	})
+
+	t.Run("create parameters policy should be applied", func(t *testing.T) {
+		tenant := &Tenant{
+			Spec: &TenantSpec{
+				CreateParameters: &CreateParameters{
+					Defaults: map[string]string{
+						"welcome":         "Welcome!",
+						"maxParticipants": "50",
+					},
+					Enforced: map[string]string{
+						"record":                 "false",
+						"lockSettingsDisableCam": "true",
+					},
+					Forbidden: []string{"logo", "meta_bigblueswarm-tenant"},
+				},
+			},
+		}
+
+		checksum := &api.Checksum{Params: "name=test&maxParticipants=10&record=true&logo=http%3A%2F%2Flogo&meta_bigblueswarm-tenant=localhost"}
+		tenant.ApplyCreateParameters(checksum)
+		assert.Equal(t, "name=test&maxParticipants=10&meta_bigblueswarm-tenant=localhost&welcome=Welcome%21&lockSettingsDisableCam=true&record=false", checksum.Params)
+	})
+}
+
+func TestQuotaPeriod(t *testing.T) {
+	now := time.Date(2022, time.March, 17, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		period string
+		name   string
+		start  time.Time
+		end    time.Time
+	}{
+		{"", QuotaPeriodMonth, time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{QuotaPeriodDay, QuotaPeriodDay, time.Date(2022, time.March, 17, 0, 0, 0, 0, time.UTC), time.Date(2022, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{QuotaPeriodWeek, QuotaPeriodWeek, time.Date(2022, time.March, 14, 0, 0, 0, 0, time.UTC), time.Date(2022, time.March, 21, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tenant := &Tenant{Spec: &TenantSpec{Quotas: &TenantQuotas{Period: test.period}}}
+			name, start, end := tenant.QuotaPeriod(now)
+			assert.Equal(t, test.name, name)
+			assert.Equal(t, test.start, start)
+			assert.Equal(t, test.end, end)
+		})
+	}
+
+	t.Run("a tenant without quotas should use a monthly period", func(t *testing.T) {
+		name, _, _ := (&Tenant{Spec: &TenantSpec{}}).QuotaPeriod(now)
+		assert.Equal(t, QuotaPeriodMonth, name)
+	})
+}
+
+func TestValidateQuotas(t *testing.T) {
+	assert.Nil(t, (&Tenant{Spec: &TenantSpec{}}).ValidateQuotas())
+	assert.Nil(t, (&Tenant{Spec: &TenantSpec{Quotas: &TenantQuotas{Period: QuotaPeriodWeek}}}).ValidateQuotas())
+	assert.NotNil(t, (&Tenant{Spec: &TenantSpec{Quotas: &TenantQuotas{Period: "year"}}}).ValidateQuotas())
+}
+
+func TestQuotaUsageExhausted(t *testing.T) {
+	quota := int64(10)
+	usage := &QuotaUsage{Meetings: 10, ParticipantMinutes: 9}
+	assert.False(t, usage.MeetingsExhausted())
+	usage.MeetingsQuota = &quota
+	usage.ParticipantMinutesQuota = &quota
+	assert.True(t, usage.MeetingsExhausted())
+	assert.False(t, usage.ParticipantMinutesExhausted())
+}
+
+func TestValidateMeetingPolicy(t *testing.T) {
+	valid := int64(60)
+	negative := int64(-1)
+	assert.Nil(t, (&Tenant{Spec: &TenantSpec{}}).ValidateMeetingPolicy())
+	assert.Nil(t, (&Tenant{Spec: &TenantSpec{MaxMeetingDuration: &valid, IdleTimeout: &valid}}).ValidateMeetingPolicy())
+	assert.NotNil(t, (&Tenant{Spec: &TenantSpec{MaxMeetingDuration: &negative}}).ValidateMeetingPolicy())
+	assert.NotNil(t, (&Tenant{Spec: &TenantSpec{IdleTimeout: &negative}}).ValidateMeetingPolicy())
+}
+
+func TestApplyMaxMeetingDuration(t *testing.T) {
+	max := int64(60)
+	tests := []struct {
+		name     string
+		params   string
+		expected string
+	}{
+		{name: "a missing duration should be set to the maximum", params: "name=test", expected: "name=test&duration=60"},
+		{name: "a shorter duration should be kept", params: "duration=30&name=test", expected: "duration=30&name=test"},
+		{name: "a longer duration should be replaced", params: "duration=120&name=test", expected: "name=test&duration=60"},
+		{name: "an unlimited duration should be replaced", params: "duration=0&name=test", expected: "name=test&duration=60"},
+		{name: "an invalid duration should be replaced", params: "duration=abc", expected: "duration=60"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checksum := &api.Checksum{Params: test.params}
+			(&Tenant{Spec: &TenantSpec{MaxMeetingDuration: &max}}).ApplyMaxMeetingDuration(checksum)
+			assert.Equal(t, test.expected, checksum.Params)
+		})
+	}
+
+	t.Run("a tenant without maximum meeting duration should not alter the parameters", func(t *testing.T) {
+		checksum := &api.Checksum{Params: "duration=120"}
+		(&Tenant{Spec: &TenantSpec{}}).ApplyMaxMeetingDuration(checksum)
+		assert.Equal(t, "duration=120", checksum.Params)
+	})
+}
+
+func TestMeetingPolicyExceeded(t *testing.T) {
+	limit := int64(30)
+	now := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	tenant := &Tenant{Spec: &TenantSpec{MaxMeetingDuration: &limit, IdleTimeout: &limit}}
+	assert.True(t, tenant.MeetingDurationExceeded(now.Add(-30*time.Minute), now))
+	assert.False(t, tenant.MeetingDurationExceeded(now.Add(-29*time.Minute), now))
+	assert.True(t, tenant.IdleTimeoutExceeded(now.Add(-time.Hour), now))
+	assert.False(t, tenant.IdleTimeoutExceeded(now.Add(-time.Minute), now))
+
+	unlimited := &Tenant{Spec: &TenantSpec{}}
+	assert.False(t, unlimited.MeetingDurationExceeded(now.Add(-24*time.Hour), now))
+	assert.False(t, unlimited.IdleTimeoutExceeded(now.Add(-24*time.Hour), now))
+}
+
+func TestTenantRateLimit(t *testing.T) {
+	join := &RateLimit{Requests: 100}
+	wildcard := &RateLimit{Requests: 10, Period: "1m", Burst: 20}
+	tenant := &Tenant{Spec: &TenantSpec{RateLimits: map[string]*RateLimit{"join": join, RateLimitWildcard: wildcard}}}
+	assert.Equal(t, join, tenant.RateLimit("join"))
+	assert.Equal(t, wildcard, tenant.RateLimit("getMeetings"))
+	assert.Nil(t, (&Tenant{Spec: &TenantSpec{}}).RateLimit("join"))
+
+	assert.Equal(t, time.Second, join.Interval())
+	assert.Equal(t, int64(100), join.Capacity())
+	assert.Equal(t, time.Minute, wildcard.Interval())
+	assert.Equal(t, int64(20), wildcard.Capacity())
+}
+
+func TestValidateRateLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *RateLimit
+		valid bool
+	}{
+		{name: "a valid rate limit", limit: &RateLimit{Requests: 10, Period: "1m", Burst: 20}, valid: true},
+		{name: "a nil rate limit", limit: nil, valid: false},
+		{name: "a rate limit without requests", limit: &RateLimit{Period: "1m"}, valid: false},
+		{name: "a negative burst", limit: &RateLimit{Requests: 10, Burst: -1}, valid: false},
+		{name: "an invalid period", limit: &RateLimit{Requests: 10, Period: "minute"}, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := (&Tenant{Spec: &TenantSpec{RateLimits: map[string]*RateLimit{"join": test.limit}}}).ValidateRateLimits()
+			assert.Equal(t, test.valid, err == nil)
+		})
+	}
+}
+
+func TestMeetingIDNamespace(t *testing.T) {
+	prefixed := &Tenant{Spec: &TenantSpec{Host: "localhost", MeetingIDNamespace: MeetingIDNamespacePrefix}}
+	assert.True(t, prefixed.HasMeetingIDNamespace())
+	assert.Equal(t, "localhost_1", prefixed.NamespaceMeetingID("1"))
+	assert.Equal(t, "1", prefixed.StripMeetingID("localhost_1"))
+	assert.Equal(t, "other_1", prefixed.StripMeetingID("other_1"))
+
+	hashed := &Tenant{Spec: &TenantSpec{Host: "localhost", MeetingIDNamespace: MeetingIDNamespaceHash}}
+	other := &Tenant{Spec: &TenantSpec{Host: "other.localhost", MeetingIDNamespace: MeetingIDNamespaceHash}}
+	assert.Len(t, hashed.NamespaceMeetingID("1"), 64)
+	assert.Equal(t, hashed.NamespaceMeetingID("1"), hashed.NamespaceMeetingID("1"))
+	assert.NotEqual(t, hashed.NamespaceMeetingID("1"), other.NamespaceMeetingID("1"))
+	assert.Equal(t, hashed.NamespaceMeetingID("1"), hashed.StripMeetingID(hashed.NamespaceMeetingID("1")))
+
+	plain := &Tenant{Spec: &TenantSpec{Host: "localhost"}}
+	assert.False(t, plain.HasMeetingIDNamespace())
+	assert.Equal(t, "1", plain.NamespaceMeetingID("1"))
+
+	assert.Nil(t, prefixed.ValidateMeetingIDNamespace())
+	assert.Nil(t, plain.ValidateMeetingIDNamespace())
+	assert.NotNil(t, (&Tenant{Spec: &TenantSpec{MeetingIDNamespace: "base64"}}).ValidateMeetingIDNamespace())
+}
+
+func TestTenantWebhooks(t *testing.T) {
+	all := &TenantWebhook{URL: "https://example.com/all"}
+	meetings := &TenantWebhook{URL: "https://example.com/meetings", Secret: "webhook", Events: []string{"meeting.*"}}
+	recordings := &TenantWebhook{URL: "https://example.com/recordings", Events: []string{"recording.discovered"}}
+	tenant := &Tenant{Spec: &TenantSpec{Host: "localhost", Secret: "tenant", Webhooks: []*TenantWebhook{all, meetings, recordings}}}
+
+	assert.Equal(t, []*TenantWebhook{all, meetings}, tenant.Webhooks("meeting.created"))
+	assert.Equal(t, []*TenantWebhook{all, recordings}, tenant.Webhooks("recording.discovered"))
+	assert.Equal(t, []*TenantWebhook{all}, tenant.Webhooks("pool.limit_reached"))
+	assert.False(t, meetings.Match("meetings.created"))
+
+	assert.Equal(t, meetings, tenant.Webhook("https://example.com/meetings"))
+	assert.Nil(t, tenant.Webhook("https://example.com/unknown"))
+
+	assert.Equal(t, "webhook", meetings.WebhookSecret(tenant, "default"))
+	assert.Equal(t, "tenant", all.WebhookSecret(tenant, "default"))
+	assert.Equal(t, "default", all.WebhookSecret(&Tenant{Spec: &TenantSpec{}}, "default"))
+}
+
+func TestValidateWebhooks(t *testing.T) {
+	tests := []struct {
+		name     string
+		webhooks []*TenantWebhook
+		valid    bool
+	}{
+		{name: "valid webhooks", webhooks: []*TenantWebhook{{URL: "https://example.com/hook", Events: []string{"meeting.*", "recording.discovered"}}}, valid: true},
+		{name: "a nil webhook", webhooks: []*TenantWebhook{nil}, valid: false},
+		{name: "a relative url", webhooks: []*TenantWebhook{{URL: "/hook"}}, valid: false},
+		{name: "a non http url", webhooks: []*TenantWebhook{{URL: "ftp://example.com/hook"}}, valid: false},
+		{name: "a duplicated url", webhooks: []*TenantWebhook{{URL: "https://example.com/hook"}, {URL: "https://example.com/hook"}}, valid: false},
+		{name: "an empty event filter", webhooks: []*TenantWebhook{{URL: "https://example.com/hook", Events: []string{""}}}, valid: false},
+		{name: "an invalid wildcard filter", webhooks: []*TenantWebhook{{URL: "https://example.com/hook", Events: []string{"meeting*"}}}, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := (&Tenant{Spec: &TenantSpec{Webhooks: test.webhooks}}).ValidateWebhooks()
+			assert.Equal(t, test.valid, err == nil)
+		})
+	}
+}
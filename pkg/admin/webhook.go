@@ -0,0 +1,106 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultWebhookDeliveriesLimit is the default number of webhook deliveries returned
+	DefaultWebhookDeliveriesLimit int64 = 50
+	// MaxWebhookDeliveriesLimit is the maximum number of webhook deliveries returned
+	MaxWebhookDeliveriesLimit int64 = 500
+)
+
+// ListWebhookDeliveries returns the tenant webhook deliveries, most recent first. The deliveries are filtered by the
+// `status` query parameter, `dead` returning the dead letter queue, and limited by the `limit` query parameter
+func (a *Admin) ListWebhookDeliveries(c *gin.Context) {
+	tenant, ok := a.getTenantFromParams(c)
+	if !ok {
+		return
+	}
+
+	logger := getLogger(c).AddField("tenant", tenant.Spec.Host)
+	status := c.Query("status")
+	if status != "" && status != WebhookDeliveryPending && status != WebhookDeliveryDelivered && status != WebhookDeliveryDead {
+		m := fmt.Sprintf("invalid status parameter %s, expecting %s, %s or %s", status, WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead)
+		logger.Warn(m)
+		c.String(http.StatusBadRequest, m)
+		return
+	}
+
+	limit, err := queryInt(c, "limit", DefaultWebhookDeliveriesLimit)
+	if err != nil || limit == 0 || limit > MaxWebhookDeliveriesLimit {
+		m := fmt.Sprintf("limit parameter should be between 1 and %d", MaxWebhookDeliveriesLimit)
+		logger.Warn(m)
+		c.String(http.StatusBadRequest, m)
+		return
+	}
+
+	deliveries, err := a.WebhookManager.List(tenant.Spec.Host, status, limit)
+	if err != nil {
+		e := fmt.Errorf("failed to list webhook deliveries: %s", err)
+		logger.Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, &WebhookDeliveryList{
+		Kind:       "WebhookDeliveryList",
+		Tenant:     tenant.Spec.Host,
+		Status:     status,
+		Deliveries: deliveries,
+	})
+}
+
+// RetryWebhookDelivery moves a dead delivery out of the dead letter queue. The delivery is sent on the next deliveries
+// poll with a new attempts budget
+func (a *Admin) RetryWebhookDelivery(c *gin.Context) {
+	tenant, ok := a.getTenantFromParams(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+	logger := getLogger(c).AddField("tenant", tenant.Spec.Host).AddField("delivery_id", id)
+	delivery, err := a.WebhookManager.Get(id)
+	if err != nil {
+		e := fmt.Errorf("failed to retrieve webhook delivery: %s", err)
+		logger.Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	if delivery == nil || delivery.Tenant != tenant.Spec.Host {
+		logger.Info("webhook delivery not found")
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	if delivery.Status != WebhookDeliveryDead {
+		m := fmt.Sprintf("webhook delivery is %s, only dead deliveries can be retried", delivery.Status)
+		logger.Warn(m)
+		c.String(http.StatusConflict, m)
+		return
+	}
+
+	before := *delivery
+	now := time.Now().UTC()
+	delivery.Status = WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	if err := a.WebhookManager.Update(delivery); err != nil {
+		e := fmt.Errorf("failed to retry webhook delivery: %s", err)
+		logger.Error(e)
+		c.String(http.StatusInternalServerError, e.Error())
+		return
+	}
+
+	a.audit(c, AuditRetryWebhookDelivery, "webhook_delivery:"+delivery.ID, &before, delivery)
+	logger.Info("webhook delivery scheduled for retry")
+	c.JSON(http.StatusAccepted, delivery)
+}
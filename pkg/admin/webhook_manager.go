@@ -0,0 +1,264 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// WebhookDeliveryPending is the status of a delivery waiting for its next attempt
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliveryDelivered is the status of a delivery acknowledged by the webhook
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryDead is the status of a delivery moved to the dead letter queue after its last failed attempt
+	WebhookDeliveryDead = "dead"
+)
+
+// WebhookPendingKey is the key of the sorted set indexing the pending deliveries by next attempt time
+const WebhookPendingKey = "webhook_deliveries:pending"
+
+const webhookDeliveryPrefix = "webhook_delivery:%s"
+
+const webhookTenantPrefix = "webhook_deliveries:%s"
+
+const webhookDeadLetterPrefix = "webhook_dead_letters:%s"
+
+// WebhookManager stores the tenants webhook deliveries
+type WebhookManager interface {
+	// Schedule stores a new pending delivery
+	Schedule(delivery *WebhookDelivery) error
+	// Claim returns the pending deliveries due at the given time. Claimed deliveries are postponed by the lease duration
+	// so several BigBlueSwarm instances do not send the same delivery at the same time
+	Claim(now time.Time, limit int64, lease time.Duration) ([]WebhookDelivery, error)
+	// Update stores the delivery and indexes it according to its status
+	Update(delivery *WebhookDelivery) error
+	// Get retrieve a delivery from its identifier. It returns nil if the delivery does not exist
+	Get(id string) (*WebhookDelivery, error)
+	// List returns the tenant deliveries, most recent first. Only the dead letters are returned if status is dead
+	List(tenant string, status string, limit int64) ([]WebhookDelivery, error)
+}
+
+// RedisWebhookManager is the redis implementation of WebhookManager. Deliveries are stored as JSON documents indexed by
+// creation time for each tenant. Delivered and dead deliveries expire after the retention duration
+type RedisWebhookManager struct {
+	RDB       *redis.Client
+	Retention time.Duration
+}
+
+// NewWebhookManager creates a new WebhookManager from the tenants webhooks configuration. Finished deliveries are kept
+// forever if the retention is not a valid duration
+func NewWebhookManager(redis redis.Client, conf *config.TenantWebhooksConfig) WebhookManager {
+	retention, _ := time.ParseDuration(conf.Retention)
+	return &RedisWebhookManager{
+		RDB:       &redis,
+		Retention: retention,
+	}
+}
+
+// WebhookDeliveryKey returns the key of a webhook delivery
+func WebhookDeliveryKey(id string) string {
+	return fmt.Sprintf(webhookDeliveryPrefix, id)
+}
+
+// WebhookTenantKey returns the key of the sorted set indexing the tenant deliveries by creation time
+func WebhookTenantKey(tenant string) string {
+	return fmt.Sprintf(webhookTenantPrefix, tenant)
+}
+
+// WebhookDeadLetterKey returns the key of the sorted set indexing the tenant dead deliveries by last attempt time
+func WebhookDeadLetterKey(tenant string) string {
+	return fmt.Sprintf(webhookDeadLetterPrefix, tenant)
+}
+
+var claimDeliveriesScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZADD', KEYS[1], ARGV[3], id)
+end
+
+return ids
+`)
+
+func attemptScore(delivery *WebhookDelivery) float64 {
+	if delivery.NextAttemptAt == nil {
+		return float64(delivery.CreatedAt.UnixMilli())
+	}
+
+	return float64(delivery.NextAttemptAt.UnixMilli())
+}
+
+// Schedule stores a new pending delivery. The tenant deliveries created before the retention duration are unindexed
+func (m *RedisWebhookManager) Schedule(delivery *WebhookDelivery) error {
+	ctx := context.Background()
+	if err := m.Update(delivery); err != nil {
+		return err
+	}
+
+	pipe := m.RDB.TxPipeline()
+	pipe.ZAdd(ctx, WebhookTenantKey(delivery.Tenant), &redis.Z{Score: float64(delivery.CreatedAt.UnixMilli()), Member: delivery.ID})
+	if m.Retention > 0 {
+		max := "(" + strconv.FormatInt(delivery.CreatedAt.Add(-m.Retention).UnixMilli(), 10)
+		pipe.ZRemRangeByScore(ctx, WebhookTenantKey(delivery.Tenant), "-inf", max)
+		pipe.ZRemRangeByScore(ctx, WebhookDeadLetterKey(delivery.Tenant), "-inf", max)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to index webhook delivery %s: %s", delivery.ID, err)
+	}
+
+	return nil
+}
+
+// Claim returns the pending deliveries due at the given time and postpones them by the lease duration
+func (m *RedisWebhookManager) Claim(now time.Time, limit int64, lease time.Duration) ([]WebhookDelivery, error) {
+	ctx := context.Background()
+	args := []interface{}{now.UnixMilli(), limit, now.Add(lease).UnixMilli()}
+	ids, err := claimDeliveriesScript.Run(ctx, m.RDB, []string{WebhookPendingKey}, args...).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %s", err)
+	}
+
+	deliveries, missing, err := m.getAll(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		if err := m.RDB.ZRem(ctx, WebhookPendingKey, missing...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to remove expired webhook deliveries: %s", err)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// Update stores the delivery and indexes it according to its status
+func (m *RedisWebhookManager) Update(delivery *WebhookDelivery) error {
+	value, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook delivery: %s", err)
+	}
+
+	ctx := context.Background()
+	pipe := m.RDB.TxPipeline()
+	switch delivery.Status {
+	case WebhookDeliveryPending:
+		pipe.Set(ctx, WebhookDeliveryKey(delivery.ID), value, 0)
+		pipe.ZAdd(ctx, WebhookPendingKey, &redis.Z{Score: attemptScore(delivery), Member: delivery.ID})
+		pipe.ZRem(ctx, WebhookDeadLetterKey(delivery.Tenant), delivery.ID)
+	case WebhookDeliveryDelivered, WebhookDeliveryDead:
+		pipe.Set(ctx, WebhookDeliveryKey(delivery.ID), value, m.Retention)
+		pipe.ZRem(ctx, WebhookPendingKey, delivery.ID)
+		if delivery.Status == WebhookDeliveryDead {
+			score := float64(time.Now().UnixMilli())
+			if delivery.LastAttemptAt != nil {
+				score = float64(delivery.LastAttemptAt.UnixMilli())
+			}
+
+			pipe.ZAdd(ctx, WebhookDeadLetterKey(delivery.Tenant), &redis.Z{Score: score, Member: delivery.ID})
+		}
+	default:
+		return fmt.Errorf("unknown webhook delivery status %s", delivery.Status)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store webhook delivery %s: %s", delivery.ID, err)
+	}
+
+	return nil
+}
+
+// Get retrieve a delivery from its identifier. It returns nil if the delivery does not exist
+func (m *RedisWebhookManager) Get(id string) (*WebhookDelivery, error) {
+	value, err := m.RDB.Get(context.Background(), WebhookDeliveryKey(id)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook delivery %s: %s", id, err)
+	}
+
+	delivery := &WebhookDelivery{}
+	if err := json.Unmarshal([]byte(value), delivery); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook delivery %s: %s", id, err)
+	}
+
+	return delivery, nil
+}
+
+// List returns the tenant deliveries, most recent first. Only the dead letters are returned if status is dead
+func (m *RedisWebhookManager) List(tenant string, status string, limit int64) ([]WebhookDelivery, error) {
+	ctx := context.Background()
+	index := WebhookTenantKey(tenant)
+	if status == WebhookDeliveryDead {
+		index = WebhookDeadLetterKey(tenant)
+	}
+
+	ids, err := m.RDB.ZRevRange(ctx, index, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %s", err)
+	}
+
+	deliveries, _, err := m.getAll(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		return deliveries, nil
+	}
+
+	filtered := []WebhookDelivery{}
+	for _, delivery := range deliveries {
+		if delivery.Status == status {
+			filtered = append(filtered, delivery)
+		}
+	}
+
+	return filtered, nil
+}
+
+// getAll retrieve the deliveries and the identifiers of the deliveries that no longer exist
+func (m *RedisWebhookManager) getAll(ctx context.Context, ids []string) ([]WebhookDelivery, []interface{}, error) {
+	deliveries := []WebhookDelivery{}
+	missing := []interface{}{}
+	if len(ids) == 0 {
+		return deliveries, missing, nil
+	}
+
+	keys := []string{}
+	for _, id := range ids {
+		keys = append(keys, WebhookDeliveryKey(id))
+	}
+
+	values, err := m.RDB.MGet(ctx, keys...).Result()
+	if utils.ComputeErr(err) != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve webhook deliveries: %s", err)
+	}
+
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			missing = append(missing, ids[i])
+			continue
+		}
+
+		delivery := WebhookDelivery{}
+		if err := json.Unmarshal([]byte(s), &delivery); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal webhook delivery %s: %s", ids[i], err)
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, missing, nil
+}
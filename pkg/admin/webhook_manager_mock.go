@@ -0,0 +1,45 @@
+// Package admin manages the bigblueswarm admin part
+package admin
+
+import "time"
+
+// WebhookManagerMock is a mock implementation of the WebhookManager interface
+type WebhookManagerMock struct{}
+
+var (
+	// ScheduleWebhookManagerMockFunc is the function that will be called when the mock webhook manager is used
+	ScheduleWebhookManagerMockFunc func(delivery *WebhookDelivery) error
+	// ClaimWebhookManagerMockFunc is the function that will be called when the mock webhook manager is used
+	ClaimWebhookManagerMockFunc func(now time.Time, limit int64, lease time.Duration) ([]WebhookDelivery, error)
+	// UpdateWebhookManagerMockFunc is the function that will be called when the mock webhook manager is used
+	UpdateWebhookManagerMockFunc func(delivery *WebhookDelivery) error
+	// GetWebhookManagerMockFunc is the function that will be called when the mock webhook manager is used
+	GetWebhookManagerMockFunc func(id string) (*WebhookDelivery, error)
+	// ListWebhookManagerMockFunc is the function that will be called when the mock webhook manager is used
+	ListWebhookManagerMockFunc func(tenant string, status string, limit int64) ([]WebhookDelivery, error)
+)
+
+// Schedule is a mock implementation that stores a new pending delivery
+func (m *WebhookManagerMock) Schedule(delivery *WebhookDelivery) error {
+	return ScheduleWebhookManagerMockFunc(delivery)
+}
+
+// Claim is a mock implementation that returns the due deliveries
+func (m *WebhookManagerMock) Claim(now time.Time, limit int64, lease time.Duration) ([]WebhookDelivery, error) {
+	return ClaimWebhookManagerMockFunc(now, limit, lease)
+}
+
+// Update is a mock implementation that stores a delivery
+func (m *WebhookManagerMock) Update(delivery *WebhookDelivery) error {
+	return UpdateWebhookManagerMockFunc(delivery)
+}
+
+// Get is a mock implementation that retrieve a delivery
+func (m *WebhookManagerMock) Get(id string) (*WebhookDelivery, error) {
+	return GetWebhookManagerMockFunc(id)
+}
+
+// List is a mock implementation that returns the tenant deliveries
+func (m *WebhookManagerMock) List(tenant string, status string, limit int64) ([]WebhookDelivery, error) {
+	return ListWebhookManagerMockFunc(tenant, status, limit)
+}
@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisWebhookManager(t *testing.T) {
+	mr := miniredis.RunT(t)
+	manager := NewWebhookManager(*redis.NewClient(&redis.Options{Addr: mr.Addr()}), &config.TenantWebhooksConfig{Retention: "24h"})
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	schedule := func(id string, tenant string, createdAt time.Time) {
+		assert.Nil(t, manager.Schedule(&WebhookDelivery{
+			ID:        id,
+			Tenant:    tenant,
+			URL:       "https://example.com/hook",
+			EventType: "meeting.created",
+			Status:    WebhookDeliveryPending,
+			CreatedAt: createdAt,
+		}))
+	}
+
+	t.Run("claim should return the due deliveries and postpone them", func(t *testing.T) {
+		schedule("first", "localhost", now.Add(-time.Minute))
+		schedule("second", "localhost", now)
+		schedule("later", "localhost", now.Add(time.Minute))
+
+		deliveries, err := manager.Claim(now, 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(deliveries))
+		assert.Equal(t, "first", deliveries[0].ID)
+
+		deliveries, _ = manager.Claim(now, 10, time.Minute)
+		assert.Equal(t, 0, len(deliveries))
+
+		deliveries, _ = manager.Claim(now.Add(time.Minute), 1, time.Minute)
+		assert.Equal(t, 1, len(deliveries))
+	})
+
+	t.Run("a finished delivery should leave the pending deliveries and expire", func(t *testing.T) {
+		delivery, err := manager.Get("first")
+		assert.Nil(t, err)
+		delivery.Status = WebhookDeliveryDelivered
+		assert.Nil(t, manager.Update(delivery))
+
+		score, _ := mr.ZScore(WebhookPendingKey, "first")
+		assert.Equal(t, float64(0), score)
+		assert.Equal(t, 24*time.Hour, mr.TTL(WebhookDeliveryKey("first")))
+	})
+
+	t.Run("a dead delivery should be added to the dead letter queue", func(t *testing.T) {
+		delivery, _ := manager.Get("second")
+		delivery.Status = WebhookDeliveryDead
+		delivery.Attempts = 8
+		delivery.LastAttemptAt = &now
+		assert.Nil(t, manager.Update(delivery))
+
+		dead, err := manager.List("localhost", WebhookDeliveryDead, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(dead))
+		assert.Equal(t, "second", dead[0].ID)
+		assert.Equal(t, 8, dead[0].Attempts)
+
+		delivery.Status = WebhookDeliveryPending
+		delivery.NextAttemptAt = &now
+		assert.Nil(t, manager.Update(delivery))
+		dead, _ = manager.List("localhost", WebhookDeliveryDead, 10)
+		assert.Equal(t, 0, len(dead))
+	})
+
+	t.Run("list should return the tenant deliveries most recent first", func(t *testing.T) {
+		schedule("other", "other.localhost", now)
+		deliveries, err := manager.List("localhost", "", 10)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(deliveries))
+		assert.Equal(t, "later", deliveries[0].ID)
+
+		delivered, _ := manager.List("localhost", WebhookDeliveryDelivered, 10)
+		assert.Equal(t, 1, len(delivered))
+		assert.Equal(t, "first", delivered[0].ID)
+	})
+
+	t.Run("get should return nil for an unknown delivery", func(t *testing.T) {
+		delivery, err := manager.Get("unknown")
+		assert.Nil(t, err)
+		assert.Nil(t, delivery)
+	})
+
+	t.Run("claim should unindex the expired deliveries", func(t *testing.T) {
+		mr.Del(WebhookDeliveryKey("other"))
+		deliveries, err := manager.Claim(now.Add(time.Hour), 10, time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(deliveries))
+		score, _ := mr.ZScore(WebhookPendingKey, "other")
+		assert.Equal(t, float64(0), score)
+	})
+
+	t.Run("an unknown status should return an error", func(t *testing.T) {
+		assert.NotNil(t, manager.Update(&WebhookDelivery{ID: "unknown", Status: "lost"}))
+	})
+}
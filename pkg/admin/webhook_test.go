@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/test_utils/pkg/request"
+	"github.com/bigblueswarm/test_utils/pkg/test"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListWebhookDeliveries(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+		return &Tenant{Spec: &TenantSpec{Host: "localhost"}}, nil
+	}
+
+	tests := []test.Test{
+		{
+			Name: "an invalid status should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "status=lost")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "an invalid limit should return a HTTP 400 - Bad Request",
+			Mock: func() {
+				request.SetRequestParams(c, "limit=1000")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			Name: "an error returned by webhook manager should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				request.SetRequestParams(c, "")
+				ListWebhookManagerMockFunc = func(tenant string, status string, limit int64) ([]WebhookDelivery, error) {
+					return nil, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a valid request should return the tenant deliveries",
+			Mock: func() {
+				request.SetRequestParams(c, "status=dead&limit=10")
+				ListWebhookManagerMockFunc = func(tenant string, status string, limit int64) ([]WebhookDelivery, error) {
+					assert.Equal(t, "localhost", tenant)
+					assert.Equal(t, WebhookDeliveryDead, status)
+					assert.Equal(t, int64(10), limit)
+					return []WebhookDelivery{{ID: "delivery", Tenant: "localhost", Status: WebhookDeliveryDead, Attempts: 8}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				list := &WebhookDeliveryList{}
+				json.Unmarshal(w.Body.Bytes(), list)
+				assert.Equal(t, "WebhookDeliveryList", list.Kind)
+				assert.Equal(t, 1, len(list.Deliveries))
+				assert.Equal(t, 8, list.Deliveries[0].Attempts)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "hostname", Value: "localhost"}}
+			test.Mock()
+			admin.ListWebhookDeliveries(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestRetryWebhookDelivery(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	var c *gin.Context
+	var updated *WebhookDelivery
+	var record *AuditRecord
+	admin := CreateAdmin(&InstanceManagerMock{}, &TenantManagerMock{}, &AuditManagerMock{}, &MeetingHistoryManagerMock{}, &WebhookManagerMock{}, &balancer.Mock{}, &config.Config{})
+	GetTenantTenantManagerMockFunc = func(hostname string) (*Tenant, error) {
+		return &Tenant{Spec: &TenantSpec{Host: "localhost"}}, nil
+	}
+
+	UpdateWebhookManagerMockFunc = func(delivery *WebhookDelivery) error {
+		updated = delivery
+		return nil
+	}
+
+	tests := []test.Test{
+		{
+			Name: "an unknown delivery should return a HTTP 404 - Not Found",
+			Mock: func() {
+				GetWebhookManagerMockFunc = func(id string) (*WebhookDelivery, error) {
+					return nil, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			Name: "a delivery of another tenant should return a HTTP 404 - Not Found",
+			Mock: func() {
+				GetWebhookManagerMockFunc = func(id string) (*WebhookDelivery, error) {
+					return &WebhookDelivery{ID: id, Tenant: "other.localhost", Status: WebhookDeliveryDead}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			Name: "a delivery not dead should return a HTTP 409 - Conflict",
+			Mock: func() {
+				GetWebhookManagerMockFunc = func(id string) (*WebhookDelivery, error) {
+					return &WebhookDelivery{ID: id, Tenant: "localhost", Status: WebhookDeliveryDelivered}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			Name: "an error returned by webhook manager should return a HTTP 500 - Internal Server Error",
+			Mock: func() {
+				GetWebhookManagerMockFunc = func(id string) (*WebhookDelivery, error) {
+					return nil, errors.New("manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			Name: "a dead delivery should be scheduled with a new attempts budget",
+			Mock: func() {
+				GetWebhookManagerMockFunc = func(id string) (*WebhookDelivery, error) {
+					return &WebhookDelivery{ID: id, Tenant: "localhost", Status: WebhookDeliveryDead, Attempts: 8}, nil
+				}
+				RecordAuditManagerMockFunc = func(r *AuditRecord) error {
+					record = r
+					return nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusAccepted, w.Code)
+				assert.Equal(t, "delivery", updated.ID)
+				assert.Equal(t, WebhookDeliveryPending, updated.Status)
+				assert.Equal(t, 0, updated.Attempts)
+				assert.NotNil(t, updated.NextAttemptAt)
+				assert.Equal(t, AuditRetryWebhookDelivery, record.Action)
+				assert.Equal(t, "webhook_delivery:delivery", record.Resource)
+				assert.Equal(t, WebhookDeliveryDead, record.Before.(*WebhookDelivery).Status)
+				assert.Equal(t, updated, record.After)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "hostname", Value: "localhost"}, {Key: "id", Value: "delivery"}}
+			test.Mock()
+			admin.RetryWebhookDelivery(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -9,25 +10,48 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"time"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/breaker"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/metrics"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// InstanceStatusError is returned when a BigBlueButton instance responds with an unexpected HTTP status code
+type InstanceStatusError struct {
+	Instance   string
+	Action     string
+	StatusCode int
+}
+
+func (e *InstanceStatusError) Error() string {
+	return fmt.Sprintf("%s instance responded to %s call with status %d", e.Instance, e.Action, e.StatusCode)
+}
+
 func (i *BigBlueButtonInstance) getLogger(action string, params string) *log.Entry {
 	return log.WithFields(log.Fields{
 		"instance": i.URL,
 		"action":   action,
-		"params":   params,
+		"params":   utils.RedactParams(params),
 	})
 }
 
 // Create execute a create api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) Create(params string) (*CreateResponse, error) {
+	return i.CreateWithContext(context.Background(), params)
+}
+
+// CreateWithContext execute a create api call on the remote BigBlueButton instance. The call is canceled when the
+// context is done
+func (i *BigBlueButtonInstance) CreateWithContext(ctx context.Context, params string) (*CreateResponse, error) {
 	logger := i.getLogger(Create, params)
-	response, err := i.api(Create, params)
+	response, err := i.apiWithContext(ctx, Create, params)
 
 	if err != nil {
 		logger.Error("api call to create method throws an error")
@@ -43,7 +67,37 @@ func (i *BigBlueButtonInstance) Create(params string) (*CreateResponse, error) {
 	return nil, e
 }
 
-func (i *BigBlueButtonInstance) callAPI(checksum *Checksum) ([]byte, error) {
+// readOnlyActions are the actions that do not change the instance state, so they can be retried on failure
+var readOnlyActions = map[string]bool{
+	GetMeetings:             true,
+	IsMeetingRunning:        true,
+	GetMeetingInfo:          true,
+	GetRecordings:           true,
+	GetRecordingsTextTracks: true,
+}
+
+func (i *BigBlueButtonInstance) callAPI(ctx context.Context, checksum *Checksum) ([]byte, error) {
+	if readOnlyActions[checksum.Action] {
+		ctx = restclient.WithRetry(ctx)
+	}
+
+	ctx, span := tracing.Start(ctx, "bigbluebutton "+checksum.Action,
+		trace.WithSpanKind(trace.SpanKindClient),
+		tracing.Attributes("bigbluebutton.instance", i.URL, "bigbluebutton.action", checksum.Action),
+	)
+
+	start := time.Now()
+	body, err := i.call(ctx, checksum)
+	tracing.End(span, err)
+	metrics.InstanceCallDuration.WithLabelValues(i.URL, checksum.Action).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.InstanceCallErrors.WithLabelValues(i.URL, checksum.Action).Inc()
+	}
+
+	return body, err
+}
+
+func (i *BigBlueButtonInstance) call(ctx context.Context, checksum *Checksum) ([]byte, error) {
 	logger := i.getLogger(checksum.Action, checksum.Params)
 	checksumValue, err := checksum.Process()
 	if err != nil {
@@ -51,14 +105,39 @@ func (i *BigBlueButtonInstance) callAPI(checksum *Checksum) ([]byte, error) {
 		return nil, err
 	}
 
+	if err := breaker.Breakers.Allow(i.URL); err != nil {
+		logger.Error(fmt.Sprintf("%s call on %s instance short-circuited", checksum.Action, i.URL), err)
+		return nil, err
+	}
+
 	url := i.URL + "/api/" + checksum.Action + "?" + checksum.Params + "&checksum=" + checksumValue
-	resp, err := restclient.Get(url)
-	if err != nil || resp.StatusCode != http.StatusOK {
+	resp, err := restclient.GetWithContext(ctx, url)
+	if err != nil {
 		logger.Error(fmt.Sprintf("calling %s action on %s instance throws an exception", checksum.Action, i.URL), err)
+		if errors.Is(err, context.Canceled) {
+			breaker.Breakers.Release(i.URL)
+		} else {
+			breaker.Breakers.Failure(i.URL)
+		}
+
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		breaker.Breakers.Failure(i.URL)
+	} else {
+		breaker.Breakers.Success(i.URL)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := &InstanceStatusError{Instance: i.URL, Action: checksum.Action, StatusCode: resp.StatusCode}
+		logger.Error(err)
+		return nil, err
+	}
 
 	return ioutil.ReadAll(resp.Body)
 }
@@ -72,11 +151,11 @@ func unMarshall(action string, body []byte, result *interface{}) error {
 	}
 }
 
-func (i *BigBlueButtonInstance) api(action string, params string) (interface{}, error) {
+func (i *BigBlueButtonInstance) apiWithContext(ctx context.Context, action string, params string) (interface{}, error) {
 	logger := i.getLogger(action, params)
 	checksum := CreateChecksum(i.Secret, action, params)
 
-	body, err := i.callAPI(checksum)
+	body, err := i.callAPI(ctx, checksum)
 	if err != nil {
 		logger.Error(fmt.Sprintf("failed to call %s instance %s api", i.URL, action), err)
 		return nil, err
@@ -136,8 +215,14 @@ func (i *BigBlueButtonInstance) GetJoinRedirectURL(params string) (string, error
 
 // Join execute a join api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) Join(params string) (*JoinRedirectResponse, error) {
+	return i.JoinWithContext(context.Background(), params)
+}
+
+// JoinWithContext execute a join api call on the remote BigBlueButton instance. The call is canceled when the context
+// is done
+func (i *BigBlueButtonInstance) JoinWithContext(ctx context.Context, params string) (*JoinRedirectResponse, error) {
 	logger := i.getLogger(Join, params)
-	response, err := i.api(Join, params)
+	response, err := i.apiWithContext(ctx, Join, params)
 
 	if err != nil {
 		logger.Error("api call to Join api failed", err)
@@ -155,8 +240,14 @@ func (i *BigBlueButtonInstance) Join(params string) (*JoinRedirectResponse, erro
 
 // End execute a end api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) End(params string) (*EndResponse, error) {
+	return i.EndWithContext(context.Background(), params)
+}
+
+// EndWithContext execute a end api call on the remote BigBlueButton instance. The call is canceled when the context is
+// done
+func (i *BigBlueButtonInstance) EndWithContext(ctx context.Context, params string) (*EndResponse, error) {
 	logger := i.getLogger(End, params)
-	response, err := i.api(End, params)
+	response, err := i.apiWithContext(ctx, End, params)
 
 	if err != nil {
 		logger.Error("api call to End api failed", err)
@@ -174,8 +265,14 @@ func (i *BigBlueButtonInstance) End(params string) (*EndResponse, error) {
 
 // IsMeetingRunning checks if a meeting is running on the remote Bigbluebutton instance
 func (i *BigBlueButtonInstance) IsMeetingRunning(params string) (*IsMeetingsRunningResponse, error) {
+	return i.IsMeetingRunningWithContext(context.Background(), params)
+}
+
+// IsMeetingRunningWithContext checks if a meeting is running on the remote Bigbluebutton instance. The call is canceled
+// when the context is done
+func (i *BigBlueButtonInstance) IsMeetingRunningWithContext(ctx context.Context, params string) (*IsMeetingsRunningResponse, error) {
 	logger := i.getLogger(IsMeetingRunning, params)
-	response, err := i.api(IsMeetingRunning, params)
+	response, err := i.apiWithContext(ctx, IsMeetingRunning, params)
 
 	if err != nil {
 		logger.Error("api call to IsMeetingRunning api failed", err)
@@ -193,8 +290,14 @@ func (i *BigBlueButtonInstance) IsMeetingRunning(params string) (*IsMeetingsRunn
 
 // GetMeetingInfo execute a get meeting info api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) GetMeetingInfo(params string) (*GetMeetingInfoResponse, error) {
+	return i.GetMeetingInfoWithContext(context.Background(), params)
+}
+
+// GetMeetingInfoWithContext execute a get meeting info api call on the remote BigBlueButton instance. The call is
+// canceled when the context is done
+func (i *BigBlueButtonInstance) GetMeetingInfoWithContext(ctx context.Context, params string) (*GetMeetingInfoResponse, error) {
 	logger := i.getLogger(GetMeetingInfo, params)
-	response, err := i.api(GetMeetingInfo, params)
+	response, err := i.apiWithContext(ctx, GetMeetingInfo, params)
 
 	if err != nil {
 		logger.Error("api call to GetMeetingInfo api failed", err)
@@ -212,8 +315,14 @@ func (i *BigBlueButtonInstance) GetMeetingInfo(params string) (*GetMeetingInfoRe
 
 // GetMeetings execute a get meetings api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) GetMeetings() (*GetMeetingsResponse, error) {
+	return i.GetMeetingsWithContext(context.Background())
+}
+
+// GetMeetingsWithContext execute a get meetings api call on the remote BigBlueButton instance. The call is canceled when
+// the context is done
+func (i *BigBlueButtonInstance) GetMeetingsWithContext(ctx context.Context) (*GetMeetingsResponse, error) {
 	logger := i.getLogger(GetMeetings, "")
-	response, err := i.api(GetMeetings, "")
+	response, err := i.apiWithContext(ctx, GetMeetings, "")
 
 	if err != nil {
 		logger.Error("api call to GetMeetings api failed", err)
@@ -231,8 +340,14 @@ func (i *BigBlueButtonInstance) GetMeetings() (*GetMeetingsResponse, error) {
 
 // GetRecordings perform a get recordings api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) GetRecordings(params string) (*GetRecordingsResponse, error) {
+	return i.GetRecordingsWithContext(context.Background(), params)
+}
+
+// GetRecordingsWithContext perform a get recordings api call on the remote BigBlueButton instance. The call is canceled
+// when the context is done
+func (i *BigBlueButtonInstance) GetRecordingsWithContext(ctx context.Context, params string) (*GetRecordingsResponse, error) {
 	logger := i.getLogger(GetRecordings, params)
-	response, err := i.api(GetRecordings, params)
+	response, err := i.apiWithContext(ctx, GetRecordings, params)
 
 	if err != nil {
 		logger.Error("api call to GetRecordings api failed", err)
@@ -250,8 +365,14 @@ func (i *BigBlueButtonInstance) GetRecordings(params string) (*GetRecordingsResp
 
 // UpdateRecordings perform a update recordings api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) UpdateRecordings(params string) (*UpdateRecordingsResponse, error) {
+	return i.UpdateRecordingsWithContext(context.Background(), params)
+}
+
+// UpdateRecordingsWithContext perform a update recordings api call on the remote BigBlueButton instance. The call is
+// canceled when the context is done
+func (i *BigBlueButtonInstance) UpdateRecordingsWithContext(ctx context.Context, params string) (*UpdateRecordingsResponse, error) {
 	logger := i.getLogger(UpdateRecordings, params)
-	response, err := i.api(UpdateRecordings, params)
+	response, err := i.apiWithContext(ctx, UpdateRecordings, params)
 
 	if err != nil {
 		logger.Error("api call to UpdateRecordings api failed", err)
@@ -269,8 +390,14 @@ func (i *BigBlueButtonInstance) UpdateRecordings(params string) (*UpdateRecordin
 
 // DeleteRecordings perform a delete recordings api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) DeleteRecordings(params string) (*DeleteRecordingsResponse, error) {
+	return i.DeleteRecordingsWithContext(context.Background(), params)
+}
+
+// DeleteRecordingsWithContext perform a delete recordings api call on the remote BigBlueButton instance. The call is
+// canceled when the context is done
+func (i *BigBlueButtonInstance) DeleteRecordingsWithContext(ctx context.Context, params string) (*DeleteRecordingsResponse, error) {
 	logger := i.getLogger(DeleteRecordings, params)
-	response, err := i.api(DeleteRecordings, params)
+	response, err := i.apiWithContext(ctx, DeleteRecordings, params)
 
 	if err != nil {
 		logger.Error("api call to DeleteRecordings api failed", err)
@@ -288,8 +415,14 @@ func (i *BigBlueButtonInstance) DeleteRecordings(params string) (*DeleteRecordin
 
 // PublishRecordings perform a publish recordings api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) PublishRecordings(params string) (*PublishRecordingsResponse, error) {
+	return i.PublishRecordingsWithContext(context.Background(), params)
+}
+
+// PublishRecordingsWithContext perform a publish recordings api call on the remote BigBlueButton instance. The call is
+// canceled when the context is done
+func (i *BigBlueButtonInstance) PublishRecordingsWithContext(ctx context.Context, params string) (*PublishRecordingsResponse, error) {
 	logger := i.getLogger(PublishRecordings, params)
-	response, err := i.api(PublishRecordings, params)
+	response, err := i.apiWithContext(ctx, PublishRecordings, params)
 
 	if err != nil {
 		logger.Error("api call to PublishRecordings api failed", err)
@@ -307,8 +440,14 @@ func (i *BigBlueButtonInstance) PublishRecordings(params string) (*PublishRecord
 
 // GetRecordingTextTracks perform a get recording text tracks api call on the remote BigBlueButton instance
 func (i *BigBlueButtonInstance) GetRecordingTextTracks(params string) (*GetRecordingsTextTracksResponse, error) {
+	return i.GetRecordingTextTracksWithContext(context.Background(), params)
+}
+
+// GetRecordingTextTracksWithContext perform a get recording text tracks api call on the remote BigBlueButton instance.
+// The call is canceled when the context is done
+func (i *BigBlueButtonInstance) GetRecordingTextTracksWithContext(ctx context.Context, params string) (*GetRecordingsTextTracksResponse, error) {
 	logger := i.getLogger(GetRecordingsTextTracks, params)
-	response, err := i.api(GetRecordingsTextTracks, params)
+	response, err := i.apiWithContext(ctx, GetRecordingsTextTracks, params)
 
 	if err != nil {
 		logger.Error("api call to GetRecordingsTextTracks api failed", err)
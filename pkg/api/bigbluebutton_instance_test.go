@@ -2,15 +2,20 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/breaker"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
 	"github.com/bigblueswarm/test_utils/pkg/test"
 	"github.com/gin-gonic/gin"
@@ -132,6 +137,82 @@ func TestCreate(t *testing.T) {
 	executeTests(t, "Create", tests)
 }
 
+func TestCallAPIStatusError(t *testing.T) {
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte{})),
+		}, nil
+	}
+
+	instance := &BigBlueButtonInstance{URL: "http://localhost/bigbluebutton", Secret: test.DefaultSecret()}
+	_, err := instance.GetMeetings()
+	var statusErr *InstanceStatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	assert.Equal(t, GetMeetings, statusErr.Action)
+	assert.Equal(t, "http://localhost/bigbluebutton instance responded to getMeetings call with status 503", err.Error())
+}
+
+func TestCallAPICircuitBreaker(t *testing.T) {
+	breaker.Init(&config.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: "1m"})
+	defer breaker.Init(&config.CircuitBreakerConfig{})
+	calls := 0
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	}
+
+	instance := &BigBlueButtonInstance{URL: "http://localhost/bigbluebutton", Secret: test.DefaultSecret()}
+	for i := 0; i < 3; i++ {
+		_, err := instance.GetMeetings()
+		assert.NotNil(t, err)
+	}
+
+	_, err := instance.GetMeetings()
+	assert.True(t, errors.Is(err, breaker.ErrOpen))
+	assert.Equal(t, 2, calls)
+}
+
+func TestCallAPICanceledProbe(t *testing.T) {
+	breaker.Init(&config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: "1m"})
+	defer breaker.Init(&config.CircuitBreakerConfig{})
+	now := time.Now()
+	breaker.Breakers.Now = func() time.Time { return now }
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	instance := &BigBlueButtonInstance{URL: "http://localhost/bigbluebutton", Secret: test.DefaultSecret()}
+	instance.GetMeetings()
+	now = now.Add(time.Minute)
+	assert.Equal(t, breaker.HalfOpen, breaker.Breakers.State(instance.URL))
+
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, context.Canceled
+	}
+
+	_, err := instance.GetMeetings()
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, breaker.HalfOpen, breaker.Breakers.State(instance.URL))
+}
+
+func TestCallAPIContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	}
+
+	instance := &BigBlueButtonInstance{URL: "http://localhost/bigbluebutton", Secret: test.DefaultSecret()}
+	_, err := instance.CreateWithContext(ctx, "name=doe&meetingID=id")
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = instance.JoinWithContext(ctx, "meetingID=id")
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = instance.EndWithContext(ctx, "meetingID=id")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
 func TestGetJoinRedirectURL(t *testing.T) {
 	t.Run("Valid join call should return a valid join redirect url", func(t *testing.T) {
 		params := fmt.Sprintf("meetingID=%s&fullName=Simon&password=pwd", meetingID)
@@ -429,3 +510,29 @@ func TestRedirect(t *testing.T) {
 		})
 	}
 }
+
+func TestCallAPIRetry(t *testing.T) {
+	restclient.Client = &restclient.RetryClient{Client: &restclient.Mock{}, Retries: 1, Backoff: time.Millisecond}
+	defer func() {
+		restclient.Client = &restclient.Mock{}
+	}()
+
+	calls := 0
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	}
+
+	instance := &BigBlueButtonInstance{URL: "http://localhost/bigbluebutton", Secret: test.DefaultSecret()}
+	t.Run("a read only action should be retried", func(t *testing.T) {
+		calls = 0
+		instance.GetMeetings()
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("an action changing the instance state should not be retried", func(t *testing.T) {
+		calls = 0
+		instance.Create("name=doe&meetingID=id")
+		assert.Equal(t, 1, calls)
+	})
+}
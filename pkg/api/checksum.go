@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // StringToSHA1 returns the string value hashed with SHA1 algorithm
@@ -29,7 +30,78 @@ func (c *Checksum) Process() (string, error) {
 	return StringToSHA1(c.Value())
 }
 
-// SetTenantMetadata set metadata tenant for the context
+// SetTenantMetadata set metadata tenant for the context. A tenant metadata provided by the caller is replaced so a
+// tenant can't tag its meetings and recordings as owned by another tenant
 func (c *Checksum) SetTenantMetadata(host string) {
-	c.Params = fmt.Sprintf("%s&meta_bigblueswarm-tenant=%s", c.Params, url.QueryEscape(host))
+	c.SetParam("meta_"+TenantMetadata, host)
+}
+
+func paramKey(param string) string {
+	key, _, _ := strings.Cut(param, "=")
+	if unescaped, err := url.QueryUnescape(key); err == nil {
+		return unescaped
+	}
+
+	return key
+}
+
+func (c *Checksum) params() []string {
+	if c.Params == "" {
+		return []string{}
+	}
+
+	return strings.Split(c.Params, "&")
+}
+
+// HasParam check if the checksum params contain the given parameter
+func (c *Checksum) HasParam(key string) bool {
+	for _, param := range c.params() {
+		if paramKey(param) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetParam returns the first value of the given parameter and tells if the parameter exists
+func (c *Checksum) GetParam(key string) (string, bool) {
+	for _, param := range c.params() {
+		if paramKey(param) != key {
+			continue
+		}
+
+		_, value, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			return unescaped, true
+		}
+
+		return value, true
+	}
+
+	return "", false
+}
+
+// DelParam remove all the occurrences of the given parameter from the checksum params
+func (c *Checksum) DelParam(key string) {
+	params := []string{}
+	for _, param := range c.params() {
+		if paramKey(param) != key {
+			params = append(params, param)
+		}
+	}
+
+	c.Params = strings.Join(params, "&")
+}
+
+// SetParam set the parameter value, replacing any existing value. Other parameters order is preserved
+func (c *Checksum) SetParam(key string, value string) {
+	c.DelParam(key)
+	param := fmt.Sprintf("%s=%s", url.QueryEscape(key), url.QueryEscape(value))
+	if c.Params == "" {
+		c.Params = param
+		return
+	}
+
+	c.Params = fmt.Sprintf("%s&%s", c.Params, param)
 }
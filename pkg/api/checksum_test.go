@@ -62,4 +62,59 @@ func TestChecksumSetTenant(t *testing.T) {
 
 	checksum.SetTenantMetadata("bbb.localhost.com")
 	assert.Equal(t, "param=value&meta_bigblueswarm-tenant=bbb.localhost.com", checksum.Params)
+
+	t.Run("a tenant metadata provided by the caller should be replaced", func(t *testing.T) {
+		checksum := &Checksum{
+			Params: "meta_bigblueswarm-tenant=other.localhost.com&param=value&meta%5Fbigblueswarm-tenant=another.localhost.com",
+		}
+
+		checksum.SetTenantMetadata("bbb.localhost.com")
+		assert.Equal(t, "param=value&meta_bigblueswarm-tenant=bbb.localhost.com", checksum.Params)
+	})
+}
+
+func TestChecksumParams(t *testing.T) {
+	t.Run("HasParam should check if the parameter exists", func(t *testing.T) {
+		checksum := &Checksum{Params: "name=test&meta_bbb%2Dorigin=greenlight&record"}
+		assert.True(t, checksum.HasParam("name"))
+		assert.True(t, checksum.HasParam("meta_bbb-origin"))
+		assert.True(t, checksum.HasParam("record"))
+		assert.False(t, checksum.HasParam("welcome"))
+	})
+
+	t.Run("GetParam should return the first parameter value", func(t *testing.T) {
+		checksum := &Checksum{Params: "name=Hello+world%21&duration=60&duration=30&record"}
+		value, exists := checksum.GetParam("name")
+		assert.True(t, exists)
+		assert.Equal(t, "Hello world!", value)
+		value, _ = checksum.GetParam("duration")
+		assert.Equal(t, "60", value)
+		value, exists = checksum.GetParam("record")
+		assert.True(t, exists)
+		assert.Equal(t, "", value)
+		_, exists = checksum.GetParam("welcome")
+		assert.False(t, exists)
+	})
+
+	t.Run("DelParam should remove all parameter occurrences", func(t *testing.T) {
+		checksum := &Checksum{Params: "name=test&record=true&meetingID=1&record=false"}
+		checksum.DelParam("record")
+		assert.Equal(t, "name=test&meetingID=1", checksum.Params)
+		checksum.DelParam("name")
+		checksum.DelParam("meetingID")
+		assert.Equal(t, "", checksum.Params)
+	})
+
+	t.Run("SetParam should replace the parameter value", func(t *testing.T) {
+		checksum := &Checksum{Params: "record=true&name=test"}
+		checksum.SetParam("record", "false")
+		checksum.SetParam("welcome", "Hello world!")
+		assert.Equal(t, "name=test&record=false&welcome=Hello+world%21", checksum.Params)
+	})
+
+	t.Run("SetParam on empty params should not add a separator", func(t *testing.T) {
+		checksum := &Checksum{}
+		checksum.SetParam("name", "test")
+		assert.Equal(t, "name=test", checksum.Params)
+	})
 }
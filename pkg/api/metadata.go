@@ -0,0 +1,60 @@
+// Package api manage the bigbluebutton api and communication between bigblueswarm and bigbluebutton instances
+package api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// TenantMetadata is the metadata key used to store the tenant owning a meeting or a recording
+const TenantMetadata = "bigblueswarm-tenant"
+
+// MeetingIDMetadata is the metadata key used to store the tenant meeting identifier of a namespaced meeting
+const MeetingIDMetadata = "bigblueswarm-meeting-id"
+
+func parseMetadata(inner []byte) map[string]string {
+	metadata := map[string]string{}
+	decoder := xml.NewDecoder(bytes.NewReader(inner))
+	key := ""
+	value := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return metadata
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			key = t.Name.Local
+			value = ""
+		case xml.CharData:
+			value += string(t)
+		case xml.EndElement:
+			if key != "" {
+				metadata[key] = strings.TrimSpace(value)
+			}
+			key = ""
+		}
+	}
+}
+
+// Metadata returns the meeting metadata as a map
+func (m *MeetingInfo) Metadata() map[string]string {
+	return parseMetadata(m.MetaData.Inner)
+}
+
+// Tenant returns the tenant stored in the meeting metadata
+func (m *MeetingInfo) Tenant() string {
+	return m.Metadata()[TenantMetadata]
+}
+
+// Metadata returns the recording metadata as a map
+func (r *Recording) Metadata() map[string]string {
+	return parseMetadata(r.MetaData.Inner)
+}
+
+// Tenant returns the tenant stored in the recording metadata
+func (r *Recording) Tenant() string {
+	return r.Metadata()[TenantMetadata]
+}
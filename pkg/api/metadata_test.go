@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeetingInfoMetadata(t *testing.T) {
+	meeting := &MeetingInfo{}
+	meeting.MetaData.Inner = []byte("<bigblueswarm-tenant>localhost</bigblueswarm-tenant><bbb-origin> greenlight </bbb-origin>")
+
+	t.Run("metadata should be parsed as a map", func(t *testing.T) {
+		assert.Equal(t, map[string]string{
+			TenantMetadata: "localhost",
+			"bbb-origin":   "greenlight",
+		}, meeting.Metadata())
+	})
+
+	t.Run("tenant should be extracted from metadata", func(t *testing.T) {
+		assert.Equal(t, "localhost", meeting.Tenant())
+	})
+
+	t.Run("empty metadata should return an empty tenant", func(t *testing.T) {
+		assert.Equal(t, "", (&MeetingInfo{}).Tenant())
+	})
+}
+
+func TestRecordingMetadata(t *testing.T) {
+	recording := &Recording{}
+	recording.MetaData.Inner = []byte("<isBreakout>false</isBreakout><bigblueswarm-tenant>localhost</bigblueswarm-tenant>")
+
+	assert.Equal(t, "false", recording.Metadata()["isBreakout"])
+	assert.Equal(t, "localhost", recording.Tenant())
+}
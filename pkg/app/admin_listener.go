@@ -0,0 +1,61 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// adminRouter returns the router serving the admin API: the dedicated admin router if an admin address is configured,
+// the main router otherwise
+func (s *Server) adminRouter() *gin.Engine {
+	if s.AdminRouter != nil {
+		return s.AdminRouter
+	}
+
+	return s.Router
+}
+
+// initAdminAllowlist parses the addresses allowed to consume the admin API. The allowlist applies to all the routes of
+// the dedicated admin listener, or to the admin API routes only if it is served by the main listener
+func (s *Server) initAdminAllowlist() error {
+	networks, err := utils.ParseNetworks(s.Config.Admin.AllowedIPs)
+	if err != nil {
+		return fmt.Errorf("failed to parse admin allowed IPs: %s", err)
+	}
+
+	s.adminNetworks = networks
+	if s.AdminRouter != nil {
+		s.AdminRouter.Use(s.checkAdminAllowlist)
+	}
+
+	return nil
+}
+
+// adminMiddlewares returns the middlewares added to the admin API routes in front of the admin authentication
+func (s *Server) adminMiddlewares() []gin.HandlerFunc {
+	if s.AdminRouter != nil {
+		return []gin.HandlerFunc{}
+	}
+
+	return []gin.HandlerFunc{s.checkAdminAllowlist}
+}
+
+// checkAdminAllowlist rejects the requests whose client address is not allowed to consume the admin API. The client
+// address is read from the forwarded headers only if the request comes from a trusted proxy
+func (s *Server) checkAdminAllowlist(c *gin.Context) {
+	if len(s.adminNetworks) > 0 && !utils.NetworksContain(s.adminNetworks, c.ClientIP()) {
+		log.WithFields(log.Fields{
+			"client_ip": c.ClientIP(),
+			"path":      c.Request.URL.Path,
+		}).Warn("client address is not allowed to consume the admin API")
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	c.Next()
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitAdminAllowlist(t *testing.T) {
+	server := &Server{Config: &config.Config{Admin: config.AdminConfig{AllowedIPs: []string{"invalid"}}}}
+	err := server.initAdminAllowlist()
+	assert.Equal(t, "failed to parse admin allowed IPs: invalid address invalid", err.Error())
+}
+
+func TestCheckAdminAllowlist(t *testing.T) {
+	type test struct {
+		name       string
+		address    string
+		allowedIPs []string
+		remoteAddr string
+		path       string
+		expected   int
+	}
+
+	tests := []test{
+		{
+			name:       "all addresses should be allowed if no allowlist is configured",
+			remoteAddr: "192.168.1.1:4567",
+			path:       "/admin/api/instances",
+			expected:   http.StatusUnauthorized,
+		},
+		{
+			name:       "an allowed address should reach the admin authentication",
+			allowedIPs: []string{"10.0.0.0/8"},
+			remoteAddr: "10.1.2.3:4567",
+			path:       "/admin/api/instances",
+			expected:   http.StatusUnauthorized,
+		},
+		{
+			name:       "a forbidden address should be rejected",
+			allowedIPs: []string{"10.0.0.0/8"},
+			remoteAddr: "192.168.1.1:4567",
+			path:       "/admin/api/instances",
+			expected:   http.StatusForbidden,
+		},
+		{
+			name:       "the allowlist should not apply to the main listener routes if no admin address is configured",
+			allowedIPs: []string{"10.0.0.0/8"},
+			remoteAddr: "192.168.1.1:4567",
+			path:       "/healthz",
+			expected:   http.StatusOK,
+		},
+		{
+			name:       "the allowlist should apply to all the admin listener routes",
+			address:    "127.0.0.1:8091",
+			allowedIPs: []string{"10.0.0.0/8"},
+			remoteAddr: "192.168.1.1:4567",
+			path:       "/healthz",
+			expected:   http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewServer(&config.Config{Admin: config.AdminConfig{
+				APIKey:     "admin_key",
+				Address:    test.address,
+				AllowedIPs: test.allowedIPs,
+			}})
+			assert.Nil(t, server.initAdminAllowlist())
+			server.initRoutes()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.RemoteAddr = test.remoteAddr
+			server.adminRouter().ServeHTTP(w, req)
+			assert.Equal(t, test.expected, w.Code)
+		})
+	}
+}
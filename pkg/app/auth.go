@@ -2,15 +2,20 @@
 package app
 
 import (
+	"context"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func processParameters(query string) string {
@@ -18,25 +23,53 @@ func processParameters(query string) string {
 	return reg.ReplaceAllString(query, "")
 }
 
+// validateChecksum returns the checksum matching the request checksum value computed with one of the tenant secrets.
+// It returns nil if no secret matches
+func (s *Server) validateChecksum(ctx context.Context, tenant *admin.Tenant, action string, params string, value string) (checksum *api.Checksum, err error) {
+	_, span := tracing.Start(ctx, "checksum.validate", tracing.Attributes("tenant", tenant.Spec.Host, "action", action))
+	defer func() {
+		span.SetAttributes(attribute.Bool("checksum.valid", checksum != nil))
+		tracing.End(span, err)
+	}()
+
+	for _, secret := range tenant.Secrets(s.Config.BigBlueSwarm.Secret, time.Now()) {
+		candidate := &api.Checksum{
+			Secret: secret,
+			Action: action,
+			Params: params,
+		}
+
+		sha, err := candidate.Process()
+		if err != nil {
+			return nil, err
+		}
+
+		if value == sha {
+			return candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // ChecksumValidation handler validate all requests checksum and returns an error if the checksum is not int the request or if the checksum is invalid
 func (s *Server) ChecksumValidation(c *gin.Context) {
 	error := api.DefaultChecksumError()
 
 	checksumParam, exists := c.GetQuery("checksum")
 	if !exists {
-		log.Warn("checksum not found in request")
+		getLogger(c).Warn("checksum not found in request")
 		c.XML(http.StatusOK, error)
 		c.Abort()
 		return
 	}
 
-	logger := log.WithFields(log.Fields{
+	logger := getLogger(c).Dup().WithFields(log.Fields{
 		"checksum": checksumParam,
 		"tenant":   utils.GetHost(c),
 	})
 
-	secret := s.Config.BigBlueSwarm.Secret
-	tenant, err := s.TenantManager.GetTenant(utils.GetHost(c))
+	tenant, err := s.resolveTenant(c)
 	if err != nil {
 		logger.Error("tenant manager can't retrieve tenant: ", err)
 		c.AbortWithStatus(http.StatusInternalServerError)
@@ -49,36 +82,30 @@ func (s *Server) ChecksumValidation(c *gin.Context) {
 		return
 	}
 
-	if tenant.Spec.Secret != "" {
-		secret = tenant.Spec.Secret
-	}
-
-	checksum := &api.Checksum{
-		Secret: secret,
-		Action: strings.TrimPrefix(c.FullPath(), "/bigbluebutton/api/"),
-		Params: processParameters(c.Request.URL.RawQuery),
-	}
-
-	sha, err := checksum.Process()
-
+	params := processParameters(c.Request.URL.RawQuery)
+	action := strings.TrimPrefix(c.FullPath(), "/bigbluebutton/api/")
+	checksum, err := s.validateChecksum(c.Request.Context(), tenant, action, params, checksumParam)
 	if err != nil {
 		logger.Error("failed to process checksum validation", err)
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
 
-	if checksumParam != string(sha) {
+	if checksum == nil {
 		logger.WithFields(log.Fields{
-			"tenant":         utils.GetHost(c),
-			"checksum":       checksumParam,
-			"expected_value": string(sha),
-			"params":         checksum.Params,
+			"tenant":   utils.GetHost(c),
+			"checksum": checksumParam,
+			"params":   utils.RedactParams(params),
 		}).Warn("checksum does not pass the checksum validation")
 		c.XML(http.StatusOK, error)
 		c.Abort()
 		return
 	}
 
+	fingerprint := admin.SecretFingerprint(checksum.Secret)
+	getLogger(c).AddField("secret", fingerprint)
+
+	s.secretUsage.track(tenant.Spec.Host, fingerprint, time.Now())
 	setAPIContext(c, checksum)
 
 	c.Next()
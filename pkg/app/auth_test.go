@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
@@ -25,6 +26,11 @@ func TestChecksumValidation(t *testing.T) {
 		},
 	})
 	server.TenantManager = &admin.TenantManagerMock{}
+	trackedSecret := ""
+	admin.TrackSecretUsageTenantManagerMockFunc = func(hostname string, fingerprint string, count int64, lastUsed time.Time) error {
+		trackedSecret = fingerprint
+		return nil
+	}
 
 	tests := []test.Test{
 		{
@@ -45,7 +51,7 @@ func TestChecksumValidation(t *testing.T) {
 			Name: "An error returned by tenant manager should returns 500 status code",
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return nil, errors.New("tenant manager error")
 				}
 				request.SetRequestParams(c, "name=simon&checksum=invalid_checksum")
@@ -59,7 +65,7 @@ func TestChecksumValidation(t *testing.T) {
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
 				request.SetRequestParams(c, "name=simon&checksum=checksum")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return nil, nil
 				}
 			},
@@ -71,7 +77,7 @@ func TestChecksumValidation(t *testing.T) {
 			Name: "An invalid checksum should returns 200 with checksum error",
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -94,7 +100,7 @@ func TestChecksumValidation(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, "name=simon&checksum=f0ce59033b7468b690112cd2e715c698e35c8e2b")
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host:   "localhost",
@@ -113,7 +119,7 @@ func TestChecksumValidation(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, "name=simon&checksum=8f0378b9dbb7967c7069c418062d4f486b951b6f")
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -126,6 +132,53 @@ func TestChecksumValidation(t *testing.T) {
 				assert.Equal(t, w.Body.String(), "") //Next handler returns an empty string
 			},
 		},
+		{
+			Name: "A valid secondary secret checksum should returns 200 code and track the secondary secret usage",
+			Mock: func() {
+				request.SetRequestParams(c, "name=simon&checksum=f0ce59033b7468b690112cd2e715c698e35c8e2b")
+				request.SetRequestHost(c, "localhost")
+				server.secretUsage.take()
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{
+						Spec: &admin.TenantSpec{
+							Host:   "localhost",
+							Secret: "mynewsecret",
+							SecondarySecrets: []admin.SecondarySecret{
+								{Secret: "mydummysecret", ExpiresAt: time.Now().Add(time.Hour)},
+							},
+						},
+					}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				server.flushSecretUsage()
+				assert.Equal(t, admin.SecretFingerprint("mydummysecret"), trackedSecret)
+			},
+		},
+		{
+			Name: "An expired secondary secret checksum should returns 200 with checksum error",
+			Mock: func() {
+				request.SetRequestParams(c, "name=simon&checksum=f0ce59033b7468b690112cd2e715c698e35c8e2b")
+				request.SetRequestHost(c, "localhost")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{
+						Spec: &admin.TenantSpec{
+							Host:   "localhost",
+							Secret: "mynewsecret",
+							SecondarySecrets: []admin.SecondarySecret{
+								{Secret: "mydummysecret", ExpiresAt: time.Now().Add(-time.Hour)},
+							},
+						},
+					}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				var response api.Error
+				xml.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, "checksumError", response.MessageKey)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -5,36 +5,37 @@ import (
 	"fmt"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
-	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 )
 
-func isPoolReached(b balancer.Balancer, m string, f string, p int64) (bool, error) {
-	state, err := b.GetCurrentState(m, f)
-	if err != nil {
-		return false, err
+func poolLimit(pool *int64) int64 {
+	if pool == nil {
+		return Unlimited
 	}
 
-	return state < p, nil
+	return *pool
 }
 
-func (s *Server) isTenantLowerThanMeetingPool(t *admin.Tenant) (bool, error) {
-	measurement := fmt.Sprintf("bigbluebutton:%s", t.Spec.Host)
-	field := "meetings"
-	reached, err := isPoolReached(s.Balancer, measurement, field, *t.Spec.MeetingsPool)
+func (s *Server) acquireTenantMeeting(t *admin.Tenant, meetingID string) (MeetingAcquisition, error) {
+	acquisition, err := s.Pool.AcquireMeeting(t.Spec.Host, meetingID, poolLimit(t.Spec.MeetingsPool))
 	if err != nil {
-		return false, fmt.Errorf("failed to check tenant state for tenant %s: %s", t.Spec.Host, err)
+		return MeetingPoolFull, fmt.Errorf("failed to acquire meeting for tenant %s: %s", t.Spec.Host, err)
 	}
 
-	return reached, nil
+	return acquisition, nil
 }
 
-func (s *Server) isTenantLowerThanUserPool(t *admin.Tenant) (bool, error) {
-	measurement := fmt.Sprintf("bigbluebutton:%s", t.Spec.Host)
-	field := "participants"
-	reached, err := isPoolReached(s.Balancer, measurement, field, *t.Spec.UserPool)
+func (s *Server) acquireTenantParticipant(t *admin.Tenant, meetingID string) (bool, error) {
+	acquired, err := s.Pool.AcquireParticipant(t.Spec.Host, meetingID, poolLimit(t.Spec.UserPool))
 	if err != nil {
-		return false, fmt.Errorf("failed to check tenant state for tenant %s: %s", t.Spec.Host, err)
+		return false, fmt.Errorf("failed to acquire participant for tenant %s: %s", t.Spec.Host, err)
 	}
 
-	return reached, nil
+	return acquired, nil
+}
+
+func (s *Server) releaseTenantMeeting(logger *utils.RequestLogger, tenant string, meetingID string) {
+	if err := s.Pool.ReleaseMeeting(tenant, meetingID); err != nil {
+		logger.Errorln("failed to release meeting from tenant pool", err)
+	}
 }
@@ -5,27 +5,26 @@ import (
 	"testing"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
-	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
 	"github.com/bigblueswarm/test_utils/pkg/test"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestIsTenantLowerThanMeetingPool(t *testing.T) {
-	mp := int64(0)
+func TestAcquireTenantMeeting(t *testing.T) {
+	var limit int64
 	tenant := &admin.Tenant{
 		Spec: &admin.TenantSpec{
-			MeetingsPool: &mp,
+			Host: "localhost",
 		},
 	}
 	server := NewServer(&config.Config{})
-	server.Balancer = &balancer.Mock{}
+	server.Pool = &PoolMock{}
 	tests := []test.Test{
 		{
-			Name: "an error returned by balancer should be returned",
+			Name: "an error returned by pool should be returned",
 			Mock: func() {
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 0, errors.New("balancer error")
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, l int64) (MeetingAcquisition, error) {
+					return MeetingPoolFull, errors.New("pool error")
 				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
@@ -33,54 +32,61 @@ func TestIsTenantLowerThanMeetingPool(t *testing.T) {
 			},
 		},
 		{
-			Name: "a state lower than the constraint should return true",
+			Name: "a tenant without meeting pool should acquire an unlimited meeting",
 			Mock: func() {
-				constraint := int64(100)
-				tenant.Spec.MeetingsPool = &constraint
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 99, nil
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, l int64) (MeetingAcquisition, error) {
+					limit = l
+					return MeetingAcquired, nil
 				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Nil(t, err)
-				assert.Equal(t, true, value.(bool))
+				assert.Equal(t, MeetingAcquired, value)
+				assert.Equal(t, Unlimited, limit)
 			},
 		},
 		{
-			Name: "a state hidher than the constraint should return true",
+			Name: "a tenant meeting pool should be used as limit",
 			Mock: func() {
-				constraint := int64(90)
-				tenant.Spec.MeetingsPool = &constraint
+				pool := int64(10)
+				tenant.Spec.MeetingsPool = &pool
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, l int64) (MeetingAcquisition, error) {
+					limit = l
+					return MeetingPoolFull, nil
+				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Nil(t, err)
-				assert.Equal(t, false, value.(bool))
+				assert.Equal(t, MeetingPoolFull, value)
+				assert.Equal(t, int64(10), limit)
 			},
 		},
 	}
 
 	for _, test := range tests {
 		test.Mock()
-		can, err := server.isTenantLowerThanMeetingPool(tenant)
-		test.Validator(t, can, err)
+		acquired, err := server.acquireTenantMeeting(tenant, meetingID)
+		test.Validator(t, acquired, err)
 	}
 }
 
-func TestIsTenantLowerThanUserPool(t *testing.T) {
-	up := int64(0)
+func TestAcquireTenantParticipant(t *testing.T) {
+	var limit int64
+	pool := int64(20)
 	tenant := &admin.Tenant{
 		Spec: &admin.TenantSpec{
-			UserPool: &up,
+			Host:     "localhost",
+			UserPool: &pool,
 		},
 	}
 	server := NewServer(&config.Config{})
-	server.Balancer = &balancer.Mock{}
+	server.Pool = &PoolMock{}
 	tests := []test.Test{
 		{
-			Name: "an error returned by balancer should be returned",
+			Name: "an error returned by pool should be returned",
 			Mock: func() {
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 0, errors.New("balancer error")
+				AcquireParticipantPoolMockFunc = func(tenant string, meetingID string, l int64) (bool, error) {
+					return false, errors.New("pool error")
 				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
@@ -88,35 +94,24 @@ func TestIsTenantLowerThanUserPool(t *testing.T) {
 			},
 		},
 		{
-			Name: "a state lower than the constraint should return true",
+			Name: "a tenant user pool should be used as limit",
 			Mock: func() {
-				constraint := int64(100)
-				tenant.Spec.UserPool = &constraint
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 99, nil
+				AcquireParticipantPoolMockFunc = func(tenant string, meetingID string, l int64) (bool, error) {
+					limit = l
+					return true, nil
 				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Nil(t, err)
-				assert.Equal(t, true, value.(bool))
-			},
-		},
-		{
-			Name: "a state hidher than the constraint should return true",
-			Mock: func() {
-				constraint := int64(90)
-				tenant.Spec.UserPool = &constraint
-			},
-			Validator: func(t *testing.T, value interface{}, err error) {
-				assert.Nil(t, err)
-				assert.Equal(t, false, value.(bool))
+				assert.True(t, value.(bool))
+				assert.Equal(t, pool, limit)
 			},
 		},
 	}
 
 	for _, test := range tests {
 		test.Mock()
-		can, err := server.isTenantLowerThanUserPool(tenant)
-		test.Validator(t, can, err)
+		acquired, err := server.acquireTenantParticipant(tenant, meetingID)
+		test.Validator(t, acquired, err)
 	}
 }
@@ -26,3 +26,19 @@ func meetingPoolReachedError() *api.Error {
 func userPoolReachedError() *api.Error {
 	return api.CreateError("userPoolReached", "Your tenant reached the user pool limit.")
 }
+
+func meetingsQuotaExceededError() *api.Error {
+	return api.CreateError("meetingsQuotaExceeded", "Your tenant exhausted its meetings quota for the current period.")
+}
+
+func participantMinutesQuotaExceededError() *api.Error {
+	return api.CreateError("participantMinutesQuotaExceeded", "Your tenant exhausted its participant minutes quota for the current period.")
+}
+
+func rateLimitExceededError() *api.Error {
+	return api.CreateError("rateLimitExceeded", "Your tenant exceeded the rate limit for this action. Retry later.")
+}
+
+func meetingIDNotUniqueError() *api.Error {
+	return api.CreateError("idNotUnique", "A meeting already exists with that meeting ID. Please use a different meeting ID.")
+}
@@ -36,3 +36,9 @@ func TestUserPoolReachedError(t *testing.T) {
 	assert.Equal(t, "userPoolReached", err.MessageKey)
 	assert.Equal(t, "Your tenant reached the user pool limit.", err.Message)
 }
+
+func TestRateLimitExceededError(t *testing.T) {
+	err := rateLimitExceededError()
+	assert.Equal(t, "FAILED", err.ReturnCode)
+	assert.Equal(t, "rateLimitExceeded", err.MessageKey)
+}
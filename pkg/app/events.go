@@ -0,0 +1,65 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/events"
+)
+
+const (
+	// EndReasonCall is the meeting.ended event reason of a meeting ended by an end call
+	EndReasonCall = "end"
+	// EndReasonPolicy is the meeting.ended event reason of a meeting ended because it violates the tenant policy
+	EndReasonPolicy = "policy"
+	// EndReasonNotRunning is the meeting.ended event reason of a meeting found no longer running by the meetings poller
+	EndReasonNotRunning = "not_running"
+)
+
+func publishMeetingCreated(tenant string, meetingID string, instance string) {
+	events.Publish(events.MeetingCreated, tenant, map[string]interface{}{
+		"meeting_id": meetingID,
+		"instance":   instance,
+	})
+}
+
+func publishMeetingEnded(tenant string, meetingID string, reason string, policy string) {
+	data := map[string]interface{}{
+		"meeting_id": meetingID,
+		"reason":     reason,
+	}
+
+	if policy != "" {
+		data["policy"] = policy
+	}
+
+	events.Publish(events.MeetingEnded, tenant, data)
+}
+
+func publishPoolLimitReached(tenant *admin.Tenant, pool string, limit *int64, meetingID string) {
+	events.Publish(events.PoolLimitReached, tenant.Spec.Host, map[string]interface{}{
+		"pool":       pool,
+		"limit":      poolLimit(limit),
+		"meeting_id": meetingID,
+	})
+}
+
+func publishInstanceOffline(instance string) {
+	events.Publish(events.InstanceOffline, "", map[string]interface{}{
+		"instance": instance,
+	})
+}
+
+func publishRecordingDiscovered(instance string, recording *api.Recording) {
+	meetingID := recording.MeetingID
+	if id, ok := recording.Metadata()[api.MeetingIDMetadata]; ok && id != "" {
+		meetingID = id
+	}
+
+	events.Publish(events.RecordingDiscovered, recording.Tenant(), map[string]interface{}{
+		"record_id":  recording.RecordID,
+		"meeting_id": meetingID,
+		"instance":   instance,
+		"published":  recording.Published,
+	})
+}
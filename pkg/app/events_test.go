@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/events"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+type eventsRecorder struct {
+	events []*events.Event
+}
+
+func (r *eventsRecorder) Publish(ctx context.Context, event *events.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventsRecorder) Close() error {
+	return nil
+}
+
+// recordEvents replaces the global events publisher. The returned function flushes the publisher and returns the
+// published events
+func recordEvents() func() []*events.Event {
+	recorder := &eventsRecorder{}
+	events.DefaultPublisher = events.NewPublisher(recorder, 100, 0, 0)
+	return func() []*events.Event {
+		events.DefaultPublisher.Close(context.Background())
+		events.DefaultPublisher = nil
+		return recorder.events
+	}
+}
+
+func TestPublishMeetingEnded(t *testing.T) {
+	published := recordEvents()
+	publishMeetingEnded("localhost", "meeting", EndReasonCall, "")
+	publishMeetingEnded("localhost", "meeting", EndReasonPolicy, "idle timeout exceeded")
+
+	result := published()
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, events.MeetingEnded, result[0].Type)
+	assert.Equal(t, "localhost", result[0].Tenant)
+	assert.Equal(t, map[string]interface{}{"meeting_id": "meeting", "reason": EndReasonCall}, result[0].Data)
+	assert.Equal(t, "idle timeout exceeded", result[1].Data["policy"])
+}
+
+func TestDiscoverRecording(t *testing.T) {
+	server := doGenericInitialization()
+	recording := &api.Recording{RecordID: "record", MeetingID: "meeting"}
+	instance := "http://localhost/bigbluebutton"
+
+	t.Run("recordings found by the first poll should not be published", func(t *testing.T) {
+		published := recordEvents()
+		known := map[string]string{}
+		server.discoverRecording(known, instance, recording)
+		server.knownRecordings = known
+		assert.Empty(t, published())
+	})
+
+	t.Run("a new recording should be published", func(t *testing.T) {
+		published := recordEvents()
+		known := map[string]string{}
+		server.discoverRecording(known, instance, recording)
+		server.discoverRecording(known, instance, &api.Recording{RecordID: "new", MeetingID: "meeting"})
+		server.knownRecordings = known
+
+		result := published()
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, events.RecordingDiscovered, result[0].Type)
+		assert.Equal(t, "new", result[0].Data["record_id"])
+		assert.Equal(t, instance, result[0].Data["instance"])
+	})
+
+	t.Run("recordings of an instance failing to respond should be kept", func(t *testing.T) {
+		known := map[string]string{}
+		server.keepKnownRecordings(known, instance)
+		server.keepKnownRecordings(known, "http://other/bigbluebutton")
+		assert.Equal(t, map[string]string{"record": instance, "new": instance}, known)
+	})
+}
+
+func TestPollMeetingsEndedEvents(t *testing.T) {
+	now := time.Date(2022, time.March, 17, 15, 4, 5, 0, time.UTC)
+	server := doGenericInitialization()
+	server.InstanceManager = &admin.InstanceManagerMock{}
+	server.Config.BigBlueSwarm.MeetingsPollInterval = "1m"
+	admin.ListInstancesInstanceManagerMockFunc = pollerInstances
+	restclient.RestClientMockDoFunc = pollerMeetings
+	admin.ListTenantsTenantManagerMockFunc = func() ([]admin.TenantListObject, error) {
+		return []admin.TenantListObject{{Hostname: "empty.localhost"}}, nil
+	}
+	admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname}}, nil
+	}
+	ReconcilePoolMockFunc = func(tenant string, meetings map[string]int64, n time.Time, grace time.Duration) (map[string]time.Time, error) {
+		return map[string]time.Time{}, nil
+	}
+	admin.ReconcileMeetingHistoryManagerMockFunc = func(tenant string, running []string, n time.Time, grace time.Duration) ([]string, error) {
+		return []string{"gone"}, nil
+	}
+	redisMock.ExpectDel(MeetingMapKey("gone"), OwnerKey(MeetingMapKey("gone"))).SetVal(2)
+	released := ""
+	ReleaseMeetingPoolMockFunc = func(tenant string, meetingID string) error {
+		released = tenant + "/" + meetingID
+		return nil
+	}
+
+	published := recordEvents()
+	server.pollMeetings(now)
+	assert.Nil(t, redisMock.ExpectationsWereMet())
+	assert.Equal(t, "empty.localhost/gone", released)
+
+	result := published()
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, events.MeetingEnded, result[0].Type)
+	assert.Equal(t, "empty.localhost", result[0].Tenant)
+	assert.Equal(t, map[string]interface{}{"meeting_id": "gone", "reason": EndReasonNotRunning}, result[0].Data)
+}
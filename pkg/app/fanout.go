@@ -0,0 +1,87 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// SkippedInstancesHeader is the response header listing the instances that failed to respond when
+// reportSkippedInstances is enabled
+const SkippedInstancesHeader = "X-BigBlueSwarm-Skipped-Instances"
+
+// instanceResult is the result of a call on an instance during a fan-out
+type instanceResult[T any] struct {
+	instance api.BigBlueButtonInstance
+	value    T
+	err      error
+}
+
+// fanOut calls the given function on every instance, with at most concurrency calls at once. Each call is canceled after
+// the timeout. A concurrency or a timeout lower or equal to 0 disables the limit. Results are returned in the instances
+// order, failing instances included
+func fanOut[T any](ctx context.Context, instances []api.BigBlueButtonInstance, concurrency int, timeout time.Duration, call func(ctx context.Context, instance api.BigBlueButtonInstance) (T, error)) []instanceResult[T] {
+	if concurrency <= 0 || concurrency > len(instances) {
+		concurrency = len(instances)
+	}
+
+	results := make([]instanceResult[T], len(instances))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, instance api.BigBlueButtonInstance) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			callCtx := ctx
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				callCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			value, err := call(callCtx, instance)
+			results[i] = instanceResult[T]{instance: instance, value: value, err: err}
+		}(i, instance)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// instanceTimeout returns the configured deadline of each instance call during a fan-out
+func (s *Server) instanceTimeout() time.Duration {
+	if s.Config.BigBlueSwarm.InstanceTimeout == "" {
+		return 0
+	}
+
+	return toDuration(s.Config.BigBlueSwarm.InstanceTimeout)
+}
+
+func (s *Server) getMeetings(ctx context.Context, instances []api.BigBlueButtonInstance) []instanceResult[*api.GetMeetingsResponse] {
+	return fanOut(ctx, instances, s.Config.BigBlueSwarm.InstanceConcurrency, s.instanceTimeout(), func(ctx context.Context, instance api.BigBlueButtonInstance) (*api.GetMeetingsResponse, error) {
+		return instance.GetMeetingsWithContext(ctx)
+	})
+}
+
+func (s *Server) getRecordings(ctx context.Context, instances []api.BigBlueButtonInstance, params string, timeout time.Duration) []instanceResult[*api.GetRecordingsResponse] {
+	return fanOut(ctx, instances, s.Config.BigBlueSwarm.InstanceConcurrency, timeout, func(ctx context.Context, instance api.BigBlueButtonInstance) (*api.GetRecordingsResponse, error) {
+		return instance.GetRecordingsWithContext(ctx, params)
+	})
+}
+
+// reportSkippedInstances adds the skipped instances to the response headers if enabled
+func (s *Server) reportSkippedInstances(c *gin.Context, skipped []string) {
+	if s.Config.BigBlueSwarm.ReportSkippedInstances && len(skipped) > 0 {
+		c.Header(SkippedInstancesHeader, strings.Join(skipped, ", "))
+	}
+}
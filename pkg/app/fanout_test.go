@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func fanOutInstances(count int) []api.BigBlueButtonInstance {
+	instances := []api.BigBlueButtonInstance{}
+	for i := 0; i < count; i++ {
+		instances = append(instances, api.BigBlueButtonInstance{URL: fmt.Sprintf("http://bbb%d/bigbluebutton", i)})
+	}
+
+	return instances
+}
+
+func TestFanOut(t *testing.T) {
+	t.Run("results should be returned in the instances order", func(t *testing.T) {
+		results := fanOut(context.Background(), fanOutInstances(5), 2, 0, func(ctx context.Context, instance api.BigBlueButtonInstance) (string, error) {
+			if instance.URL == "http://bbb3/bigbluebutton" {
+				return "", errors.New("instance error")
+			}
+
+			return instance.URL, nil
+		})
+
+		assert.Len(t, results, 5)
+		for i, result := range results {
+			assert.Equal(t, fmt.Sprintf("http://bbb%d/bigbluebutton", i), result.instance.URL)
+			if i == 3 {
+				assert.NotNil(t, result.err)
+				continue
+			}
+
+			assert.Equal(t, result.instance.URL, result.value)
+		}
+	})
+
+	t.Run("concurrent calls should not exceed the concurrency", func(t *testing.T) {
+		var running int32
+		var max int32
+		fanOut(context.Background(), fanOutInstances(10), 3, 0, func(ctx context.Context, instance api.BigBlueButtonInstance) (bool, error) {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				previous := atomic.LoadInt32(&max)
+				if current <= previous || atomic.CompareAndSwapInt32(&max, previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return true, nil
+		})
+
+		assert.Equal(t, int32(3), max)
+	})
+
+	t.Run("a hung instance should be canceled after the timeout without blocking other instances", func(t *testing.T) {
+		start := time.Now()
+		results := fanOut(context.Background(), fanOutInstances(3), 0, 20*time.Millisecond, func(ctx context.Context, instance api.BigBlueButtonInstance) (bool, error) {
+			if instance.URL == "http://bbb1/bigbluebutton" {
+				<-ctx.Done()
+				return false, ctx.Err()
+			}
+
+			return true, nil
+		})
+
+		assert.Less(t, time.Since(start), time.Second)
+		assert.True(t, results[0].value)
+		assert.ErrorIs(t, results[1].err, context.DeadlineExceeded)
+		assert.True(t, results[2].value)
+	})
+}
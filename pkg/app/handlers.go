@@ -2,18 +2,26 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"path"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/metrics"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // HealthCheck handler returns an health check response
@@ -23,9 +31,8 @@ func (s *Server) HealthCheck(c *gin.Context) {
 
 // checkTenant check if tenant exists. Otherwise, it returns an error
 func (s *Server) checkTenant(c *gin.Context) {
-	hostname := utils.GetHost(c)
 	logger := getLogger(c)
-	tenant, err := s.TenantManager.GetTenant(hostname)
+	tenant, err := s.resolveTenant(c)
 	if err != nil {
 		logger.Errorln("failed to retrieve tenant", err)
 		c.XML(http.StatusInternalServerError, getTenantError())
@@ -33,7 +40,7 @@ func (s *Server) checkTenant(c *gin.Context) {
 		return
 	}
 
-	logger.addField("tenant", utils.GetHost(c))
+	logger.AddField("tenant", utils.GetHost(c))
 	if tenant == nil {
 		logger.Warn("tenant manager does not found current hostname")
 		c.XML(http.StatusForbidden, tenantNotFoundError())
@@ -41,12 +48,119 @@ func (s *Server) checkTenant(c *gin.Context) {
 		return
 	}
 
+	c.Set("tenant", tenant)
 	c.Next()
 }
 
+func retryAfterSeconds(retryAfter time.Duration) string {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return strconv.FormatInt(seconds, 10)
+}
+
+// checkRateLimit rejects the request if the tenant exhausted the requested action rate limit. The request is not
+// rejected if the rate limiter fails
+func (s *Server) checkRateLimit(c *gin.Context) {
+	value, exists := c.Get("tenant")
+	if !exists {
+		c.Next()
+		return
+	}
+
+	tenant := value.(*admin.Tenant)
+	action := path.Base(c.Request.URL.Path)
+	limit := tenant.RateLimit(action)
+	if limit == nil {
+		c.Next()
+		return
+	}
+
+	logger := getLogger(c)
+	allowed, retryAfter, err := s.RateLimiter.Allow(RateLimitKey(tenant.Spec.Host, action), limit, time.Now())
+	if err != nil {
+		logger.Errorln("failed to check tenant rate limit", err)
+		c.Next()
+		return
+	}
+
+	if !allowed {
+		logger.Dup().AddField("action", action).Warn("tenant rate limit exceeded")
+		c.Header("Retry-After", retryAfterSeconds(retryAfter))
+		c.XML(http.StatusTooManyRequests, rateLimitExceededError())
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// namespaceMeetingIDs replace the requested meeting identifiers by the tenant namespaced meeting identifiers used on the
+// instances. The tenant meeting identifier is stored in the created meeting metadata so it can be restored in responses.
+// Handlers must read the meeting identifiers from the API context since the request query is not rewritten
+func (s *Server) namespaceMeetingIDs(c *gin.Context) {
+	value, exists := c.Get("tenant")
+	if !exists || !value.(*admin.Tenant).HasMeetingIDNamespace() {
+		c.Next()
+		return
+	}
+
+	tenant := value.(*admin.Tenant)
+	ctx := getAPIContext(c)
+	meetingIDs, exists := ctx.GetParam("meetingID")
+	if !exists {
+		c.Next()
+		return
+	}
+
+	namespaced := map[string]string{}
+	ids := strings.Split(meetingIDs, ",")
+	for i, id := range ids {
+		ids[i] = tenant.NamespaceMeetingID(id)
+		namespaced[ids[i]] = id
+	}
+
+	ctx.SetParam("meetingID", strings.Join(ids, ","))
+	if ctx.Action == api.Create {
+		ctx.SetParam("meta_"+api.MeetingIDMetadata, meetingIDs)
+	}
+
+	c.Set("meeting_ids", namespaced)
+	c.Next()
+}
+
+// tenantMeetingID returns the tenant meeting identifier of an instance meeting identifier, using the meeting metadata,
+// the request meeting identifiers if a request context is given, or the tenant prefix
+func tenantMeetingID(c *gin.Context, tenant *admin.Tenant, meetingID string, metadata map[string]string) string {
+	if !tenant.HasMeetingIDNamespace() {
+		return meetingID
+	}
+
+	if id, ok := metadata[api.MeetingIDMetadata]; ok && id != "" {
+		return id
+	}
+
+	if c != nil {
+		if value, exists := c.Get("meeting_ids"); exists {
+			if id, ok := value.(map[string]string)[meetingID]; ok {
+				return id
+			}
+		}
+	}
+
+	return tenant.StripMeetingID(meetingID)
+}
+
 // GetMeetings handler returns the getMeetings API. See https://docs.bigbluebutton.org/dev/api.html#getmeetings.
 func (s *Server) GetMeetings(c *gin.Context) {
 	logger := getLogger(c)
+	tenant := s.requestTenant(c, logger)
+	if tenant == nil {
+		return
+	}
+
 	instances, err := s.InstanceManager.ListInstances()
 	if err != nil {
 		logger.Errorln("failed to retrieving instances", err)
@@ -59,19 +173,62 @@ func (s *Server) GetMeetings(c *gin.Context) {
 		Meetings:   make([]api.MeetingInfo, 0),
 	}
 
-	for _, instance := range instances {
-		meetings, err := instance.GetMeetings()
-		if err != nil {
-			logger.dup().addField("instance", instance.URL).Errorln("An error occurred while retrieving meetings from instance", err)
+	skipped := []string{}
+	for _, result := range s.getMeetings(c.Request.Context(), instances) {
+		if result.err != nil {
+			logger.Dup().AddField("instance", result.instance.URL).Errorln("An error occurred while retrieving meetings from instance", result.err)
+			skipped = append(skipped, result.instance.URL)
 			continue
 		}
 
-		response.Meetings = append(response.Meetings, meetings.Meetings...)
+		for _, meeting := range result.value.Meetings {
+			if ownedByTenant(tenant, result.instance.URL, meeting.Tenant()) {
+				meeting.MeetingID = tenantMeetingID(c, tenant, meeting.MeetingID, meeting.Metadata())
+				response.Meetings = append(response.Meetings, meeting)
+			}
+		}
 	}
 
+	s.reportSkippedInstances(c, skipped)
 	c.XML(http.StatusOK, response)
 }
 
+// requestTenant retrieve the requesting tenant. If the tenant can't be retrieved, it writes the error response and
+// returns nil
+func (s *Server) requestTenant(c *gin.Context, logger *utils.RequestLogger) *admin.Tenant {
+	tenant, err := s.resolveTenant(c)
+	if err != nil {
+		logger.Errorln("failed to retrieve tenant", err)
+		c.XML(http.StatusInternalServerError, getTenantError())
+		return nil
+	}
+
+	if tenant == nil {
+		logger.Warn("tenant manager does not found current hostname")
+		c.XML(http.StatusForbidden, tenantNotFoundError())
+		return nil
+	}
+
+	return tenant
+}
+
+// ownedByTenant check if a meeting or a recording belongs to the tenant. A resource tagged with the tenant metadata
+// belongs to this tenant. A resource without tenant metadata belongs to the tenant only if its instance is dedicated to
+// the tenant
+func ownedByTenant(tenant *admin.Tenant, instance string, owner string) bool {
+	if owner != "" {
+		return owner == tenant.Spec.Host
+	}
+
+	for _, i := range tenant.Instances {
+		if i == instance {
+			return true
+		}
+	}
+
+	return false
+}
+
 func missingMeetingIDParameter(c *gin.Context) {
 	c.XML(http.StatusOK, api.CreateError(api.MessageKeys().ValidationError, api.Messages().EmptyMeetingID))
 }
@@ -80,8 +237,11 @@ func missingRecordIDParameter(c *gin.Context) {
 	c.XML(http.StatusOK, api.CreateError(api.MessageKeys().MissingRecordIDParameter, api.Messages().MissingRecordIDParameter))
 }
 
-func (s *Server) retrieveBBBBInstanceFromKey(key string) (api.BigBlueButtonInstance, error) {
-	host, err := s.Mapper.Get(key)
+// retrieveBBBBInstanceFromKey retrieve the instance hosting the session. The session must be owned by the tenant.
+// Sessions without owner, like recordings without tenant metadata, are only accessible if their instance is dedicated
+// to the tenant
+func (s *Server) retrieveBBBBInstanceFromKey(ctx context.Context, key string, tenant *admin.Tenant) (api.BigBlueButtonInstance, error) {
+	host, err := s.mapper(ctx).Get(key)
 	if err != nil {
 		return api.BigBlueButtonInstance{}, fmt.Errorf("mapper failed to retrieve session: %s", err.Error())
 	}
@@ -90,6 +250,15 @@ func (s *Server) retrieveBBBBInstanceFromKey(key string) (api.BigBlueButtonInsta
 		return api.BigBlueButtonInstance{}, errors.New("mapper failed to retrieve session host")
 	}
 
+	owner, err := s.mapper(ctx).GetOwner(key)
+	if err != nil {
+		return api.BigBlueButtonInstance{}, fmt.Errorf("mapper failed to retrieve session owner: %s", err.Error())
+	}
+
+	if !ownedByTenant(tenant, host, owner) {
+		return api.BigBlueButtonInstance{}, fmt.Errorf("session %s is not owned by tenant %s", key, tenant.Spec.Host)
+	}
+
 	instance, err := s.InstanceManager.Get(host)
 	if err != nil {
 		return api.BigBlueButtonInstance{}, fmt.Errorf("manager failed to retrieve target instance for current request %s", err.Error())
@@ -98,56 +267,127 @@ func (s *Server) retrieveBBBBInstanceFromKey(key string) (api.BigBlueButtonInsta
 	return instance, nil
 }
 
-func (s *Server) canTenantJoinMeeting(logger *RequestLogger, tenant *admin.Tenant) (int, *api.Error) {
-	if tenant.HasUserPool() {
-		logger := logger.setFields(log.Fields{
-			"tenant":        tenant.Spec.Host,
-			"meetings_pool": tenant.Spec.MeetingsPool,
-			"users_pool":    tenant.Spec.UserPool,
-		})
+func (s *Server) canTenantJoinMeeting(logger *utils.RequestLogger, tenant *admin.Tenant, meetingID string) (int, *api.Error) {
+	if status, err := s.checkTenantQuotas(logger, tenant); status != http.StatusOK {
+		return status, err
+	}
 
-		canCreate, err := s.isTenantLowerThanUserPool(tenant)
-		if err != nil {
-			logger.Errorln("unable to check if tenant can join meeting", err)
-			return http.StatusInternalServerError, serverError("BigBlueSwarm failed to check if your tenant reached the user pool limit.")
-		}
+	logger = logger.SetFields(log.Fields{
+		"tenant":        tenant.Spec.Host,
+		"meetings_pool": tenant.Spec.MeetingsPool,
+		"users_pool":    tenant.Spec.UserPool,
+	})
 
-		if !canCreate {
-			log.Info("tenant raise the user pool limit and can't join meeting")
-			return http.StatusForbidden, userPoolReachedError()
-		}
+	acquired, err := s.acquireTenantParticipant(tenant, meetingID)
+	if err != nil {
+		logger.Errorln("unable to check if tenant can join meeting", err)
+		return http.StatusInternalServerError, serverError("BigBlueSwarm failed to check if your tenant reached the user pool limit.")
+	}
+
+	if !acquired {
+		logger.Info("tenant raise the user pool limit and can't join meeting")
+		publishPoolLimitReached(tenant, "users", tenant.Spec.UserPool, tenantMeetingID(nil, tenant, meetingID, nil))
+		return http.StatusForbidden, userPoolReachedError()
 	}
 
 	return http.StatusOK, nil
 }
 
-func (s *Server) canTenantCreateMeeting(logger *RequestLogger, tenant *admin.Tenant) (int, *api.Error) {
-	if tenant.HasMeetingPool() {
-		logger := logger.setFields(log.Fields{
-			"tenant":        tenant.Spec.Host,
-			"meetings_pool": tenant.Spec.MeetingsPool,
-			"users_pool":    tenant.Spec.UserPool,
-		})
+func (s *Server) canTenantCreateMeeting(logger *utils.RequestLogger, tenant *admin.Tenant, meetingID string) (MeetingAcquisition, int, *api.Error) {
+	if status, err := s.checkTenantQuotas(logger, tenant); status != http.StatusOK {
+		return MeetingPoolFull, status, err
+	}
 
-		canCreate, err := s.isTenantLowerThanMeetingPool(tenant)
-		if err != nil {
-			logger.Errorln("unable to check if tenant can create meeting", err)
-			return http.StatusInternalServerError, serverError("BigBlueSwarm failed to check if your tenant reached the meeting pool limit.")
-		}
+	logger = logger.SetFields(log.Fields{
+		"tenant":        tenant.Spec.Host,
+		"meetings_pool": tenant.Spec.MeetingsPool,
+		"users_pool":    tenant.Spec.UserPool,
+	})
 
-		if !canCreate {
-			logger.Info("tenant raise the meetings pool limit and can't create a new meeting")
-			return http.StatusForbidden, meetingPoolReachedError()
-		}
+	acquisition, err := s.acquireTenantMeeting(tenant, meetingID)
+	if err != nil {
+		logger.Errorln("unable to check if tenant can create meeting", err)
+		return MeetingPoolFull, http.StatusInternalServerError, serverError("BigBlueSwarm failed to check if your tenant reached the meeting pool limit.")
+	}
+
+	if acquisition == MeetingPoolFull {
+		logger.Info("tenant raise the meetings pool limit and can't create a new meeting")
+		publishPoolLimitReached(tenant, "meetings", tenant.Spec.MeetingsPool, tenantMeetingID(nil, tenant, meetingID, nil))
+		return MeetingPoolFull, http.StatusForbidden, meetingPoolReachedError()
+	}
+
+	return acquisition, http.StatusOK, nil
+}
+
+// checkTenantQuotas check the tenant participant minutes consumption over the current quota period. The meetings quota
+// is checked when the meeting creation is reserved
+func (s *Server) checkTenantQuotas(logger *utils.RequestLogger, tenant *admin.Tenant) (int, *api.Error) {
+	if !tenant.HasQuotas() {
+		return http.StatusOK, nil
+	}
+
+	usage, err := s.TenantManager.GetQuotaUsage(tenant, time.Now())
+	if err != nil {
+		logger.Errorln("unable to check tenant quotas", err)
+		return http.StatusInternalServerError, serverError("BigBlueSwarm failed to check your tenant quotas.")
+	}
+
+	if usage.ParticipantMinutesExhausted() {
+		logger.Info("tenant exhausted its participant minutes quota")
+		return http.StatusForbidden, participantMinutesQuotaExceededError()
 	}
 
 	return http.StatusOK, nil
 }
 
+// reserveTenantMeetingUsage reserves the meeting creation in the tenant meetings quota. It returns the error to return
+// if the quota is exhausted or can't be checked
+func (s *Server) reserveTenantMeetingUsage(logger *utils.RequestLogger, tenant *admin.Tenant, now time.Time) (int, *api.Error) {
+	reserved, err := s.TenantManager.ReserveMeetingUsage(tenant, now)
+	if err != nil {
+		logger.Errorln("unable to check tenant quotas", err)
+		return http.StatusInternalServerError, serverError("BigBlueSwarm failed to check your tenant quotas.")
+	}
+
+	if !reserved {
+		logger.Info("tenant exhausted its meetings quota")
+		return http.StatusForbidden, meetingsQuotaExceededError()
+	}
+
+	return http.StatusOK, nil
+}
+
+// claimMeetingOwner claims the meeting owner for the tenant. It returns true if the tenant newly claimed the meeting, or
+// the error to return if the owner can't be checked or if the meeting is owned by another tenant
+func (s *Server) claimMeetingOwner(ctx context.Context, logger *utils.RequestLogger, tenant *admin.Tenant, meetingID string) (bool, int, *api.Error) {
+	claimed, err := s.mapper(ctx).ClaimOwner(MeetingMapKey(meetingID), tenant.Spec.Host)
+	if err != nil {
+		logger.Errorln("mapper failed to claim meeting owner", err)
+		return false, http.StatusInternalServerError, serverError("BigBlueSwarm failed to check the meeting owner")
+	}
+
+	if claimed {
+		return true, http.StatusOK, nil
+	}
+
+	owner, err := s.mapper(ctx).GetOwner(MeetingMapKey(meetingID))
+	if err != nil {
+		logger.Errorln("mapper failed to retrieve meeting owner", err)
+		return false, http.StatusInternalServerError, serverError("BigBlueSwarm failed to check the meeting owner")
+	}
+
+	if owner != tenant.Spec.Host {
+		logger.Warn("meeting id is already used by another tenant")
+		return false, http.StatusOK, meetingIDNotUniqueError()
+	}
+
+	return false, http.StatusOK, nil
+}
+
 // Create handler find a server and create a meeting on balanced server.
 func (s *Server) Create(c *gin.Context) {
 	ctx := getAPIContext(c)
-	tenant, err := s.TenantManager.GetTenant(utils.GetHost(c))
+	tenant, err := s.resolveTenant(c)
 	logger := getLogger(c)
 
 	if err != nil {
@@ -156,17 +396,63 @@ func (s *Server) Create(c *gin.Context) {
 		return
 	}
 
-	logger.setFields(log.Fields{
+	logger.SetFields(log.Fields{
 		"tenant": tenant.Spec.Host,
 		"action": ctx.Action,
-		"params": ctx.Params,
+		"params": utils.RedactParams(ctx.Params),
 	})
 
-	if status, err := s.canTenantCreateMeeting(logger.dup(), tenant); status != http.StatusOK {
-		c.XML(status, err)
+	meetingID, _ := ctx.GetParam("meetingID")
+	logger.AddField("meeting_id", meetingID)
+	claimed, status, apiErr := s.claimMeetingOwner(c.Request.Context(), logger, tenant, meetingID)
+	if apiErr != nil {
+		c.XML(status, apiErr)
+		return
+	}
+
+	// The meeting owner is claimed before calling the instance so concurrent creations of the same meeting by two
+	// tenants can't both succeed. The claim is released if the meeting is not mapped
+	mapped := false
+	defer func() {
+		if claimed && !mapped {
+			if err := s.mapper(c.Request.Context()).RemoveOwner(MeetingMapKey(meetingID)); err != nil {
+				logger.Errorln("mapper failed to release meeting owner", err)
+			}
+		}
+	}()
+
+	acquisition, status, apiErr := s.canTenantCreateMeeting(logger.Dup(), tenant, meetingID)
+	if status != http.StatusOK {
+		c.XML(status, apiErr)
+		return
+	}
+
+	// The meeting is released on failure only if this request reserved it, so a failed create for a running meeting
+	// does not remove it from the pool
+	created := false
+	defer func() {
+		if !created && acquisition == MeetingAcquired {
+			s.releaseTenantMeeting(logger, tenant.Spec.Host, meetingID)
+		}
+	}()
+
+	// The meetings quota is reserved before calling the instance so concurrent creations can't exceed it. The
+	// reservation is released if the meeting is not created or was already running
+	reservedAt := time.Now()
+	if status, apiErr := s.reserveTenantMeetingUsage(logger, tenant, reservedAt); status != http.StatusOK {
+		c.XML(status, apiErr)
 		return
 	}
 
+	counted := false
+	defer func() {
+		if !counted {
+			if err := s.TenantManager.ReleaseMeetingUsage(tenant, reservedAt); err != nil {
+				logger.Errorln("tenant manager failed to release meeting usage", err)
+			}
+		}
+	}()
+
 	if len(tenant.Instances) == 0 {
 		logger.Info("tenant does not have a configured instance list. Getting all instances")
 		instances, err := s.InstanceManager.List()
@@ -179,15 +465,23 @@ func (s *Server) Create(c *gin.Context) {
 		tenant.Instances = instances
 	}
 
+	tenant.ApplyCreateParameters(ctx)
+	tenant.ApplyMaxMeetingDuration(ctx)
 	ctx.SetTenantMetadata(tenant.Spec.Host)
 
+	_, span := tracing.Start(c.Request.Context(), "balancer.process")
 	target, err := s.Balancer.Process(tenant.Instances)
+	span.SetAttributes(attribute.String("balancer.target", target))
+	tracing.End(span, err)
 	if err != nil || target == "" {
+		metrics.BalancerFailures.Inc()
 		logger.Errorln("balancer failed to process current request", err)
 		c.XML(http.StatusInternalServerError, noInstanceFoundError())
 		return
 	}
 
+	metrics.BalancerDecisions.WithLabelValues(target).Inc()
+	logger.AddField("instance", target)
 	instance, err := s.InstanceManager.Get(target)
 	if err != nil {
 		logger.Errorln("manager failed to retrieve target instance for current request", err)
@@ -195,7 +489,7 @@ func (s *Server) Create(c *gin.Context) {
 		return
 	}
 
-	apiResponse, err := instance.Create(ctx.Params)
+	apiResponse, err := instance.CreateWithContext(c.Request.Context(), ctx.Params)
 
 	if err != nil {
 		logger.Errorln("an error occurred while creating remote session, instance returns a nil response", err)
@@ -203,11 +497,24 @@ func (s *Server) Create(c *gin.Context) {
 		return
 	}
 
-	addErr := s.Mapper.Add(MeetingMapKey(apiResponse.MeetingID), instance.URL)
-	if addErr != nil {
-		logger.Errorln("mapper failed to add new session", addErr)
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
+	// A meeting the instance failed to create is not mapped, so the owner claim is released
+	if apiResponse.ReturnCode == api.ReturnCodes().Success {
+		addErr := s.mapper(c.Request.Context()).Add(MeetingMapKey(apiResponse.MeetingID), instance.URL)
+		if addErr != nil {
+			logger.Errorln("mapper failed to add new session", addErr)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		mapped = true
+		created = true
+		s.recordMeetingCreation(logger.Entry, tenant.Spec.Host, instance.URL, apiResponse)
+	}
+
+	counted = created && apiResponse.MessageKey != api.MessageKeys().DuplicationWarning
+	apiResponse.MeetingID = tenantMeetingID(c, tenant, apiResponse.MeetingID, nil)
+	if counted {
+		publishMeetingCreated(tenant.Spec.Host, apiResponse.MeetingID, instance.URL)
 	}
 
 	c.XML(http.StatusOK, apiResponse)
@@ -217,7 +524,7 @@ func (s *Server) Create(c *gin.Context) {
 func (s *Server) Join(c *gin.Context) {
 	ctx := getAPIContext(c)
 	logger := getLogger(c)
-	tenant, err := s.TenantManager.GetTenant(utils.GetHost(c))
+	tenant, err := s.resolveTenant(c)
 
 	if err != nil {
 		logger.Errorln("failed to retrieve tenant from host", err)
@@ -225,41 +532,46 @@ func (s *Server) Join(c *gin.Context) {
 		return
 	}
 
-	logger.setFields(log.Fields{
+	logger.SetFields(log.Fields{
 		"tenant": tenant.Spec.Host,
 		"action": ctx.Action,
-		"params": ctx.Params,
+		"params": utils.RedactParams(ctx.Params),
 	})
 
-	if status, err := s.canTenantJoinMeeting(logger.dup(), tenant); status != http.StatusOK {
-		c.XML(status, err)
-		return
-	}
-
-	meetingID, exists := c.GetQuery("meetingID")
+	meetingID, exists := ctx.GetParam("meetingID")
 	if !exists {
 		logger.Warn("meeting id parameter missing")
 		missingMeetingIDParameter(c)
 		return
 	}
 
-	redirect, redirectExists := c.GetQuery("redirect")
-
-	instance, err := s.retrieveBBBBInstanceFromKey(MeetingMapKey(meetingID))
+	logger.AddField("meeting_id", meetingID)
+	// The meeting is resolved before acquiring the participant so joins of unknown or foreign meetings don't hold a
+	// user pool slot
+	instance, err := s.retrieveBBBBInstanceFromKey(c.Request.Context(), MeetingMapKey(meetingID), tenant)
 	if err != nil {
 		logger.Error(err)
 		c.XML(http.StatusOK, api.CreateError(api.MessageKeys().NotFound, api.Messages().NotFound))
 		return
 	}
 
+	logger.AddField("instance", instance.URL)
+	if status, err := s.canTenantJoinMeeting(logger.Dup(), tenant, meetingID); status != http.StatusOK {
+		c.XML(status, err)
+		return
+	}
+
+	redirect, redirectExists := c.GetQuery("redirect")
+
 	if redirectExists && redirect == "false" {
-		response, err := instance.Join(ctx.Params)
+		response, err := instance.JoinWithContext(c.Request.Context(), ctx.Params)
 		if err != nil {
 			logger.Errorln("An error occurred while calling join instance api", err)
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
 
+		response.MeetingID = tenantMeetingID(c, tenant, response.MeetingID, nil)
 		c.XML(http.StatusOK, response)
 	} else {
 		redirectURL, err := instance.GetJoinRedirectURL(ctx.Params)
@@ -275,13 +587,16 @@ func (s *Server) Join(c *gin.Context) {
 
 // End handler end provided session. See https://docs.bigbluebutton.org/dev/api.html#end
 func (s *Server) End(c *gin.Context) {
-	endProcess := func() error {
-		meetingID, _ := c.GetQuery("meetingID")
-		removeErr := s.Mapper.Remove(MeetingMapKey(meetingID))
+	endProcess := func(tenant *admin.Tenant) error {
+		meetingID, _ := getAPIContext(c).GetParam("meetingID")
+		removeErr := s.mapper(c.Request.Context()).Remove(MeetingMapKey(meetingID))
 		if removeErr != nil {
 			return fmt.Errorf("mapper failed to remove session %s: %s", meetingID, removeErr)
 		}
 
+		s.releaseTenantMeeting(getLogger(c), tenant.Spec.Host, meetingID)
+		s.recordMeetingEnd(getLogger(c).Entry, tenant.Spec.Host, meetingID)
+		publishMeetingEnded(tenant.Spec.Host, tenantMeetingID(c, tenant, meetingID, nil), EndReasonCall, "")
 		return nil
 	}
 
@@ -316,17 +631,22 @@ func (s *Server) proxyRecordings(c *gin.Context, action string, endProcess func(
 		return
 	}
 
-	logger.addField("record", recordID)
+	logger.AddField("record_id", recordID)
 
-	instance, err := s.retrieveBBBBInstanceFromKey(RecordingMapKey(recordID))
+	tenant := s.requestTenant(c, logger)
+	if tenant == nil {
+		return
+	}
+
+	instance, err := s.retrieveBBBBInstanceFromKey(c.Request.Context(), RecordingMapKey(recordID), tenant)
 	if err != nil {
 		logger.Errorln("failed to retrieve instance", err)
 		ginMethod(action, c)(http.StatusOK, errorMessage(action))
 		return
 	}
 
-	logger.addField("instance", instance.URL)
-	response, mErr := callInstanceMethod(ctx, instance, action)
+	logger.AddField("instance", instance.URL)
+	response, mErr := callInstanceMethod(c.Request.Context(), ctx, instance, action)
 	if mErr != nil {
 		logger.Error(err)
 		c.XML(http.StatusInternalServerError, serverError("BigBlueSwarm failed to call remote instance method"))
@@ -345,8 +665,9 @@ func (s *Server) proxyRecordings(c *gin.Context, action string, endProcess func(
 	ginMethod(action, c)(http.StatusOK, response)
 }
 
-func callInstanceMethod(ctx *api.Checksum, instance api.BigBlueButtonInstance, action string) (interface{}, interface{}) {
-	methodName := strings.Title(action)
+// callInstanceMethod calls the instance action method bound to the request context
+func callInstanceMethod(requestCtx context.Context, ctx *api.Checksum, instance api.BigBlueButtonInstance, action string) (interface{}, interface{}) {
+	methodName := strings.Title(action) + "WithContext"
 	value := reflect.ValueOf(&instance)
 	if value.IsNil() {
 		return nil, errors.New("failed to execute reflect on instance")
@@ -357,38 +678,52 @@ func callInstanceMethod(ctx *api.Checksum, instance api.BigBlueButtonInstance, a
 		return nil, fmt.Errorf("failed to retrieve %s method on bigbluebutton instance", methodName)
 	}
 
-	values := method.Call([]reflect.Value{reflect.ValueOf(ctx.Params)})
+	values := method.Call([]reflect.Value{reflect.ValueOf(requestCtx), reflect.ValueOf(ctx.Params)})
 	return values[0].Interface(), values[1].Interface()
 }
 
-func (s *Server) proxy(c *gin.Context, action string, endProcess func() error) {
+func (s *Server) proxy(c *gin.Context, action string, endProcess func(tenant *admin.Tenant) error) {
 	ctx := getAPIContext(c)
 	logger := getLogger(c)
-	meetingID, exists := c.GetQuery("meetingID")
+	meetingID, exists := ctx.GetParam("meetingID")
 	if !exists {
 		logger.Error("missing meeting id parameter")
 		missingMeetingIDParameter(c)
 		return
 	}
 
-	logger.addField("meeting_id", meetingID)
+	logger.AddField("meeting_id", meetingID)
 
-	instance, err := s.retrieveBBBBInstanceFromKey(MeetingMapKey(meetingID))
+	tenant := s.requestTenant(c, logger)
+	if tenant == nil {
+		return
+	}
+
+	instance, err := s.retrieveBBBBInstanceFromKey(c.Request.Context(), MeetingMapKey(meetingID), tenant)
 	if err != nil {
 		logger.Error(err)
 		ginMethod(action, c)(http.StatusOK, api.CreateError(api.MessageKeys().NotFound, api.Messages().NotFound))
 		return
 	}
 
-	response, mErr := callInstanceMethod(ctx, instance, action)
+	logger.AddField("instance", instance.URL)
+	response, mErr := callInstanceMethod(c.Request.Context(), ctx, instance, action)
 	if mErr != nil {
 		logger.Error(err)
 		c.XML(http.StatusInternalServerError, serverError("BigBlueSwarm failed to process api call"))
 		return
 	}
 
+	if info, ok := response.(*api.GetMeetingInfoResponse); ok && info != nil {
+		if info.ReturnCode == api.ReturnCodes().Success {
+			s.recordMeetingSnapshot(logger.Entry, tenant.Spec.Host, instance.URL, &info.MeetingInfo)
+		}
+
+		info.MeetingID = tenantMeetingID(c, tenant, info.MeetingID, info.Metadata())
+	}
+
 	if endProcess != nil {
-		err := endProcess()
+		err := endProcess(tenant)
 		if err != nil {
 			logger.Error(err)
 			c.XML(http.StatusInternalServerError, serverError("BigBlueSwarm failed to end api process"))
@@ -412,7 +747,7 @@ func (s *Server) GetMeetingInfo(c *gin.Context) {
 // GetRecordings handler get recordings for provided session. See https://docs.bigbluebutton.org/dev/api.html#getrecordings
 func (s *Server) GetRecordings(c *gin.Context) {
 	ctx := getAPIContext(c)
-	logger := getLogger(c).addField("action", ctx.Action)
+	logger := getLogger(c).AddField("action", ctx.Action)
 	emptyRecordingsResponse := &api.GetRecordingsResponse{
 		Response: api.Response{
 			ReturnCode: api.ReturnCodes().Success,
@@ -421,6 +756,11 @@ func (s *Server) GetRecordings(c *gin.Context) {
 		},
 	}
 
+	tenant := s.requestTenant(c, logger)
+	if tenant == nil {
+		return
+	}
+
 	instances, err := s.InstanceManager.ListInstances()
 	if err != nil {
 		logger.Errorln("manager failed to retrieve instances for getRecordings request", err)
@@ -435,16 +775,24 @@ func (s *Server) GetRecordings(c *gin.Context) {
 		Recordings: []api.Recording{},
 	}
 
-	for _, instance := range instances {
-		recordings, err := instance.GetRecordings(ctx.Params)
-		if err != nil {
-			logger.dup().addField("instance", instance.URL).Errorln("instance failed to retrieve recordings.", err)
+	skipped := []string{}
+	for _, result := range s.getRecordings(c.Request.Context(), instances, ctx.Params, s.instanceTimeout()) {
+		if result.err != nil {
+			logger.Dup().AddField("instance", result.instance.URL).Errorln("instance failed to retrieve recordings.", result.err)
+			skipped = append(skipped, result.instance.URL)
 			continue
 		}
 
-		response.Recordings = append(response.Recordings, recordings.Recordings...)
+		for _, recording := range result.value.Recordings {
+			if ownedByTenant(tenant, result.instance.URL, recording.Tenant()) {
+				recording.MeetingID = tenantMeetingID(c, tenant, recording.MeetingID, recording.Metadata())
+				response.Recordings = append(response.Recordings, recording)
+			}
+		}
 	}
 
+	s.reportSkippedInstances(c, skipped)
+
 	if len(response.Recordings) == 0 {
 		c.XML(http.StatusOK, emptyRecordingsResponse)
 		return
@@ -463,7 +811,7 @@ func (s *Server) DeleteRecordings(c *gin.Context) {
 	endProcess := func(response interface{}) error {
 		if deletion, ok := response.(*api.DeleteRecordingsResponse); ok && deletion.Deleted {
 			recordID, _ := c.GetQuery("recordID")
-			return s.Mapper.Remove(RecordingMapKey(recordID))
+			return s.mapper(c.Request.Context()).Remove(RecordingMapKey(recordID))
 		}
 
 		return nil
@@ -493,8 +841,13 @@ func (s *Server) PutRecordingTextTrack(c *gin.Context) {
 		return
 	}
 
-	logger.addField("record_id", recordID)
-	instance, err := s.retrieveBBBBInstanceFromKey(RecordingMapKey(recordID))
+	logger.AddField("record_id", recordID)
+	tenant := s.requestTenant(c, logger)
+	if tenant == nil {
+		return
+	}
+
+	instance, err := s.retrieveBBBBInstanceFromKey(c.Request.Context(), RecordingMapKey(recordID), tenant)
 	if err != nil {
 		logger.Error(err)
 		c.AbortWithStatusJSON(http.StatusOK, api.CreateJSONError(api.MessageKeys().NoRecordings, api.Messages().RecordingTextTrackNotFound))
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
@@ -34,6 +35,30 @@ var (
 	c         *gin.Context
 )
 
+func resetPoolMock() {
+	AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+		return MeetingAcquired, nil
+	}
+	AcquireParticipantPoolMockFunc = func(tenant string, meetingID string, limit int64) (bool, error) {
+		return true, nil
+	}
+	ReleaseMeetingPoolMockFunc = func(tenant string, meetingID string) error {
+		return nil
+	}
+}
+
+func resetHistoryMock() {
+	admin.RecordMeetingHistoryManagerMockFunc = func(meeting *admin.MeetingRecord) error {
+		return nil
+	}
+	admin.EndMeetingHistoryManagerMockFunc = func(tenant string, meetingID string, end time.Time) error {
+		return nil
+	}
+	admin.ReconcileMeetingHistoryManagerMockFunc = func(tenant string, running []string, now time.Time, grace time.Duration) ([]string, error) {
+		return []string{}, nil
+	}
+}
+
 func doGenericInitialization() *Server {
 	server := NewServer(&config.Config{})
 	server.Mapper = mapper
@@ -41,6 +66,15 @@ func doGenericInitialization() *Server {
 	server.TenantManager = &admin.TenantManagerMock{}
 	server.Balancer = &balancer.Mock{}
 	restclient.Client = &restclient.Mock{}
+	admin.ReserveMeetingUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) (bool, error) {
+		return true, nil
+	}
+	admin.ReleaseMeetingUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) error {
+		return nil
+	}
+	server.Pool = &PoolMock{}
+	server.HistoryManager = &admin.MeetingHistoryManagerMock{}
+	resetHistoryMock()
 
 	return server
 }
@@ -168,7 +202,7 @@ func TestCheckTenant(t *testing.T) {
 			Name: "an error returned by tenant manager should return an internal server error",
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return nil, errors.New("tenant manager error")
 				}
 			},
@@ -181,7 +215,7 @@ func TestCheckTenant(t *testing.T) {
 			Name: "a tenant not found should return a no found tenant error",
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return nil, nil
 				}
 			},
@@ -204,6 +238,145 @@ func TestCheckTenant(t *testing.T) {
 	}
 }
 
+func TestCheckRateLimit(t *testing.T) {
+	limit := &admin.RateLimit{Requests: 10}
+	tenant := &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost", RateLimits: map[string]*admin.RateLimit{"getMeetings": limit}}}
+	var key string
+	tests := []test.Test{
+		{
+			Name: "a request for an action without rate limit should not be limited",
+			Mock: func() {
+				c.Request.URL.Path = "/bigbluebutton/api/join"
+				AllowRateLimiterMockFunc = func(k string, l *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+					panic("rate limiter should not be called")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.False(t, c.IsAborted())
+			},
+		},
+		{
+			Name: "a request under the rate limit should not be limited",
+			Mock: func() {
+				AllowRateLimiterMockFunc = func(k string, l *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+					key = k
+					assert.Equal(t, limit, l)
+					return true, 0, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.False(t, c.IsAborted())
+				assert.Equal(t, RateLimitKey("localhost", "getMeetings"), key)
+			},
+		},
+		{
+			Name: "a rate limiter error should not limit the request",
+			Mock: func() {
+				AllowRateLimiterMockFunc = func(k string, l *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+					return false, 0, errors.New("redis error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.False(t, c.IsAborted())
+			},
+		},
+		{
+			Name: "a request over the rate limit should return a rate limit error with a retry after header",
+			Mock: func() {
+				AllowRateLimiterMockFunc = func(k string, l *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+					return false, 1500 * time.Millisecond, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.True(t, c.IsAborted())
+				assert.Equal(t, http.StatusTooManyRequests, w.Code)
+				assert.Equal(t, "2", w.Header().Get("Retry-After"))
+				assert.Equal(t, *rateLimitExceededError(), unMarshallError(w.Body.Bytes()))
+			},
+		},
+	}
+
+	server := doGenericInitialization()
+	server.RateLimiter = &RateLimiterMock{}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Set("logger", newRequestLogger())
+			c.Set("tenant", tenant)
+			request.SetRequestHost(c, "localhost")
+			c.Request.URL.Path = "/bigbluebutton/api/getMeetings"
+			test.Mock()
+			server.checkRateLimit(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestNamespaceMeetingIDs(t *testing.T) {
+	tenant := &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost", MeetingIDNamespace: admin.MeetingIDNamespacePrefix}}
+	var ctx *api.Checksum
+	tests := []test.Test{
+		{
+			Name: "a tenant without namespace should not alter the meeting id",
+			Mock: func() {
+				c.Set("tenant", &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "meetingID=1&name=test", ctx.Params)
+			},
+		},
+		{
+			Name: "a create call should namespace the meeting id and store the tenant meeting id in metadata",
+			Mock: func() {
+				ctx.Action = api.Create
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "name=test&meetingID=localhost_1&meta_bigblueswarm-meeting-id=1", ctx.Params)
+				assert.Equal(t, "1", tenantMeetingID(c, tenant, "localhost_1", nil))
+			},
+		},
+		{
+			Name: "a meeting id list should be namespaced",
+			Mock: func() {
+				ctx.Action = api.GetRecordings
+				ctx.Params = "meetingID=1,2"
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "meetingID=localhost_1%2Clocalhost_2", ctx.Params)
+			},
+		},
+	}
+
+	server := doGenericInitialization()
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			ctx = &api.Checksum{Action: api.Join, Params: "meetingID=1&name=test"}
+			c.Set("tenant", tenant)
+			test.Mock()
+			request.SetRequestParams(c, ctx.Params)
+			c.Set("api_ctx", ctx)
+			server.namespaceMeetingIDs(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
+
+func TestTenantMeetingID(t *testing.T) {
+	hashed := &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost", MeetingIDNamespace: admin.MeetingIDNamespaceHash}}
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	namespaced := hashed.NamespaceMeetingID("1")
+
+	assert.Equal(t, namespaced, tenantMeetingID(c, hashed, namespaced, nil))
+	assert.Equal(t, "1", tenantMeetingID(c, hashed, namespaced, map[string]string{api.MeetingIDMetadata: "1"}))
+	c.Set("meeting_ids", map[string]string{namespaced: "1"})
+	assert.Equal(t, "1", tenantMeetingID(c, hashed, namespaced, nil))
+	assert.Equal(t, "raw", tenantMeetingID(c, &admin.Tenant{Spec: &admin.TenantSpec{}}, "raw", map[string]string{api.MeetingIDMetadata: "1"}))
+}
+
 func TestHealthCheckRoute(t *testing.T) {
 	// Healthcheck has a single test. The method always returns success and the same response.
 	t.Run("Healtcheck should returns a valid response", func(t *testing.T) {
@@ -224,6 +397,8 @@ func TestHealthCheckRoute(t *testing.T) {
 
 func TestCreate(t *testing.T) {
 	creationParams := fmt.Sprintf("%s&name=test_name&attendeePW=pwd&moderatorPW=pwd2", params)
+	released := false
+	usageReleased := false
 
 	tests := []test.Test{
 		{
@@ -237,7 +412,7 @@ func TestCreate(t *testing.T) {
 				c.Set("api_ctx", checksum)
 				request.SetRequestHost(c, "localhost")
 				request.SetRequestParams(c, creationParams)
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return nil, errors.New("tenant manager error")
 				}
 			},
@@ -246,6 +421,26 @@ func TestCreate(t *testing.T) {
 				assert.Equal(t, *getTenantError(), unMarshallError(w.Body.Bytes()))
 			},
 		},
+		{
+			Name: "Creating a meeting with a meeting id owned by another tenant should return an id not unique error",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: creationParams, Action: api.Create})
+				request.SetRequestHost(c, "localhost")
+				request.SetRequestParams(c, creationParams)
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}}, nil
+				}
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(false)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("another-tenant.localhost")
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+					panic("pool should not be called")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, *meetingIDNotUniqueError(), unMarshallError(w.Body.Bytes()))
+			},
+		},
 		{
 			Name: "An error returned while checking if tenant reach meeting pool should return an internal http error - 500",
 			Mock: func() {
@@ -257,7 +452,8 @@ func TestCreate(t *testing.T) {
 				c.Set("api_ctx", checksum)
 				request.SetRequestHost(c, "localhost")
 				request.SetRequestParams(c, creationParams)
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "", 0).SetVal(true)
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					pool := int64(0)
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
@@ -266,9 +462,10 @@ func TestCreate(t *testing.T) {
 						Instances: []string{},
 					}, nil
 				}
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 0, errors.New("balancer error")
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+					return MeetingPoolFull, errors.New("pool error")
 				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -287,7 +484,8 @@ func TestCreate(t *testing.T) {
 				c.Set("api_ctx", checksum)
 				request.SetRequestHost(c, "localhost")
 				request.SetRequestParams(c, creationParams)
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "", 0).SetVal(true)
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					pool := int64(0)
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
@@ -296,9 +494,10 @@ func TestCreate(t *testing.T) {
 						Instances: []string{},
 					}, nil
 				}
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 10, nil
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+					return MeetingPoolFull, nil
 				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusForbidden, w.Code)
@@ -315,13 +514,15 @@ func TestCreate(t *testing.T) {
 				}
 				c.Set("api_ctx", checksum)
 				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "", 0).SetVal(true)
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec:      &admin.TenantSpec{},
 						Instances: []string{},
 					}, nil
 				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -337,8 +538,9 @@ func TestCreate(t *testing.T) {
 				}
 				c.Set("api_ctx", checksum)
 				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -351,12 +553,93 @@ func TestCreate(t *testing.T) {
 				balancer.BalancerMockProcessFunc = func(instances []string) (string, error) {
 					return "", errors.New("balancer error")
 				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
 				assert.Equal(t, *noInstanceFoundError(), unMarshallError(w.Body.Bytes()))
 			},
 		},
+		{
+			Name: "A failed creation should release the meeting newly acquired in the tenant pool",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: creationParams, Action: api.Create})
+				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
+				request.SetRequestHost(c, "localhost")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}, Instances: []string{instance}}, nil
+				}
+				balancer.BalancerMockProcessFunc = func(instances []string) (string, error) {
+					return "", errors.New("balancer error")
+				}
+				released = false
+				ReleaseMeetingPoolMockFunc = func(tenant string, meetingID string) error {
+					released = true
+					return nil
+				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.True(t, released)
+				assert.True(t, usageReleased)
+			},
+		},
+		{
+			Name: "Creating a meeting when the meetings quota is exhausted should return a quota error and release the meeting pool",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: creationParams, Action: api.Create})
+				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
+				request.SetRequestHost(c, "localhost")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}, Instances: []string{instance}}, nil
+				}
+				admin.ReserveMeetingUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) (bool, error) {
+					return false, nil
+				}
+				admin.ReleaseMeetingUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) error {
+					panic("an unreserved meeting usage should not be released")
+				}
+				released = false
+				ReleaseMeetingPoolMockFunc = func(tenant string, meetingID string) error {
+					released = true
+					return nil
+				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Equal(t, *meetingsQuotaExceededError(), unMarshallError(w.Body.Bytes()))
+				assert.True(t, released)
+			},
+		},
+		{
+			Name: "A failed creation should not release a meeting already acquired in the tenant pool",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: creationParams, Action: api.Create})
+				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(false)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
+				request.SetRequestHost(c, "localhost")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}, Instances: []string{instance}}, nil
+				}
+				AcquireMeetingPoolMockFunc = func(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+					return MeetingAlreadyAcquired, nil
+				}
+				balancer.BalancerMockProcessFunc = func(instances []string) (string, error) {
+					return "", errors.New("balancer error")
+				}
+				ReleaseMeetingPoolMockFunc = func(tenant string, meetingID string) error {
+					panic("running meeting should not be released")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
 		{
 			Name: "An error thrown by InstanceManager while getting target instance should return an internal server error",
 			Mock: func() {
@@ -367,8 +650,9 @@ func TestCreate(t *testing.T) {
 				}
 				c.Set("api_ctx", checksum)
 				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -382,6 +666,7 @@ func TestCreate(t *testing.T) {
 					return instance, nil
 				}
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetErr(errors.New("redis error"))
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -397,8 +682,9 @@ func TestCreate(t *testing.T) {
 				}
 				c.Set("api_ctx", checksum)
 				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -415,6 +701,7 @@ func TestCreate(t *testing.T) {
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					return nil, errors.New("bbb error")
 				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -430,8 +717,9 @@ func TestCreate(t *testing.T) {
 				}
 				c.Set("api_ctx", checksum)
 				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -466,11 +754,49 @@ func TestCreate(t *testing.T) {
 					}, nil
 				}
 				redisMock.ExpectSet(MeetingMapKey(meetingID), instance, 0).SetErr(errors.New("redis error"))
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
 			},
 		},
+		{
+			Name: "A creation failed by the instance should not map the meeting and release the meeting owner",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: creationParams, Action: api.Create})
+				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
+				request.SetRequestHost(c, "localhost")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}, Instances: []string{instance}}, nil
+				}
+				balancer.BalancerMockProcessFunc = func(instances []string) (string, error) {
+					return instance, nil
+				}
+				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					response, err := xml.Marshal(&api.CreateResponse{
+						Response:  api.Response{ReturnCode: api.ReturnCodes().Failed, MessageKey: "invalidParams"},
+						MeetingID: meetingID,
+					})
+					if err != nil {
+						panic(err)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader(response)),
+					}, nil
+				}
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, api.ReturnCodes().Failed, unMarshallCreateResponse(w.Body.Bytes()).ReturnCode)
+				assert.True(t, usageReleased)
+				assert.Nil(t, redisMock.ExpectationsWereMet())
+			},
+		},
 		{
 			Name: "A valid request should return a valid response",
 			Mock: func() {
@@ -481,8 +807,9 @@ func TestCreate(t *testing.T) {
 				}
 				c.Set("api_ctx", checksum)
 				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -521,12 +848,71 @@ func TestCreate(t *testing.T) {
 			Validator: func(t *testing.T, value interface{}, err error) {
 				response := unMarshallCreateResponse(w.Body.Bytes())
 				assert.Equal(t, http.StatusOK, w.Code)
+				assert.False(t, usageReleased)
 				assert.Equal(t, api.ReturnCodes().Success, response.ReturnCode)
 				assert.Equal(t, meetingID, response.MeetingID)
 				assert.Equal(t, "pwd", response.AttendeePW)
 				assert.Equal(t, "pwd2", response.ModeratorPW)
 			},
 		},
+		{
+			Name: "Tenant create parameters and maximum meeting duration should be applied before calling the instance",
+			Mock: func() {
+				maxDuration := int64(90)
+				checksum := &api.Checksum{
+					Secret: test.DefaultSecret(),
+					Params: fmt.Sprintf("%s&record=true&duration=240", creationParams),
+					Action: api.Create,
+				}
+				c.Set("api_ctx", checksum)
+				request.SetRequestParams(c, creationParams)
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
+				request.SetRequestHost(c, "localhost")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{
+						Spec: &admin.TenantSpec{
+							Host: "localhost",
+							CreateParameters: &admin.CreateParameters{
+								Defaults: map[string]string{"welcome": "hello"},
+								Enforced: map[string]string{"record": "false"},
+							},
+							MaxMeetingDuration: &maxDuration,
+						},
+						Instances: []string{
+							"http://localhost/bigbuebutton",
+						},
+					}, nil
+				}
+				balancer.BalancerMockProcessFunc = func(instances []string) (string, error) {
+					return instance, nil
+				}
+				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					query := req.URL.Query()
+					assert.Equal(t, "false", query.Get("record"))
+					assert.Equal(t, "hello", query.Get("welcome"))
+					assert.Equal(t, "90", query.Get("duration"))
+					assert.Equal(t, "localhost", query.Get("meta_bigblueswarm-tenant"))
+
+					response, err := xml.Marshal(&api.CreateResponse{
+						Response:  api.Response{ReturnCode: api.ReturnCodes().Success},
+						MeetingID: meetingID,
+					})
+					if err != nil {
+						panic(err)
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader(response)),
+					}, nil
+				}
+				redisMock.ExpectSet(MeetingMapKey(meetingID), instance, 0).SetVal(meetingID)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -534,8 +920,14 @@ func TestCreate(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
-			test.Mock()
+			resetPoolMock()
 			server := doGenericInitialization()
+			usageReleased = false
+			admin.ReleaseMeetingUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) error {
+				usageReleased = true
+				return nil
+			}
+			test.Mock()
 			server.Create(c)
 			test.Validator(t, nil, nil)
 		})
@@ -548,7 +940,7 @@ func TestJoin(t *testing.T) {
 			Name: "An error returned by TenantManager should return an internal server error 500 and a xml server error",
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return nil, errors.New("tenant manager error")
 				}
 			},
@@ -563,8 +955,9 @@ func TestJoin(t *testing.T) {
 			Name: "If a tenant reached the user pool, it should return a forbidden error and a reacher user pool error",
 			Mock: func() {
 				request.SetRequestHost(c, "localhost")
+				request.SetRequestParams(c, params)
 				pool := int64(10)
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host:     "localhost",
@@ -572,8 +965,11 @@ func TestJoin(t *testing.T) {
 						},
 					}, nil
 				}
-				balancer.BalancerGetCurrentStateFunc = func(measurement, field string) (int64, error) {
-					return 10, nil
+				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
+				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
+				AcquireParticipantPoolMockFunc = func(tenant string, meetingID string, limit int64) (bool, error) {
+					return false, nil
 				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
@@ -592,7 +988,7 @@ func TestJoin(t *testing.T) {
 					Action: api.IsMeetingRunning,
 				}
 				c.Set("api_ctx", checksum)
-				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
 					return &admin.Tenant{
 						Spec: &admin.TenantSpec{
 							Host: "localhost",
@@ -609,7 +1005,7 @@ func TestJoin(t *testing.T) {
 			},
 		},
 		{
-			Name: "Providing a meeting id that does not exists should return a not found error",
+			Name: "Providing a meeting id that does not exists should return a not found error without acquiring a participant",
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal("")
@@ -619,6 +1015,9 @@ func TestJoin(t *testing.T) {
 					Action: api.IsMeetingRunning,
 				}
 				c.Set("api_ctx", checksum)
+				AcquireParticipantPoolMockFunc = func(tenant string, meetingID string, limit int64) (bool, error) {
+					panic("a participant should not be acquired for an unknown meeting")
+				}
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				response := unMarshallError(w.Body.Bytes())
@@ -633,6 +1032,7 @@ func TestJoin(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal("")
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -649,11 +1049,24 @@ func TestJoin(t *testing.T) {
 				assert.Equal(t, api.Messages().NotFound, response.Message)
 			},
 		},
+		{
+			Name: "Joining a meeting owned by another tenant should return a not found error",
+			Mock: func() {
+				request.SetRequestParams(c, params)
+				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("another-tenant.localhost")
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, api.MessageKeys().NotFound, unMarshallError(w.Body.Bytes()).MessageKey)
+			},
+		},
 		{
 			Name: "A valid request should redirect to the meeting url",
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -672,6 +1085,7 @@ func TestJoin(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, fmt.Sprintf("%s&redirect=false", params))
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -692,6 +1106,7 @@ func TestJoin(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, fmt.Sprintf("%s&redirect=false", params))
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -734,8 +1149,9 @@ func TestJoin(t *testing.T) {
 			c.Set("logger", newRequestLogger())
 			c.Set("api_ctx", &api.Checksum{
 				Action: "join",
-				Params: "params",
+				Params: params,
 			})
+			resetPoolMock()
 			test.Mock()
 			server := doGenericInitialization()
 			server.Join(c)
@@ -759,6 +1175,7 @@ func TestEnd(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -766,7 +1183,7 @@ func TestEnd(t *testing.T) {
 					Action: api.IsMeetingRunning,
 				}
 				c.Set("api_ctx", checksum)
-				redisMock.ExpectDel(MeetingMapKey(meetingID)).SetErr(errors.New("error"))
+				redisMock.ExpectDel(MeetingMapKey(meetingID), OwnerKey(MeetingMapKey(meetingID))).SetErr(errors.New("error"))
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					endResponse := &api.EndResponse{
 						Response: api.Response{
@@ -789,11 +1206,28 @@ func TestEnd(t *testing.T) {
 				assert.Equal(t, http.StatusInternalServerError, w.Code)
 			},
 		},
+		{
+			Name: "Ending a meeting owned by another tenant should return a not found error",
+			Mock: func() {
+				request.SetRequestParams(c, params)
+				c.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: params, Action: api.End})
+				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("another-tenant.localhost")
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					panic("instance should not be called")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, api.MessageKeys().NotFound, unMarshallError(w.Body.Bytes()).MessageKey)
+			},
+		},
 		{
 			Name: "A valid end call should return a success response",
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -801,7 +1235,7 @@ func TestEnd(t *testing.T) {
 					Action: api.IsMeetingRunning,
 				}
 				c.Set("api_ctx", checksum)
-				redisMock.ExpectDel(MeetingMapKey(meetingID)).SetVal(1)
+				redisMock.ExpectDel(MeetingMapKey(meetingID), OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					endResponse := &api.EndResponse{
 						Response: api.Response{
@@ -833,6 +1267,8 @@ func TestEnd(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			test.Mock()
 			server := doGenericInitialization()
 			server.End(c)
@@ -886,6 +1322,7 @@ func TestIsMeetingRunning(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal("")
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -907,6 +1344,7 @@ func TestIsMeetingRunning(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -927,6 +1365,7 @@ func TestIsMeetingRunning(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -965,6 +1404,8 @@ func TestIsMeetingRunning(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			test.Mock()
 			server := doGenericInitialization()
 			server.IsMeetingRunning(c)
@@ -987,6 +1428,7 @@ func TestGetMeetingInfo(t *testing.T) {
 			Mock: func() {
 				request.SetRequestParams(c, params)
 				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
 				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
 				checksum := &api.Checksum{
 					Secret: test.DefaultSecret(),
@@ -1027,6 +1469,8 @@ func TestGetMeetingInfo(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			server := doGenericInitialization()
 			test.Mock()
 			server.GetMeetingInfo(c)
@@ -1035,8 +1479,72 @@ func TestGetMeetingInfo(t *testing.T) {
 	}
 }
 
+// dedicatedTenant resolves a tenant owning the test instances, so meetings and recordings without tenant metadata are
+// returned
+func dedicatedTenant(hostname string) (*admin.Tenant, error) {
+	return &admin.Tenant{
+		Spec:      &admin.TenantSpec{Host: "localhost"},
+		Instances: []string{"http://localhost/bigbluebutton", "http://localhost:8080/bigbluebutton"},
+	}, nil
+}
+
+func sharedInstanceResponse(body string) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil
+	}
+}
+
 func TestGetMeetings(t *testing.T) {
+	var server *Server
 	tests := []test.Test{
+		{
+			Name: "An error returned by the tenant manager should return an internal server error",
+			Mock: func() {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return nil, errors.New("tenant manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.Equal(t, *getTenantError(), unMarshallError(w.Body.Bytes()))
+			},
+		},
+		{
+			Name: "A tenant should only retrieve its own meetings from a shared instance",
+			Mock: func() {
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "tenant-a.localhost"}}, nil
+				}
+				redisMock.ExpectHGetAll(admin.BBSInstances).SetVal(map[string]string{
+					"http://localhost/bigbluebutton": test.DefaultSecret(),
+				})
+				restclient.RestClientMockDoFunc = sharedInstanceResponse(`<response>
+					<returncode>SUCCESS</returncode>
+					<meetings>
+						<meeting>
+							<meetingID>tenant-a-meeting</meetingID>
+							<metadata><bigblueswarm-tenant>tenant-a.localhost</bigblueswarm-tenant></metadata>
+						</meeting>
+						<meeting>
+							<meetingID>tenant-b-meeting</meetingID>
+							<metadata><bigblueswarm-tenant>tenant-b.localhost</bigblueswarm-tenant></metadata>
+						</meeting>
+						<meeting>
+							<meetingID>untagged-meeting</meetingID>
+						</meeting>
+					</meetings>
+				</response>`)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				response := unMarshallGetMeetingsResponse(w.Body.Bytes())
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, 1, len(response.Meetings))
+				assert.Equal(t, "tenant-a-meeting", response.Meetings[0].MeetingID)
+			},
+		},
 		{
 			Name: "An error thrown by instance manager should return an http internal error status",
 			Mock: func() {
@@ -1121,6 +1629,32 @@ func TestGetMeetings(t *testing.T) {
 				assert.Equal(t, api.ReturnCodes().Success, response.ReturnCode)
 				assert.Equal(t, 1, len(response.Meetings))
 				assert.Equal(t, "meeting-id", response.Meetings[0].MeetingID)
+				assert.Empty(t, w.Header().Get(SkippedInstancesHeader))
+			},
+		},
+		{
+			Name: "An instance exceeding the instance timeout should be reported as skipped when enabled",
+			Mock: func() {
+				server.Config.BigBlueSwarm.InstanceTimeout = "10ms"
+				server.Config.BigBlueSwarm.ReportSkippedInstances = true
+				redisMock.ExpectHGetAll(admin.BBSInstances).SetVal(map[string]string{
+					"http://localhost/bigbluebutton":      test.DefaultSecret(),
+					"http://localhost:8080/bigbluebutton": test.DefaultSecret(),
+				})
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					if req.URL.Host == "localhost:8080" {
+						<-req.Context().Done()
+						return nil, req.Context().Err()
+					}
+
+					return sharedInstanceResponse(`<response><returncode>SUCCESS</returncode><meetings><meeting><meetingID>meeting-id</meetingID></meeting></meetings></response>`)(req)
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				response := unMarshallGetMeetingsResponse(w.Body.Bytes())
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, 1, len(response.Meetings))
+				assert.Equal(t, "http://localhost:8080/bigbluebutton", w.Header().Get(SkippedInstancesHeader))
 			},
 		},
 	}
@@ -1130,7 +1664,9 @@ func TestGetMeetings(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
-			server := doGenericInitialization()
+			server = doGenericInitialization()
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			test.Mock()
 			server.GetMeetings(c)
 			test.Validator(t, nil, nil)
@@ -1153,6 +1689,53 @@ func TestGetRecodings(t *testing.T) {
 	log.AddHook(logHook)
 
 	tests := []test.Test{
+		{
+			Name: "A tenant should only retrieve its own recordings from a shared instance",
+			Mock: func() {
+				c.Set("api_ctx", checksum)
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "tenant-a.localhost"}}, nil
+				}
+				redisMock.ExpectHGetAll(admin.BBSInstances).SetVal(instances)
+				restclient.RestClientMockDoFunc = sharedInstanceResponse(`<response>
+					<returncode>SUCCESS</returncode>
+					<recordings>
+						<recording>
+							<recordID>tenant-a-record</recordID>
+							<metadata><bigblueswarm-tenant>tenant-a.localhost</bigblueswarm-tenant></metadata>
+						</recording>
+						<recording>
+							<recordID>tenant-b-record</recordID>
+							<metadata><bigblueswarm-tenant>tenant-b.localhost</bigblueswarm-tenant></metadata>
+						</recording>
+						<recording>
+							<recordID>untagged-record</recordID>
+						</recording>
+					</recordings>
+				</response>`)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				response := unMarshallGetRecordingsResponse(w.Body.Bytes())
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, 1, len(response.Recordings))
+				assert.Equal(t, "tenant-a-record", response.Recordings[0].RecordID)
+			},
+		},
+		{
+			Name: "A tenant without recordings on a shared instance should return a no recordings response",
+			Mock: func() {
+				c.Set("api_ctx", checksum)
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "tenant-c.localhost"}}, nil
+				}
+				redisMock.ExpectHGetAll(admin.BBSInstances).SetVal(instances)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				response := unMarshallGetRecordingsResponse(w.Body.Bytes())
+				assert.Equal(t, api.MessageKeys().NoRecordings, response.MessageKey)
+				assert.Equal(t, 0, len(response.Recordings))
+			},
+		},
 		{
 			Name: "An error returned by the instance manager ListInstance method should return a no recordings response",
 			Mock: func() {
@@ -1263,6 +1846,8 @@ func TestGetRecodings(t *testing.T) {
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
 			server := doGenericInitialization()
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			test.Mock()
 			server.GetRecordings(c)
 			test.Validator(t, nil, nil)
@@ -1328,6 +1913,8 @@ func TestUpdateRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				mock := redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton")
 				mock.SetVal("")
 				mock.SetErr(errors.New("redis error"))
@@ -1353,6 +1940,8 @@ func TestUpdateRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					return nil, errors.New("http error")
@@ -1375,6 +1964,8 @@ func TestUpdateRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					recordings := &api.UpdateRecordingsResponse{
@@ -1407,6 +1998,8 @@ func TestUpdateRecordings(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			server := doGenericInitialization()
 			test.Mock()
 			server.UpdateRecordings(c)
@@ -1419,6 +2012,41 @@ func TestDeleteRecordings(t *testing.T) {
 	// Because the DeleteRecordings uses the proxyRecordings method that is already tested by UpdateRecording,
 	// DeleteRecordings test will only test the end process method and a valid test case
 	tests := []test.Test{
+		{
+			Name: "Deleting a recording owned by another tenant should return a not found error",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Params: "recordID=record-id", Secret: test.DefaultSecret(), Action: api.DeleteRecordings})
+				request.SetRequestParams(c, "recordID=record-id")
+				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("another-tenant.localhost")
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					panic("instance should not be called")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, api.MessageKeys().NotFound, unMarshallError(w.Body.Bytes()).MessageKey)
+			},
+		},
+		{
+			Name: "Deleting a recording without owner hosted on a shared instance should return a not found error",
+			Mock: func() {
+				c.Set("api_ctx", &api.Checksum{Params: "recordID=record-id", Secret: test.DefaultSecret(), Action: api.DeleteRecordings})
+				request.SetRequestParams(c, "recordID=record-id")
+				admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}, Instances: []string{"http://localhost/bigbluebutton"}}, nil
+				}
+				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).RedisNil()
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					panic("instance should not be called")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, api.MessageKeys().NotFound, unMarshallError(w.Body.Bytes()).MessageKey)
+			},
+		},
 		{
 			Name: "A valid request with deleted=false should not delete the recording",
 			Mock: func() {
@@ -1432,6 +2060,8 @@ func TestDeleteRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					recordings := &api.DeleteRecordingsResponse{
@@ -1470,6 +2100,8 @@ func TestDeleteRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					recordings := &api.DeleteRecordingsResponse{
@@ -1487,7 +2119,7 @@ func TestDeleteRecordings(t *testing.T) {
 						Body:       ioutil.NopCloser(bytes.NewReader(response)),
 					}, nil
 				}
-				mock := redisMock.ExpectDel(RecordingMapKey("record-id"))
+				mock := redisMock.ExpectDel(RecordingMapKey("record-id"), OwnerKey(RecordingMapKey("record-id")))
 				mock.SetErr(errors.New("redis error"))
 				mock.SetVal(0)
 			},
@@ -1508,6 +2140,8 @@ func TestDeleteRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					recordings := &api.DeleteRecordingsResponse{
@@ -1525,7 +2159,7 @@ func TestDeleteRecordings(t *testing.T) {
 						Body:       ioutil.NopCloser(bytes.NewReader(response)),
 					}, nil
 				}
-				redisMock.ExpectDel(RecordingMapKey("record-id")).SetVal(1)
+				redisMock.ExpectDel(RecordingMapKey("record-id"), OwnerKey(RecordingMapKey("record-id"))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, _ error) {
 				response := unMarshallDeleteRecordingsResponse(w.Body.Bytes())
@@ -1541,6 +2175,8 @@ func TestDeleteRecordings(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			server := doGenericInitialization()
 			test.Mock()
 			server.DeleteRecordings(c)
@@ -1566,6 +2202,8 @@ func TestPublishRecordings(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id&published=true")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					recordings := &api.PublishRecordingsResponse{
@@ -1598,6 +2236,8 @@ func TestPublishRecordings(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			server := doGenericInitialization()
 			test.Mock()
 			server.PublishRecordings(c)
@@ -1623,6 +2263,8 @@ func TestGetRecordingsTextTracks(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost:8080/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost:8080/bigbluebutton").SetVal(test.DefaultSecret())
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					tracks := &api.GetRecordingsTextTracksResponse{
@@ -1670,6 +2312,8 @@ func TestGetRecordingsTextTracks(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			server := doGenericInitialization()
 			test.Mock()
 			server.GetRecordingsTextTracks(c)
@@ -1735,6 +2379,8 @@ func TestPutRecordingTextTrack(t *testing.T) {
 				request.SetRequestParams(c, "recordID=record-id")
 
 				redisMock.ExpectGet(RecordingMapKey("record-id")).SetVal("http://localhost/bigbluebutton")
+				redisMock.ExpectGet(OwnerKey(RecordingMapKey("record-id"))).SetVal("localhost")
+
 				redisMock.ExpectHGet(admin.BBSInstances, "http://localhost/bigbluebutton").SetVal(test.DefaultSecret())
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
@@ -1749,6 +2395,8 @@ func TestPutRecordingTextTrack(t *testing.T) {
 			w = httptest.NewRecorder()
 			c, _ = gin.CreateTestContext(w)
 			c.Set("logger", newRequestLogger())
+			request.SetRequestHost(c, "localhost")
+			admin.ResolveTenantTenantManagerMockFunc = dedicatedTenant
 			server := doGenericInitialization()
 			test.Mock()
 			server.PutRecordingTextTrack(c)
@@ -1756,3 +2404,96 @@ func TestPutRecordingTextTrack(t *testing.T) {
 		})
 	}
 }
+
+func TestTenantQuotas(t *testing.T) {
+	quota := int64(10)
+	secret := test.DefaultSecret()
+	var usage *admin.QuotaUsage
+	var handler func(s *Server, c *gin.Context)
+	tests := []test.Test{
+		{
+			Name: "An error returned while retrieving quota usage should return an internal server error",
+			Mock: func() {
+				handler = (*Server).Create
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
+				admin.GetQuotaUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) (*admin.QuotaUsage, error) {
+					return nil, errors.New("tenant manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.Equal(t, *serverError("BigBlueSwarm failed to check your tenant quotas."), unMarshallError(w.Body.Bytes()))
+			},
+		},
+		{
+			Name: "Creating a meeting when the meetings quota is exhausted should return a quota error",
+			Mock: func() {
+				handler = (*Server).Create
+				redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(meetingID)), "localhost", 0).SetVal(true)
+				redisMock.ExpectDel(OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
+				usage = &admin.QuotaUsage{}
+				admin.ReserveMeetingUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) (bool, error) {
+					return false, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Equal(t, *meetingsQuotaExceededError(), unMarshallError(w.Body.Bytes()))
+			},
+		},
+		{
+			Name: "Joining a meeting when the participant minutes quota is exhausted should return a quota error",
+			Mock: func() {
+				handler = (*Server).Join
+				usage = &admin.QuotaUsage{ParticipantMinutes: 10, ParticipantMinutesQuota: &quota}
+				redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal(instance)
+				redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
+				redisMock.ExpectHGet(admin.BBSInstances, instance).SetVal(test.DefaultSecret())
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Equal(t, *participantMinutesQuotaExceededError(), unMarshallError(w.Body.Bytes()))
+			},
+		},
+		{
+			Name: "An exhausted meetings quota should not prevent users from joining",
+			Mock: func() {
+				handler = (*Server).Join
+				usage = &admin.QuotaUsage{Meetings: 10, MeetingsQuota: &quota}
+				request.SetRequestParams(c, "")
+				c.Set("api_ctx", &api.Checksum{Secret: secret, Action: api.Join})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, api.MessageKeys().ValidationError, unMarshallError(w.Body.Bytes()).MessageKey)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Set("logger", newRequestLogger())
+			c.Set("api_ctx", &api.Checksum{Secret: secret, Params: params, Action: api.Create})
+			request.SetRequestHost(c, "localhost")
+			request.SetRequestParams(c, params)
+			admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+				return &admin.Tenant{
+					Spec: &admin.TenantSpec{
+						Host:   "localhost",
+						Quotas: &admin.TenantQuotas{Meetings: &quota, ParticipantMinutes: &quota},
+					},
+				}, nil
+			}
+			admin.GetQuotaUsageTenantManagerMockFunc = func(tenant *admin.Tenant, now time.Time) (*admin.QuotaUsage, error) {
+				return usage, nil
+			}
+			server := doGenericInitialization()
+			test.Mock()
+			handler(server, c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
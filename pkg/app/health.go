@@ -0,0 +1,130 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ReadinessCheckRedis is the readiness check of the redis database
+	ReadinessCheckRedis = "redis"
+	// ReadinessCheckMetrics is the readiness check of the instances metrics backend
+	ReadinessCheckMetrics = "metrics_backend"
+	// ReadinessCheckInstances is the readiness check of the online instances
+	ReadinessCheckInstances = "instances"
+	// ReadinessCheckShutdown is the readiness check failing once the shutdown is requested
+	ReadinessCheckShutdown = "shutdown"
+)
+
+const readinessCheckOK = "ok"
+
+// Readiness represents the readiness probe response. Checks contains the result of each check, `ok` if it passed
+type Readiness struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Liveness handler always returns a HTTP 200 - OK while the process is able to serve requests
+func (s *Server) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readiness checks that redis and the metrics backend are reachable and that at least one instance is online. The
+// checks after a failing dependency are not run
+func (s *Server) readiness() *Readiness {
+	readiness := &Readiness{Checks: map[string]string{}}
+	if s.shuttingDown.Load() {
+		readiness.Checks[ReadinessCheckShutdown] = "shutting down"
+		return readiness
+	}
+
+	instances, err := s.InstanceManager.List()
+	if err != nil {
+		readiness.Checks[ReadinessCheckRedis] = err.Error()
+		return readiness
+	}
+
+	readiness.Checks[ReadinessCheckRedis] = readinessCheckOK
+	if len(instances) == 0 {
+		readiness.Checks[ReadinessCheckInstances] = "no instance registered"
+		return readiness
+	}
+
+	status, err := s.clusterStatus(instances)
+	if err != nil {
+		readiness.Checks[ReadinessCheckMetrics] = err.Error()
+		return readiness
+	}
+
+	readiness.Checks[ReadinessCheckMetrics] = readinessCheckOK
+	online := 0
+	for _, instance := range status {
+		if instance.APIStatus == "Up" {
+			online++
+		}
+	}
+
+	if online == 0 {
+		readiness.Checks[ReadinessCheckInstances] = "no instance online"
+		return readiness
+	}
+
+	readiness.Checks[ReadinessCheckInstances] = fmt.Sprintf("%d online", online)
+	readiness.Ready = true
+	return readiness
+}
+
+// clusterStatus returns the instances status, or an error if the metrics backend does not respond before the readiness
+// timeout
+func (s *Server) clusterStatus(instances []string) ([]balancer.InstanceStatus, error) {
+	timeout := s.readinessTimeout()
+	if timeout <= 0 {
+		return s.Balancer.ClusterStatus(instances)
+	}
+
+	type result struct {
+		status []balancer.InstanceStatus
+		err    error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		status, err := s.Balancer.ClusterStatus(instances)
+		results <- result{status: status, err: err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.status, r.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("no response after %s", timeout)
+	}
+}
+
+// readinessTimeout returns the configured deadline of the metrics backend check
+func (s *Server) readinessTimeout() time.Duration {
+	if s.Config.BigBlueSwarm.ReadinessTimeout == "" {
+		return 0
+	}
+
+	return toDuration(s.Config.BigBlueSwarm.ReadinessTimeout)
+}
+
+// Readiness handler returns a HTTP 200 - OK if BigBlueSwarm is ready to balance meetings, otherwise a HTTP 503 -
+// Service Unavailable. It fails once the shutdown is requested so the load balancers stop sending requests
+func (s *Server) Readiness(c *gin.Context) {
+	readiness := s.readiness()
+	if !readiness.Ready {
+		log.WithField("checks", readiness.Checks).Warn("readiness check failed")
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+
+	c.JSON(http.StatusOK, readiness)
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/test_utils/pkg/test"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveness(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	(&Server{}).Liveness(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestReadiness(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	server := &Server{
+		Config:          &config.Config{BigBlueSwarm: config.BigBlueSwarm{ReadinessTimeout: "50ms"}},
+		InstanceManager: &admin.InstanceManagerMock{},
+		Balancer:        &balancer.Mock{},
+	}
+
+	readiness := func() *Readiness {
+		r := &Readiness{}
+		json.Unmarshal(w.Body.Bytes(), r)
+		return r
+	}
+
+	admin.ListInstanceManagerMockFunc = func() ([]string, error) {
+		return []string{"http://localhost/bigbluebutton", "http://localhost:8080/bigbluebutton"}, nil
+	}
+
+	tests := []test.Test{
+		{
+			Name: "a redis error should return a HTTP 503 - Service Unavailable",
+			Mock: func() {
+				admin.ListInstanceManagerMockFunc = func() ([]string, error) {
+					return nil, errors.New("redis error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "redis error", readiness().Checks[ReadinessCheckRedis])
+			},
+		},
+		{
+			Name: "no registered instance should return a HTTP 503 - Service Unavailable",
+			Mock: func() {
+				admin.ListInstanceManagerMockFunc = func() ([]string, error) {
+					return []string{}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "ok", readiness().Checks[ReadinessCheckRedis])
+			},
+		},
+		{
+			Name: "a metrics backend error should return a HTTP 503 - Service Unavailable",
+			Mock: func() {
+				admin.ListInstanceManagerMockFunc = func() ([]string, error) {
+					return []string{"http://localhost/bigbluebutton"}, nil
+				}
+				balancer.BalancerMockClusterStatusFunc = func(instances []string) ([]balancer.InstanceStatus, error) {
+					return nil, errors.New("influxdb error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "influxdb error", readiness().Checks[ReadinessCheckMetrics])
+			},
+		},
+		{
+			Name: "a metrics backend not responding before the readiness timeout should return a HTTP 503 - Service Unavailable",
+			Mock: func() {
+				balancer.BalancerMockClusterStatusFunc = func(instances []string) ([]balancer.InstanceStatus, error) {
+					time.Sleep(time.Second)
+					return []balancer.InstanceStatus{{Host: "http://localhost/bigbluebutton", APIStatus: "Up"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "no response after 50ms", readiness().Checks[ReadinessCheckMetrics])
+			},
+		},
+		{
+			Name: "no online instance should return a HTTP 503 - Service Unavailable",
+			Mock: func() {
+				balancer.BalancerMockClusterStatusFunc = func(instances []string) ([]balancer.InstanceStatus, error) {
+					return []balancer.InstanceStatus{{Host: "http://localhost/bigbluebutton", APIStatus: "Down"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "no instance online", readiness().Checks[ReadinessCheckInstances])
+			},
+		},
+		{
+			Name: "an online instance should return a HTTP 200 - OK",
+			Mock: func() {
+				balancer.BalancerMockClusterStatusFunc = func(instances []string) ([]balancer.InstanceStatus, error) {
+					return []balancer.InstanceStatus{{Host: "http://localhost/bigbluebutton", APIStatus: "Up"}}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.True(t, readiness().Ready)
+				assert.Equal(t, "1 online", readiness().Checks[ReadinessCheckInstances])
+			},
+		},
+		{
+			Name: "a shutting down server should return a HTTP 503 - Service Unavailable",
+			Mock: func() {
+				server.shuttingDown.Store(true)
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				assert.Equal(t, "shutting down", readiness().Checks[ReadinessCheckShutdown])
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			w = httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			test.Mock()
+			server.Readiness(c)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// historyRecordID returns the history record identifier of a meeting. Meeting identifiers may be reused once a meeting
+// ended so the record is identified by the meeting identifier and its creation time
+func historyRecordID(meetingID string, createTime string) string {
+	if createTime == "" {
+		return meetingID
+	}
+
+	return meetingID + "-" + createTime
+}
+
+// meetingCreateTime parses a meeting creation time in milliseconds. It returns the current time if it is invalid
+func meetingCreateTime(createTime string) time.Time {
+	value, err := strconv.ParseInt(createTime, 10, 64)
+	if err != nil || value <= 0 {
+		return time.Now().UTC()
+	}
+
+	return time.UnixMilli(value).UTC()
+}
+
+// recordMeetingCreation creates the history record of a meeting created on the instance
+func (s *Server) recordMeetingCreation(logger *log.Entry, tenant string, instance string, response *api.CreateResponse) {
+	err := s.HistoryManager.Record(&admin.MeetingRecord{
+		ID:        historyRecordID(response.MeetingID, response.CreateTime),
+		MeetingID: response.MeetingID,
+		Tenant:    tenant,
+		Instance:  instance,
+		StartTime: meetingCreateTime(response.CreateTime),
+	})
+
+	if err != nil {
+		logger.Errorln("failed to record meeting creation in meetings history.", err)
+	}
+}
+
+// recordMeetingSnapshot updates the history record of a meeting running on the instance
+func (s *Server) recordMeetingSnapshot(logger *log.Entry, tenant string, instance string, meeting *api.MeetingInfo) {
+	err := s.HistoryManager.Record(&admin.MeetingRecord{
+		ID:               historyRecordID(meeting.MeetingID, meeting.CreateTime),
+		MeetingID:        meeting.MeetingID,
+		Tenant:           tenant,
+		Instance:         instance,
+		StartTime:        meetingCreateTime(meeting.CreateTime),
+		PeakParticipants: int64(meeting.ParticipantCount),
+		Recording:        meeting.Recording,
+	})
+
+	if err != nil {
+		logger.Errorln("failed to record meeting snapshot in meetings history.", err)
+	}
+}
+
+// recordMeetingEnd ends the history record of the tenant meeting
+func (s *Server) recordMeetingEnd(logger *log.Entry, tenant string, meetingID string) {
+	if err := s.HistoryManager.End(tenant, meetingID, time.Now().UTC()); err != nil {
+		logger.Errorln("failed to record meeting end in meetings history.", err)
+	}
+}
+
+// reconcileMeetingsHistory ends the tenant meetings history records of the meetings no longer running and returns their
+// meeting identifiers
+func (s *Server) reconcileMeetingsHistory(logger *log.Entry, tenant string, meetings []api.MeetingInfo, now time.Time, interval time.Duration) []string {
+	running := []string{}
+	for _, meeting := range meetings {
+		running = append(running, meeting.MeetingID)
+	}
+
+	// meetings created since the previous poll may not be listed yet by their instance so they are kept two intervals
+	ended, err := s.HistoryManager.Reconcile(tenant, running, now, 2*interval)
+	if err != nil {
+		logger.Errorln("failed to reconcile meetings history.", err)
+		return []string{}
+	}
+
+	return ended
+}
+
+// purgeMeetingsHistory removes the meetings history records older than the configured retention
+func (s *Server) purgeMeetingsHistory(logger *log.Entry, now time.Time) {
+	retention, err := time.ParseDuration(s.Config.BigBlueSwarm.MeetingsHistoryRetention)
+	if err != nil || retention <= 0 {
+		return
+	}
+
+	count, err := s.HistoryManager.Purge(now.Add(-retention))
+	if err != nil {
+		logger.Errorln("failed to purge meetings history.", err)
+		return
+	}
+
+	if count > 0 {
+		logger.Infof("%d meetings history records purged.", count)
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryRecordID(t *testing.T) {
+	assert.Equal(t, "meeting-1647529200000", historyRecordID("meeting", "1647529200000"))
+	assert.Equal(t, "meeting", historyRecordID("meeting", ""))
+}
+
+func TestMeetingCreateTime(t *testing.T) {
+	assert.Equal(t, time.Date(2022, time.March, 17, 15, 0, 0, 0, time.UTC), meetingCreateTime("1647529200000"))
+	assert.WithinDuration(t, time.Now(), meetingCreateTime("invalid"), time.Minute)
+}
+
+func TestPollMeetingsHistory(t *testing.T) {
+	now := time.Date(2022, time.March, 17, 15, 4, 5, 0, time.UTC)
+	records := []*admin.MeetingRecord{}
+	reconciled := map[string][]string{}
+	var purged time.Time
+
+	server := doGenericInitialization()
+	server.InstanceManager = &admin.InstanceManagerMock{}
+	server.Config.BigBlueSwarm.MeetingsPollInterval = "1m"
+	server.Config.BigBlueSwarm.MeetingsHistoryRetention = "24h"
+	admin.ListInstancesInstanceManagerMockFunc = pollerInstances
+	restclient.RestClientMockDoFunc = pollerMeetings
+	admin.ListTenantsTenantManagerMockFunc = func() ([]admin.TenantListObject, error) {
+		return []admin.TenantListObject{{Hostname: "localhost"}, {Hostname: "empty.localhost"}}, nil
+	}
+	admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname}}, nil
+	}
+	admin.AddParticipantUsageTenantManagerMockFunc = func(tenant *admin.Tenant, s int64, sl time.Time, interval time.Duration) error {
+		return nil
+	}
+	ReconcilePoolMockFunc = func(tenant string, meetings map[string]int64, n time.Time, grace time.Duration) (map[string]time.Time, error) {
+		return map[string]time.Time{}, nil
+	}
+	admin.RecordMeetingHistoryManagerMockFunc = func(meeting *admin.MeetingRecord) error {
+		records = append(records, meeting)
+		return nil
+	}
+	admin.ReconcileMeetingHistoryManagerMockFunc = func(tenant string, running []string, n time.Time, grace time.Duration) ([]string, error) {
+		assert.Equal(t, 2*time.Minute, grace)
+		reconciled[tenant] = running
+		return []string{}, nil
+	}
+	admin.PurgeMeetingHistoryManagerMockFunc = func(before time.Time) (int64, error) {
+		purged = before
+		return 0, nil
+	}
+
+	server.pollMeetings(now)
+
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "first", records[0].MeetingID)
+	assert.Equal(t, "localhost", records[0].Tenant)
+	assert.Equal(t, "http://localhost:8080/bigbluebutton", records[0].Instance)
+	assert.Equal(t, int64(3), records[0].PeakParticipants)
+	assert.Equal(t, map[string][]string{
+		"localhost":       {"first", "second"},
+		"empty.localhost": {},
+	}, reconciled)
+	assert.Equal(t, now.Add(-24*time.Hour), purged)
+}
+
+func TestRecordMeetingCreation(t *testing.T) {
+	server := doGenericInitialization()
+	var record *admin.MeetingRecord
+	admin.RecordMeetingHistoryManagerMockFunc = func(meeting *admin.MeetingRecord) error {
+		record = meeting
+		return nil
+	}
+
+	server.recordMeetingCreation(newRequestLogger().Entry, "localhost", "http://localhost/bigbluebutton", &api.CreateResponse{
+		MeetingID:  "meeting",
+		CreateTime: "1647529200000",
+	})
+
+	assert.Equal(t, &admin.MeetingRecord{
+		ID:        "meeting-1647529200000",
+		MeetingID: "meeting",
+		Tenant:    "localhost",
+		Instance:  "http://localhost/bigbluebutton",
+		StartTime: time.Date(2022, time.March, 17, 15, 0, 0, 0, time.UTC),
+	}, record)
+}
@@ -0,0 +1,101 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// listener is an HTTP server started by Run and gracefully shut down when Run stops
+type listener struct {
+	name   string
+	server *http.Server
+}
+
+// addListener registers a listener serving the handler on the address
+func (s *Server) addListener(name string, addr string, handler http.Handler) *listener {
+	l := &listener{
+		name:   name,
+		server: &http.Server{Addr: addr, Handler: handler},
+	}
+
+	s.listeners = append(s.listeners, l)
+	return l
+}
+
+// addSecureListener registers a listener serving the handler over TLS if TLS is enabled, or over plain HTTP otherwise.
+// HTTP/2 is negotiated on TLS connections unless disabled
+func (s *Server) addSecureListener(name string, addr string, handler http.Handler) *listener {
+	l := s.addListener(name, addr, handler)
+	if s.tlsConfig == nil {
+		return l
+	}
+
+	l.server.TLSConfig = s.tlsConfig
+	if s.Config.TLS.DisableHTTP2 {
+		l.server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	}
+
+	return l
+}
+
+// serve starts the listeners. The returned channel receives the error of the first listener failing
+func (s *Server) serve() <-chan error {
+	errs := make(chan error, len(s.listeners))
+	for _, l := range s.listeners {
+		go func(l *listener) {
+			log.WithFields(log.Fields{"listener": l.name, "address": l.server.Addr, "tls": l.server.TLSConfig != nil}).Info("listening")
+			if err := l.listenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- fmt.Errorf("%s listener failed: %s", l.name, err)
+			}
+		}(l)
+	}
+
+	return errs
+}
+
+// listenAndServe serves over TLS if the listener has a TLS configuration. The certificates are provided by the TLS
+// configuration
+func (l *listener) listenAndServe() error {
+	if l.server.TLSConfig != nil {
+		return l.server.ListenAndServeTLS("", "")
+	}
+
+	return l.server.ListenAndServe()
+}
+
+// preStop fails the readiness probe then waits for the delay, so the load balancers stop sending requests before the
+// listeners are shut down
+func (s *Server) preStop(delay time.Duration) {
+	s.shuttingDown.Store(true)
+	if delay <= 0 {
+		return
+	}
+
+	log.WithField("delay", delay).Info("shutdown requested, waiting for the load balancers to stop sending requests")
+	time.Sleep(delay)
+}
+
+// shutdown marks the server as shutting down and gracefully shuts down the listeners. The in-flight requests are
+// closed once the context is done
+func (s *Server) shutdown(ctx context.Context) error {
+	s.shuttingDown.Store(true)
+	var err error
+	for _, l := range s.listeners {
+		if shutdownErr := l.server.Shutdown(ctx); shutdownErr != nil {
+			log.WithField("listener", l.name).Errorln("failed to drain listener requests.", shutdownErr)
+			l.server.Close()
+			if err == nil {
+				err = fmt.Errorf("failed to shut down %s listener: %s", l.name, shutdownErr)
+			}
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	server := &Server{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	completed := make(chan int, 1)
+	server.addListener("test", "127.0.0.1:18090", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	errs := server.serve()
+	go func() {
+		for i := 0; i < 50; i++ {
+			response, err := http.Get("http://127.0.0.1:18090/")
+			if err == nil {
+				completed <- response.StatusCode
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	<-started
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- server.shutdown(context.Background())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, server.shuttingDown.Load())
+	close(release)
+	assert.Nil(t, <-shutdown)
+	assert.Equal(t, http.StatusNoContent, <-completed)
+	assert.Equal(t, 0, len(errs))
+}
+
+func TestPreStop(t *testing.T) {
+	server := &Server{}
+	server.addListener("test", "127.0.0.1:18091", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.serve()
+	defer server.shutdown(context.Background())
+
+	stopped := make(chan struct{})
+	go func() {
+		server.preStop(200 * time.Millisecond)
+		close(stopped)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	readiness := server.readiness()
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, "shutting down", readiness.Checks[ReadinessCheckShutdown])
+	response, err := http.Get("http://127.0.0.1:18091/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	select {
+	case <-stopped:
+		assert.Fail(t, "preStop should wait for the shutdown delay before the listeners are shut down")
+	default:
+	}
+
+	<-stopped
+}
+
+func TestServeError(t *testing.T) {
+	server := &Server{}
+	server.addListener("test", "invalid address", http.NotFoundHandler())
+	assert.NotNil(t, <-server.serve())
+}
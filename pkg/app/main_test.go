@@ -25,6 +25,7 @@ func TestMain(m *testing.M) {
 	redisMock = rMock
 	mapper = NewMapper(*redisClient)
 	instanceManager = admin.NewInstanceManager(*redisClient)
+	resetPoolMock()
 
 	status := m.Run()
 
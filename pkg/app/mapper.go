@@ -13,8 +13,14 @@ import (
 type Mapper interface {
 	Add(key string, host string) error
 	Get(key string) (string, error)
+	SetOwner(key string, tenant string) error
+	GetOwner(key string) (string, error)
+	ClaimOwner(key string, tenant string) (bool, error)
+	RemoveOwner(key string) error
 	Remove(key string) error
 	DeleteAll(pattern string) error
+	List(pattern string) (map[string]string, error)
+	Count(pattern string) (int64, error)
 }
 
 // RedisMapper internally manage remote bigbluebutton session
@@ -39,6 +45,16 @@ func RecordingMapKey(id string) string {
 	return "recording:" + id
 }
 
+// OwnerKey format a session key as a valid owner key, storing the tenant owning the session
+func OwnerKey(key string) string {
+	return "owner:" + key
+}
+
+// MeetingPattern is the pattern used to retrieve all the meetings
+func MeetingPattern() string {
+	return "meeting:*"
+}
+
 // RecodingPattern is the pattern used to retrieve all the recordings
 func RecodingPattern() string {
 	return "recording:*"
@@ -58,14 +74,43 @@ func (m *RedisMapper) Get(key string) (string, error) {
 	return host, utils.ComputeErr(err)
 }
 
-// Remove remove the session from the redis database
+// SetOwner persist the tenant owning the session in the redis database
+func (m *RedisMapper) SetOwner(key string, tenant string) error {
+	_, err := m.RDB.Set(context.Background(), OwnerKey(key), tenant, 0).Result()
+
+	return utils.ComputeErr(err)
+}
+
+// GetOwner retrieve the tenant owning the session from the redis database. It returns an empty string if the owner is unknown
+func (m *RedisMapper) GetOwner(key string) (string, error) {
+	tenant, err := m.RDB.Get(context.Background(), OwnerKey(key)).Result()
+
+	return tenant, utils.ComputeErr(err)
+}
+
+// ClaimOwner atomically set the tenant as the session owner if the session does not have an owner yet. It returns false
+// if the session already has an owner, including the tenant itself
+func (m *RedisMapper) ClaimOwner(key string, tenant string) (bool, error) {
+	claimed, err := m.RDB.SetNX(context.Background(), OwnerKey(key), tenant, 0).Result()
+
+	return claimed, utils.ComputeErr(err)
+}
+
+// RemoveOwner remove the session owner from the redis database, keeping the session
+func (m *RedisMapper) RemoveOwner(key string) error {
+	_, err := m.RDB.Del(context.Background(), OwnerKey(key)).Result()
+
+	return utils.ComputeErr(err)
+}
+
+// Remove remove the session and its owner from the redis database
 func (m *RedisMapper) Remove(key string) error {
-	_, err := m.RDB.Del(context.Background(), key).Result()
+	_, err := m.RDB.Del(context.Background(), key, OwnerKey(key)).Result()
 
 	return utils.ComputeErr(err)
 }
 
-// DeleteAll delete all keys matching the pattern
+// DeleteAll delete all keys matching the pattern and their owners
 func (m *RedisMapper) DeleteAll(pattern string) error {
 	keys, err := m.RDB.Keys(context.Background(), pattern).Result()
 	if utils.ComputeErr(err) != nil {
@@ -73,7 +118,7 @@ func (m *RedisMapper) DeleteAll(pattern string) error {
 	}
 
 	for _, key := range keys {
-		_, err := m.RDB.Del(context.Background(), key).Result()
+		_, err := m.RDB.Del(context.Background(), key, OwnerKey(key)).Result()
 		if utils.ComputeErr(err) != nil {
 			return err
 		}
@@ -81,3 +126,36 @@ func (m *RedisMapper) DeleteAll(pattern string) error {
 
 	return nil
 }
+
+// List returns the hosts of all the sessions matching the pattern, indexed by key
+func (m *RedisMapper) List(pattern string) (map[string]string, error) {
+	sessions := map[string]string{}
+	keys, err := m.RDB.Keys(context.Background(), pattern).Result()
+	if utils.ComputeErr(err) != nil || len(keys) == 0 {
+		return sessions, utils.ComputeErr(err)
+	}
+
+	hosts, err := m.RDB.MGet(context.Background(), keys...).Result()
+	if utils.ComputeErr(err) != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		if host, ok := hosts[i].(string); ok {
+			sessions[key] = host
+		}
+	}
+
+	return sessions, nil
+}
+
+// Count returns the number of keys matching the pattern
+func (m *RedisMapper) Count(pattern string) (int64, error) {
+	count := int64(0)
+	iter := m.RDB.Scan(context.Background(), 0, pattern, 1000).Iterator()
+	for iter.Next(context.Background()) {
+		count++
+	}
+
+	return count, utils.ComputeErr(iter.Err())
+}
@@ -97,12 +97,67 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestOwnerKey(t *testing.T) {
+	assert.Equal(t, fmt.Sprintf("owner:meeting:%s", id), OwnerKey(MeetingMapKey(id)))
+}
+
+func TestOwner(t *testing.T) {
+	t.Run("SetOwner should store the session owner", func(t *testing.T) {
+		redisMock.ExpectSet(OwnerKey(MeetingMapKey(id)), "localhost", 0).SetVal("OK")
+		assert.Nil(t, mapper.SetOwner(MeetingMapKey(id), "localhost"))
+	})
+
+	t.Run("SetOwner should return an error if redis throws an error", func(t *testing.T) {
+		redisMock.ExpectSet(OwnerKey(MeetingMapKey(id)), "localhost", 0).SetErr(errors.New("redis error"))
+		assert.NotNil(t, mapper.SetOwner(MeetingMapKey(id), "localhost"))
+	})
+
+	t.Run("GetOwner should return the session owner", func(t *testing.T) {
+		redisMock.ExpectGet(OwnerKey(MeetingMapKey(id))).SetVal("localhost")
+		owner, err := mapper.GetOwner(MeetingMapKey(id))
+		assert.Nil(t, err)
+		assert.Equal(t, "localhost", owner)
+	})
+
+	t.Run("GetOwner should return an empty string if the owner is unknown", func(t *testing.T) {
+		redisMock.ExpectGet(OwnerKey(MeetingMapKey(id))).RedisNil()
+		owner, err := mapper.GetOwner(MeetingMapKey(id))
+		assert.Nil(t, err)
+		assert.Equal(t, "", owner)
+	})
+
+	t.Run("ClaimOwner should claim an unowned session", func(t *testing.T) {
+		redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(id)), "localhost", 0).SetVal(true)
+		claimed, err := mapper.ClaimOwner(MeetingMapKey(id), "localhost")
+		assert.Nil(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("ClaimOwner should not claim an owned session", func(t *testing.T) {
+		redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(id)), "localhost", 0).SetVal(false)
+		claimed, err := mapper.ClaimOwner(MeetingMapKey(id), "localhost")
+		assert.Nil(t, err)
+		assert.False(t, claimed)
+	})
+
+	t.Run("ClaimOwner should return an error if redis throws an error", func(t *testing.T) {
+		redisMock.ExpectSetNX(OwnerKey(MeetingMapKey(id)), "localhost", 0).SetErr(errors.New("redis error"))
+		_, err := mapper.ClaimOwner(MeetingMapKey(id), "localhost")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("RemoveOwner should delete the session owner", func(t *testing.T) {
+		redisMock.ExpectDel(OwnerKey(MeetingMapKey(id))).SetVal(1)
+		assert.Nil(t, mapper.RemoveOwner(MeetingMapKey(id)))
+	})
+}
+
 func TestRemove(t *testing.T) {
 	tests := []test.Test{
 		{
 			Name: "Remove should return nil if the session is removed",
 			Mock: func() {
-				redisMock.ExpectDel(MeetingMapKey(id)).SetErr(redis.Nil)
+				redisMock.ExpectDel(MeetingMapKey(id), OwnerKey(MeetingMapKey(id))).SetErr(redis.Nil)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Nil(t, err)
@@ -111,7 +166,7 @@ func TestRemove(t *testing.T) {
 		{
 			Name: "Remove should return an error if redis throws an error",
 			Mock: func() {
-				redisMock.ExpectDel(MeetingMapKey(id)).SetErr(errors.New("redis error"))
+				redisMock.ExpectDel(MeetingMapKey(id), OwnerKey(MeetingMapKey(id))).SetErr(errors.New("redis error"))
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.NotNil(t, err)
@@ -146,7 +201,7 @@ func TestDeleteAll(t *testing.T) {
 			Mock: func() {
 				mock := redisMock.ExpectKeys(RecodingPattern())
 				mock.SetVal([]string{RecordingMapKey(id)})
-				redisMock.ExpectDel(RecordingMapKey(id)).SetErr(errors.New("redis error"))
+				redisMock.ExpectDel(RecordingMapKey(id), OwnerKey(RecordingMapKey(id))).SetErr(errors.New("redis error"))
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Error(t, err)
@@ -157,7 +212,7 @@ func TestDeleteAll(t *testing.T) {
 			Mock: func() {
 				mock := redisMock.ExpectKeys(RecodingPattern())
 				mock.SetVal([]string{RecordingMapKey(id)})
-				redisMock.ExpectDel(RecordingMapKey(id)).SetVal(1)
+				redisMock.ExpectDel(RecordingMapKey(id), OwnerKey(RecordingMapKey(id))).SetVal(1)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Nil(t, err)
@@ -173,3 +228,56 @@ func TestDeleteAll(t *testing.T) {
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	tests := []test.Test{
+		{
+			Name: "An error thrown by redis keys method should be returned",
+			Mock: func() {
+				redisMock.ExpectKeys(RecodingPattern()).SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name: "No keys should return an empty map",
+			Mock: func() {
+				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]string{}, value)
+			},
+		},
+		{
+			Name: "An error thrown by redis mget method should be returned",
+			Mock: func() {
+				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{RecordingMapKey(id)})
+				redisMock.ExpectMGet(RecordingMapKey(id)).SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			Name: "The sessions hosts should be returned by key, skipping the removed sessions",
+			Mock: func() {
+				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{RecordingMapKey(id), RecordingMapKey("removed")})
+				redisMock.ExpectMGet(RecordingMapKey(id), RecordingMapKey("removed")).SetVal([]interface{}{"http://localhost/bigbluebutton", nil})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]string{RecordingMapKey(id): "http://localhost/bigbluebutton"}, value)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Mock()
+			sessions, err := mapper.List(RecodingPattern())
+			test.Validator(t, sessions, err)
+		})
+	}
+}
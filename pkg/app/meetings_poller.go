@@ -0,0 +1,230 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// listTenantsMeetings retrieve the running meetings on all instances indexed by tenant and records their snapshots in
+// the meetings history. Meetings without tenant metadata are ignored. It also returns false if at least one instance
+// failed to return its meetings
+func (s *Server) listTenantsMeetings(logger *log.Entry) (map[string][]api.MeetingInfo, bool, error) {
+	instances, err := s.InstanceManager.ListInstances()
+	if err != nil {
+		return nil, false, err
+	}
+
+	complete := true
+	meetings := map[string][]api.MeetingInfo{}
+	for _, result := range s.getMeetings(context.Background(), instances) {
+		if result.err != nil {
+			logger.Dup().WithField("instance", result.instance.URL).Errorln("failed to retrieve meetings.", result.err)
+			complete = false
+			continue
+		}
+
+		for _, meeting := range result.value.Meetings {
+			if tenant := meeting.Tenant(); tenant != "" {
+				meetings[tenant] = append(meetings[tenant], meeting)
+				s.recordMeetingSnapshot(logger, tenant, result.instance.URL, &meeting)
+			}
+		}
+	}
+
+	return meetings, complete, nil
+}
+
+func (s *Server) reconcilePool(logger *log.Entry, tenant string, meetings []api.MeetingInfo, now time.Time, interval time.Duration) map[string]time.Time {
+	participants := map[string]int64{}
+	for _, meeting := range meetings {
+		participants[meeting.MeetingID] = int64(meeting.ParticipantCount)
+	}
+
+	// meetings created since the previous poll may not be listed yet by their instance so they are kept two intervals
+	idle, err := s.Pool.Reconcile(tenant, participants, now, 2*interval)
+	if err != nil {
+		logger.Errorln("failed to reconcile tenant pool.", err)
+	}
+
+	return idle
+}
+
+func (s *Server) accountParticipantUsage(logger *log.Entry, tenant *admin.Tenant, meetings []api.MeetingInfo, slot time.Time, interval time.Duration) {
+	participants := int64(0)
+	for _, meeting := range meetings {
+		participants += int64(meeting.ParticipantCount)
+	}
+
+	seconds := participants * int64(interval.Seconds())
+	if err := s.TenantManager.AddParticipantUsage(tenant, seconds, slot, interval); err != nil {
+		logger.Errorln("failed to account participant usage.", err)
+	}
+}
+
+// meetingPolicyViolation returns the reason why the meeting should be ended according to the tenant policy. It returns
+// an empty string if the meeting respects the policy. Idle times are only known when the tenant pool is reconciled
+func meetingPolicyViolation(tenant *admin.Tenant, meeting api.MeetingInfo, idle map[string]time.Time, now time.Time) string {
+	if createTime, err := strconv.ParseInt(meeting.CreateTime, 10, 64); err == nil {
+		if tenant.MeetingDurationExceeded(time.UnixMilli(createTime), now) {
+			return "max meeting duration exceeded"
+		}
+	}
+
+	if since, ok := idle[meeting.MeetingID]; ok && meeting.ParticipantCount == 0 && tenant.IdleTimeoutExceeded(since, now) {
+		return "idle timeout exceeded"
+	}
+
+	return ""
+}
+
+// endMeeting ends the meeting on the instance retrieved from the mapper then cleans the meeting mapping and releases the
+// meeting from the tenant pool
+func (s *Server) endMeeting(logger *log.Entry, tenant *admin.Tenant, meeting api.MeetingInfo) error {
+	instance, err := s.retrieveBBBBInstanceFromKey(context.Background(), MeetingMapKey(meeting.MeetingID), tenant)
+	if err != nil {
+		return err
+	}
+
+	params := fmt.Sprintf("meetingID=%s", url.QueryEscape(meeting.MeetingID))
+	if meeting.ModeratorPW != "" {
+		params = fmt.Sprintf("%s&password=%s", params, url.QueryEscape(meeting.ModeratorPW))
+	}
+
+	response, err := instance.End(params)
+	if err != nil {
+		return err
+	}
+
+	if response.ReturnCode != api.ReturnCodes().Success {
+		return fmt.Errorf("instance %s failed to end meeting: %s", instance.URL, response.Message)
+	}
+
+	if err := s.Mapper.Remove(MeetingMapKey(meeting.MeetingID)); err != nil {
+		return fmt.Errorf("mapper failed to remove session %s: %s", meeting.MeetingID, err)
+	}
+
+	if err := s.Pool.ReleaseMeeting(tenant.Spec.Host, meeting.MeetingID); err != nil {
+		logger.Errorln("failed to release meeting from tenant pool.", err)
+	}
+
+	s.recordMeetingEnd(logger, tenant.Spec.Host, meeting.MeetingID)
+
+	return nil
+}
+
+// releaseEndedMeeting removes the mapping and the owner of a meeting that ended on its own, so its meeting id can be
+// reused by any tenant, and releases it from the tenant pool
+func (s *Server) releaseEndedMeeting(logger *log.Entry, tenant string, meetingID string) {
+	mLogger := logger.Dup().WithField("meeting_id", meetingID)
+	if err := s.Mapper.Remove(MeetingMapKey(meetingID)); err != nil {
+		mLogger.Errorln("mapper failed to remove ended session.", err)
+	}
+
+	if err := s.Pool.ReleaseMeeting(tenant, meetingID); err != nil {
+		mLogger.Errorln("failed to release ended meeting from tenant pool.", err)
+	}
+}
+
+func (s *Server) endMeetingsViolatingPolicy(logger *log.Entry, tenant *admin.Tenant, meetings []api.MeetingInfo, idle map[string]time.Time, now time.Time) {
+	for _, meeting := range meetings {
+		reason := meetingPolicyViolation(tenant, meeting, idle, now)
+		if reason == "" {
+			continue
+		}
+
+		mLogger := logger.Dup().WithField("meeting_id", meeting.MeetingID)
+		mLogger.Infof("ending meeting: %s.", reason)
+		if err := s.endMeeting(mLogger, tenant, meeting); err != nil {
+			mLogger.Errorln("failed to end meeting.", err)
+			continue
+		}
+
+		publishMeetingEnded(tenant.Spec.Host, tenantMeetingID(nil, tenant, meeting.MeetingID, meeting.Metadata()), EndReasonPolicy, reason)
+	}
+}
+
+func (s *Server) pollMeetings(now time.Time) {
+	logger := log.WithField("context", "meetings_poller")
+	interval := toDuration(s.Config.BigBlueSwarm.MeetingsPollInterval)
+	meetings, complete, err := s.listTenantsMeetings(logger)
+	if err != nil {
+		logger.Errorln("failed to retrieve instances.", err)
+		return
+	}
+
+	tenants, err := s.TenantManager.ListTenants()
+	if err != nil {
+		logger.Errorln("failed to retrieve tenants.", err)
+		return
+	}
+
+	if !complete {
+		logger.Warn("some instances did not return their meetings, tenants pools are not reconciled.")
+	}
+
+	for _, t := range tenants {
+		tLogger := logger.Dup().WithField("tenant", t.Hostname)
+		tenantMeetings := meetings[t.Hostname]
+		delete(meetings, t.Hostname)
+		var idle map[string]time.Time
+		ended := []string{}
+		if complete {
+			idle = s.reconcilePool(tLogger, t.Hostname, tenantMeetings, now, interval)
+			ended = s.reconcileMeetingsHistory(tLogger, t.Hostname, tenantMeetings, now, interval)
+		}
+
+		if len(tenantMeetings) == 0 && len(ended) == 0 {
+			continue
+		}
+
+		tenant, err := s.TenantManager.GetTenant(t.Hostname)
+		if err != nil {
+			tLogger.Errorln("failed to retrieve tenant.", err)
+			continue
+		}
+
+		if tenant == nil {
+			continue
+		}
+
+		for _, meetingID := range ended {
+			s.releaseEndedMeeting(tLogger, t.Hostname, meetingID)
+			publishMeetingEnded(t.Hostname, tenantMeetingID(nil, tenant, meetingID, nil), EndReasonNotRunning, "")
+		}
+
+		if len(tenantMeetings) == 0 {
+			continue
+		}
+
+		s.accountParticipantUsage(tLogger, tenant, tenantMeetings, now.Truncate(interval), interval)
+		s.endMeetingsViolatingPolicy(tLogger, tenant, tenantMeetings, idle, now)
+	}
+
+	for host := range meetings {
+		logger.Dup().WithField("tenant", host).Warn("meetings belong to an unknown tenant.")
+	}
+
+	s.purgeMeetingsHistory(logger, now)
+}
+
+// launchMeetingsPoller polls the meetings until the context is done
+func (s *Server) launchMeetingsPoller(ctx context.Context) {
+	ticker := time.NewTicker(toDuration(s.Config.BigBlueSwarm.MeetingsPollInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.pollMeetings(now)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/test_utils/pkg/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+
+	log "github.com/sirupsen/logrus"
+	LogTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+const pollerMeetingsResponse = `<response>
+	<returncode>SUCCESS</returncode>
+	<meetings>
+		<meeting>
+			<meetingID>first</meetingID>
+			<participantCount>3</participantCount>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+		<meeting>
+			<meetingID>second</meetingID>
+			<participantCount>2</participantCount>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+		<meeting>
+			<meetingID>untracked</meetingID>
+			<participantCount>5</participantCount>
+		</meeting>
+	</meetings>
+</response>`
+
+const pollerPolicyMeetingsResponse = `<response>
+	<returncode>SUCCESS</returncode>
+	<meetings>
+		<meeting>
+			<meetingID>long</meetingID>
+			<moderatorPW>mp</moderatorPW>
+			<createTime>%d</createTime>
+			<participantCount>3</participantCount>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+		<meeting>
+			<meetingID>idle</meetingID>
+			<moderatorPW>mp</moderatorPW>
+			<createTime>%d</createTime>
+			<participantCount>0</participantCount>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+		<meeting>
+			<meetingID>joined</meetingID>
+			<moderatorPW>mp</moderatorPW>
+			<createTime>%d</createTime>
+			<participantCount>1</participantCount>
+			<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+		</meeting>
+	</meetings>
+</response>`
+
+func pollerInstances() ([]api.BigBlueButtonInstance, error) {
+	return []api.BigBlueButtonInstance{
+		{
+			URL:    "http://localhost:8080/bigbluebutton",
+			Secret: test.DefaultSecret(),
+		},
+	}, nil
+}
+
+func pollerMeetings(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(pollerMeetingsResponse))),
+	}, nil
+}
+
+func TestPollMeetings(t *testing.T) {
+	logHook := LogTest.NewGlobal()
+	log.AddHook(logHook)
+	now := time.Date(2022, time.March, 17, 15, 4, 5, 0, time.UTC)
+	var seconds int64
+	var slot time.Time
+	var reconciled map[string]map[string]int64
+	var ended []string
+	var released []string
+	limit := int64(60)
+
+	tests := []test.Test{
+		{
+			Name: "An error returned by the list instances method should be logged",
+			Mock: func() {
+				admin.ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
+					return nil, errors.New("admin error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "failed to retrieve instances. admin error", logHook.LastEntry().Message)
+			},
+		},
+		{
+			Name: "An error returned by the list tenants method should be logged",
+			Mock: func() {
+				admin.ListInstancesInstanceManagerMockFunc = pollerInstances
+				restclient.RestClientMockDoFunc = pollerMeetings
+				admin.ListTenantsTenantManagerMockFunc = func() ([]admin.TenantListObject, error) {
+					return nil, errors.New("tenant manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "failed to retrieve tenants. tenant manager error", logHook.LastEntry().Message)
+			},
+		},
+		{
+			Name: "An unknown tenant should be logged",
+			Mock: func() {
+				admin.ListTenantsTenantManagerMockFunc = func() ([]admin.TenantListObject, error) {
+					return []admin.TenantListObject{}, nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "meetings belong to an unknown tenant.", logHook.LastEntry().Message)
+			},
+		},
+		{
+			Name: "Tenant pool should be reconciled and participant usage accounted for each tenant",
+			Mock: func() {
+				admin.ListTenantsTenantManagerMockFunc = func() ([]admin.TenantListObject, error) {
+					return []admin.TenantListObject{{Hostname: "localhost"}, {Hostname: "empty.localhost"}}, nil
+				}
+				reconciled = map[string]map[string]int64{}
+				ReconcilePoolMockFunc = func(tenant string, meetings map[string]int64, n time.Time, grace time.Duration) (map[string]time.Time, error) {
+					reconciled[tenant] = meetings
+					assert.Equal(t, 2*time.Minute, grace)
+					return map[string]time.Time{}, nil
+				}
+				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname}}, nil
+				}
+				admin.AddParticipantUsageTenantManagerMockFunc = func(tenant *admin.Tenant, s int64, sl time.Time, interval time.Duration) error {
+					seconds = s
+					slot = sl
+					return nil
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, map[string]map[string]int64{
+					"localhost":       {"first": 3, "second": 2},
+					"empty.localhost": {},
+				}, reconciled)
+				assert.Equal(t, int64(300), seconds)
+				assert.Equal(t, time.Date(2022, time.March, 17, 15, 4, 0, 0, time.UTC), slot)
+			},
+		},
+		{
+			Name: "An error returned while accounting participant usage should be logged",
+			Mock: func() {
+				admin.AddParticipantUsageTenantManagerMockFunc = func(tenant *admin.Tenant, s int64, sl time.Time, interval time.Duration) error {
+					return errors.New("tenant manager error")
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "failed to account participant usage. tenant manager error", logHook.LastEntry().Message)
+			},
+		},
+		{
+			Name: "Tenant pools should not be reconciled if an instance does not return its meetings",
+			Mock: func() {
+				reconciled = map[string]map[string]int64{}
+				admin.AddParticipantUsageTenantManagerMockFunc = func(tenant *admin.Tenant, s int64, sl time.Time, interval time.Duration) error {
+					return nil
+				}
+				admin.ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
+					instances, _ := pollerInstances()
+					return append(instances, api.BigBlueButtonInstance{URL: "http://failing/bigbluebutton"}), nil
+				}
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					if req.URL.Host == "failing" {
+						return nil, errors.New("rest client error")
+					}
+
+					return pollerMeetings(req)
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Empty(t, reconciled)
+			},
+		},
+		{
+			Name: "Meetings exceeding the tenant maximum duration or idle timeout should be ended",
+			Mock: func() {
+				admin.ListInstancesInstanceManagerMockFunc = pollerInstances
+				admin.ListTenantsTenantManagerMockFunc = func() ([]admin.TenantListObject, error) {
+					return []admin.TenantListObject{{Hostname: "localhost"}}, nil
+				}
+				admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+					return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname, MaxMeetingDuration: &limit, IdleTimeout: &limit}}, nil
+				}
+				ReconcilePoolMockFunc = func(tenant string, meetings map[string]int64, n time.Time, grace time.Duration) (map[string]time.Time, error) {
+					return map[string]time.Time{"idle": now.Add(-time.Hour), "joined": now.Add(-time.Hour)}, nil
+				}
+				released = []string{}
+				ReleaseMeetingPoolMockFunc = func(tenant string, meetingID string) error {
+					released = append(released, meetingID)
+					return nil
+				}
+				admin.GetInstanceManagerMockFunc = func(URL string) (api.BigBlueButtonInstance, error) {
+					return api.BigBlueButtonInstance{URL: URL, Secret: test.DefaultSecret()}, nil
+				}
+				ended = []string{}
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					body := fmt.Sprintf(pollerPolicyMeetingsResponse, now.Add(-2*time.Hour).UnixMilli(), now.UnixMilli(), now.UnixMilli())
+					if strings.HasSuffix(req.URL.Path, "/api/end") {
+						ended = append(ended, req.URL.Query().Get("meetingID")+":"+req.URL.Query().Get("password"))
+						body = "<response><returncode>SUCCESS</returncode></response>"
+					}
+
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+					}, nil
+				}
+				for _, meetingID := range []string{"long", "idle"} {
+					redisMock.ExpectGet(MeetingMapKey(meetingID)).SetVal("http://localhost:8080/bigbluebutton")
+					redisMock.ExpectGet(OwnerKey(MeetingMapKey(meetingID))).SetVal("localhost")
+					redisMock.ExpectDel(MeetingMapKey(meetingID), OwnerKey(MeetingMapKey(meetingID))).SetVal(1)
+				}
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, []string{"long:mp", "idle:mp"}, ended)
+				assert.Equal(t, []string{"long", "idle"}, released)
+			},
+		},
+		{
+			Name: "An error returned while ending a meeting should be logged",
+			Mock: func() {
+				redisMock.ExpectGet(MeetingMapKey("long")).SetErr(errors.New("redis error"))
+				redisMock.ExpectGet(MeetingMapKey("idle")).SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "failed to end meeting. mapper failed to retrieve session: redis error", logHook.LastEntry().Message)
+			},
+		},
+	}
+
+	server := doGenericInitialization()
+	server.InstanceManager = &admin.InstanceManagerMock{}
+	server.Config.BigBlueSwarm.MeetingsPollInterval = "1m"
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Mock()
+			server.pollMeetings(now)
+			test.Validator(t, nil, nil)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// collectMetrics records the BigBlueButton API requests count and latency by action and tenant
+func (s *Server) collectMetrics(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	tenant := "unknown"
+	if value, exists := c.Get("tenant"); exists {
+		tenant = value.(*admin.Tenant).Spec.Host
+	}
+
+	action := path.Base(c.FullPath())
+	metrics.Requests.WithLabelValues(action, tenant, strconv.Itoa(c.Writer.Status())).Inc()
+	metrics.RequestDuration.WithLabelValues(action, tenant).Observe(time.Since(start).Seconds())
+}
+
+// metricsAPIKeyValidation check that the request contains the metrics api key provided by Authorization header, if
+// configured
+func (s *Server) metricsAPIKeyValidation(c *gin.Context) {
+	key := s.Config.Metrics.APIKey
+	if key != "" && !admin.APIKeyMatches(strings.TrimSpace(c.Request.Header.Get("Authorization")), key) {
+		log.Warn("metrics auth key does not match the configured metrics key")
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	c.Next()
+}
+
+// mapperCollector collects the mapper sizes on scrape
+type mapperCollector struct {
+	mapper Mapper
+}
+
+// Describe implements prometheus.Collector
+func (m *mapperCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metrics.MapperSizeDesc
+}
+
+// Collect implements prometheus.Collector
+func (m *mapperCollector) Collect(ch chan<- prometheus.Metric) {
+	for kind, pattern := range map[string]string{"meetings": MeetingPattern(), "recordings": RecodingPattern()} {
+		count, err := m.mapper.Count(pattern)
+		if err != nil {
+			log.Errorln(fmt.Sprintf("failed to count mapper %s.", kind), err)
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(metrics.MapperSizeDesc, prometheus.GaugeValue, float64(count), kind)
+	}
+}
+
+// initMetrics expose the prometheus metrics on the admin API listener or on a dedicated listener if configured
+func (s *Server) initMetrics() error {
+	conf := s.Config.Metrics
+	if !conf.Enabled {
+		return nil
+	}
+
+	if err := metrics.Registry.Register(&mapperCollector{mapper: s.Mapper}); err != nil {
+		return fmt.Errorf("failed to register mapper metrics: %s", err)
+	}
+
+	if conf.APIKey == "" && (conf.Port != 0 || len(s.adminNetworks) == 0) {
+		log.Warn("metrics endpoint is protected neither by an api key nor by the admin allowlist")
+	}
+
+	handlers := []gin.HandlerFunc{s.metricsAPIKeyValidation, gin.WrapH(metrics.Handler())}
+	if conf.Port == 0 {
+		s.adminRouter().GET("/metrics", append(s.adminMiddlewares(), handlers...)...)
+		return nil
+	}
+
+	router := gin.New()
+	router.GET("/metrics", handlers...)
+	s.addListener("metrics", fmt.Sprintf(":%d", conf.Port), router)
+	return nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/metrics"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectMetrics(t *testing.T) {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("tenant", &admin.Tenant{Spec: &admin.TenantSpec{Host: "metrics.localhost"}})
+	})
+	server := doGenericInitialization()
+	router.GET("/bigbluebutton/api/getMeetings", server.collectMetrics, func(c *gin.Context) {
+		c.Status(http.StatusTeapot)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bigbluebutton/api/getMeetings", nil))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.Requests.WithLabelValues("getMeetings", "metrics.localhost", "418")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.RequestDuration.WithLabelValues("getMeetings", "metrics.localhost").(prometheus.Histogram)))
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	mr.Set(MeetingMapKey("meeting"), "http://localhost/bigbluebutton")
+	mr.Set(OwnerKey(MeetingMapKey("meeting")), "localhost")
+	mr.Set(RecordingMapKey("first"), "http://localhost/bigbluebutton")
+	mr.Set(RecordingMapKey("second"), "http://localhost/bigbluebutton")
+
+	server := doGenericInitialization()
+	server.Mapper = NewMapper(*client)
+	server.Config.Metrics.Enabled = true
+	server.Config.Metrics.APIKey = "metrics_key"
+	assert.Nil(t, server.initMetrics())
+	defer metrics.Registry.Unregister(&mapperCollector{})
+
+	t.Run("a request without the metrics api key should be unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("a request with the metrics api key should return the metrics", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Authorization", "metrics_key")
+		server.Router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.True(t, strings.Contains(body, `bigblueswarm_mapper_sessions{kind="meetings"} 1`))
+		assert.True(t, strings.Contains(body, `bigblueswarm_mapper_sessions{kind="recordings"} 2`))
+	})
+
+	t.Run("a request from an address outside the admin allowlist should be forbidden", func(t *testing.T) {
+		networks, err := utils.ParseNetworks([]string{"10.0.0.0/8"})
+		assert.Nil(t, err)
+		server.adminNetworks = networks
+		defer func() { server.adminNetworks = nil }()
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Authorization", "metrics_key")
+		server.Router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,191 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Unlimited is the pool limit used when a tenant does not have a pool configured
+const Unlimited int64 = -1
+
+// MeetingAcquisition is the result of a meeting acquisition in a tenant pool
+type MeetingAcquisition int
+
+const (
+	// MeetingPoolFull means the tenant reached its meetings pool limit
+	MeetingPoolFull MeetingAcquisition = iota
+	// MeetingAcquired means the meeting was newly reserved in the tenant pool
+	MeetingAcquired
+	// MeetingAlreadyAcquired means the meeting was already reserved in the tenant pool, by a running meeting or a
+	// concurrent creation
+	MeetingAlreadyAcquired
+)
+
+// Pool manages the authoritative tenants meetings and participants counters used to enforce the tenants pools
+type Pool interface {
+	// AcquireMeeting reserves a meeting in the tenant pool. It returns MeetingPoolFull if the tenant reached the limit.
+	// Acquiring an already acquired meeting always succeeds and returns MeetingAlreadyAcquired
+	AcquireMeeting(tenant string, meetingID string, limit int64) (MeetingAcquisition, error)
+	// ReleaseMeeting removes a meeting and its participants from the tenant pool
+	ReleaseMeeting(tenant string, meetingID string) error
+	// AcquireParticipant reserves a participant in the tenant pool. It returns false if the tenant reached the limit
+	AcquireParticipant(tenant string, meetingID string, limit int64) (bool, error)
+	// Reconcile synchronizes the tenant pool with the meetings running on the instances. Meetings is a map of meeting
+	// identifiers to participants count. Tracked meetings neither running nor acquired during the grace period are removed.
+	// It returns the time since which each tracked meeting has no participant
+	Reconcile(tenant string, meetings map[string]int64, now time.Time, grace time.Duration) (map[string]time.Time, error)
+}
+
+// RedisPool is the redis implementation of Pool. Meetings are stored in a sorted set scored by the last time the meeting
+// was acquired or seen running, participants and idle times are stored in hashes indexed by meeting. Checks and updates are executed
+// in lua scripts so the limits are enforced atomically.
+type RedisPool struct {
+	RDB *redis.Client
+}
+
+// NewPool creates a new Pool
+func NewPool(rdb redis.Client) Pool {
+	return &RedisPool{
+		RDB: &rdb,
+	}
+}
+
+// PoolMeetingsKey returns the key of the tenant meetings pool
+func PoolMeetingsKey(tenant string) string {
+	return fmt.Sprintf("pool:meetings:%s", tenant)
+}
+
+// PoolIdleKey returns the key storing since when the tenant meetings have no participant
+func PoolIdleKey(tenant string) string {
+	return fmt.Sprintf("pool:idle:%s", tenant)
+}
+
+// PoolParticipantsKey returns the key of the tenant participants pool
+func PoolParticipantsKey(tenant string) string {
+	return fmt.Sprintf("pool:participants:%s", tenant)
+}
+
+var acquireMeetingScript = redis.NewScript(`
+if redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	return 2
+end
+
+local limit = tonumber(ARGV[2])
+if limit >= 0 and redis.call('ZCARD', KEYS[1]) >= limit then
+	return 0
+end
+
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return 1
+`)
+
+var acquireParticipantScript = redis.NewScript(`
+local limit = tonumber(ARGV[2])
+if limit >= 0 then
+	local total = 0
+	for _, count in ipairs(redis.call('HVALS', KEYS[1])) do
+		total = total + tonumber(count)
+	end
+
+	if total >= limit then
+		return 0
+	end
+end
+
+redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+return 1
+`)
+
+var reconcileScript = redis.NewScript(`
+for i = 3, #ARGV, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[i])
+	redis.call('HSET', KEYS[2], ARGV[i], ARGV[i + 1])
+	if tonumber(ARGV[i + 1]) == 0 then
+		redis.call('HSETNX', KEYS[3], ARGV[i], ARGV[1])
+	else
+		redis.call('HDEL', KEYS[3], ARGV[i])
+	end
+end
+
+for _, meeting in ipairs(redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[2])) do
+	redis.call('ZREM', KEYS[1], meeting)
+end
+
+for k = 2, 3 do
+	for _, meeting in ipairs(redis.call('HKEYS', KEYS[k])) do
+		if not redis.call('ZSCORE', KEYS[1], meeting) then
+			redis.call('HDEL', KEYS[k], meeting)
+		end
+	end
+end
+
+return redis.call('HGETALL', KEYS[3])
+`)
+
+// AcquireMeeting reserves a meeting in the tenant pool. It returns MeetingPoolFull if the tenant reached the limit.
+// Acquiring an already acquired meeting always succeeds and returns MeetingAlreadyAcquired
+func (p *RedisPool) AcquireMeeting(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+	keys := []string{PoolMeetingsKey(tenant)}
+	acquisition, err := acquireMeetingScript.Run(context.Background(), p.RDB, keys, meetingID, limit, time.Now().Unix()).Int()
+	if utils.ComputeErr(err) != nil {
+		return MeetingPoolFull, err
+	}
+
+	return MeetingAcquisition(acquisition), nil
+}
+
+// ReleaseMeeting removes a meeting, its participants and its idle state from the tenant pool
+func (p *RedisPool) ReleaseMeeting(tenant string, meetingID string) error {
+	pipe := p.RDB.TxPipeline()
+	pipe.ZRem(context.Background(), PoolMeetingsKey(tenant), meetingID)
+	pipe.HDel(context.Background(), PoolParticipantsKey(tenant), meetingID)
+	pipe.HDel(context.Background(), PoolIdleKey(tenant), meetingID)
+	_, err := pipe.Exec(context.Background())
+	return utils.ComputeErr(err)
+}
+
+// AcquireParticipant reserves a participant in the tenant pool. It returns false if the tenant reached the limit
+func (p *RedisPool) AcquireParticipant(tenant string, meetingID string, limit int64) (bool, error) {
+	keys := []string{PoolParticipantsKey(tenant)}
+	acquired, err := acquireParticipantScript.Run(context.Background(), p.RDB, keys, meetingID, limit).Int()
+	if utils.ComputeErr(err) != nil {
+		return false, err
+	}
+
+	return acquired == 1, nil
+}
+
+// Reconcile synchronizes the tenant pool with the meetings running on the instances. Meetings is a map of meeting
+// identifiers to participants count. Tracked meetings neither running nor acquired during the grace period are removed.
+// It returns the time since which each tracked meeting has no participant
+func (p *RedisPool) Reconcile(tenant string, meetings map[string]int64, now time.Time, grace time.Duration) (map[string]time.Time, error) {
+	keys := []string{PoolMeetingsKey(tenant), PoolParticipantsKey(tenant), PoolIdleKey(tenant)}
+	args := []interface{}{now.Unix(), now.Add(-grace).Unix()}
+	for meetingID, participants := range meetings {
+		args = append(args, meetingID, participants)
+	}
+
+	values, err := reconcileScript.Run(context.Background(), p.RDB, keys, args...).StringSlice()
+	if utils.ComputeErr(err) != nil {
+		return nil, err
+	}
+
+	idle := map[string]time.Time{}
+	for i := 0; i+1 < len(values); i += 2 {
+		since, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid idle time for meeting %s: %s", values[i], err)
+		}
+
+		idle[values[i]] = time.Unix(since, 0)
+	}
+
+	return idle, nil
+}
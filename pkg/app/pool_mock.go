@@ -0,0 +1,38 @@
+// Package app is the bigblueswarm core
+package app
+
+import "time"
+
+// PoolMock is a mock implementation of the Pool interface
+type PoolMock struct{}
+
+var (
+	// AcquireMeetingPoolMockFunc is the function that will be called when the mock pool is used
+	AcquireMeetingPoolMockFunc func(tenant string, meetingID string, limit int64) (MeetingAcquisition, error)
+	// ReleaseMeetingPoolMockFunc is the function that will be called when the mock pool is used
+	ReleaseMeetingPoolMockFunc func(tenant string, meetingID string) error
+	// AcquireParticipantPoolMockFunc is the function that will be called when the mock pool is used
+	AcquireParticipantPoolMockFunc func(tenant string, meetingID string, limit int64) (bool, error)
+	// ReconcilePoolMockFunc is the function that will be called when the mock pool is used
+	ReconcilePoolMockFunc func(tenant string, meetings map[string]int64, now time.Time, grace time.Duration) (map[string]time.Time, error)
+)
+
+// AcquireMeeting is a mock implementation that reserves a meeting in the tenant pool
+func (p *PoolMock) AcquireMeeting(tenant string, meetingID string, limit int64) (MeetingAcquisition, error) {
+	return AcquireMeetingPoolMockFunc(tenant, meetingID, limit)
+}
+
+// ReleaseMeeting is a mock implementation that removes a meeting from the tenant pool
+func (p *PoolMock) ReleaseMeeting(tenant string, meetingID string) error {
+	return ReleaseMeetingPoolMockFunc(tenant, meetingID)
+}
+
+// AcquireParticipant is a mock implementation that reserves a participant in the tenant pool
+func (p *PoolMock) AcquireParticipant(tenant string, meetingID string, limit int64) (bool, error) {
+	return AcquireParticipantPoolMockFunc(tenant, meetingID, limit)
+}
+
+// Reconcile is a mock implementation that synchronizes the tenant pool
+func (p *PoolMock) Reconcile(tenant string, meetings map[string]int64, now time.Time, grace time.Duration) (map[string]time.Time, error) {
+	return ReconcilePoolMockFunc(tenant, meetings, now, grace)
+}
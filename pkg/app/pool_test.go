@@ -0,0 +1,211 @@
+package app
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+	"github.com/bigblueswarm/test_utils/pkg/request"
+	"github.com/bigblueswarm/test_utils/pkg/test"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMiniRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	mr := miniredis.RunT(t)
+	return mr, redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func TestRedisPoolMeetings(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	pool := NewPool(*client)
+
+	t.Run("meetings should be acquired until the limit is reached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			acquisition, err := pool.AcquireMeeting("localhost", fmt.Sprintf("meeting-%d", i), 2)
+			assert.Nil(t, err)
+			assert.Equal(t, MeetingAcquired, acquisition)
+		}
+
+		acquisition, err := pool.AcquireMeeting("localhost", "meeting-2", 2)
+		assert.Nil(t, err)
+		assert.Equal(t, MeetingPoolFull, acquisition)
+	})
+
+	t.Run("an already acquired meeting should be acquired again", func(t *testing.T) {
+		acquisition, err := pool.AcquireMeeting("localhost", "meeting-0", 2)
+		assert.Nil(t, err)
+		assert.Equal(t, MeetingAlreadyAcquired, acquisition)
+	})
+
+	t.Run("an unlimited pool should always acquire meetings", func(t *testing.T) {
+		acquisition, err := pool.AcquireMeeting("localhost", "meeting-2", Unlimited)
+		assert.Nil(t, err)
+		assert.Equal(t, MeetingAcquired, acquisition)
+	})
+
+	t.Run("releasing a meeting should remove it, its participants and its idle state from the pool", func(t *testing.T) {
+		_, err := pool.AcquireParticipant("localhost", "meeting-2", Unlimited)
+		assert.Nil(t, err)
+		mr.HSet(PoolIdleKey("localhost"), "meeting-2", "1")
+		assert.Nil(t, pool.ReleaseMeeting("localhost", "meeting-2"))
+		members, _ := mr.ZMembers(PoolMeetingsKey("localhost"))
+		assert.Equal(t, []string{"meeting-0", "meeting-1"}, members)
+		assert.False(t, mr.Exists(PoolParticipantsKey("localhost")))
+		assert.False(t, mr.Exists(PoolIdleKey("localhost")))
+	})
+}
+
+func TestRedisPoolParticipants(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	pool := NewPool(*client)
+
+	for i := 0; i < 3; i++ {
+		acquired, err := pool.AcquireParticipant("localhost", fmt.Sprintf("meeting-%d", i%2), 3)
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	}
+
+	acquired, err := pool.AcquireParticipant("localhost", "meeting-2", 3)
+	assert.Nil(t, err)
+	assert.False(t, acquired)
+	assert.Equal(t, "2", mr.HGet(PoolParticipantsKey("localhost"), "meeting-0"))
+}
+
+func TestRedisPoolReconcile(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	pool := NewPool(*client)
+	now := time.Now()
+	key := PoolMeetingsKey("localhost")
+	mr.ZAdd(key, float64(now.Add(-time.Hour).Unix()), "ended")
+	mr.ZAdd(key, float64(now.Add(-time.Minute).Unix()), "just-created")
+	mr.HSet(PoolParticipantsKey("localhost"), "ended", "4")
+	mr.HSet(PoolParticipantsKey("localhost"), "just-created", "1")
+	mr.HSet(PoolIdleKey("localhost"), "ended", "1")
+	mr.HSet(PoolIdleKey("localhost"), "emptied", fmt.Sprint(now.Add(-10*time.Minute).Unix()))
+	mr.HSet(PoolIdleKey("localhost"), "running", fmt.Sprint(now.Add(-10*time.Minute).Unix()))
+
+	meetings := map[string]int64{"running": 7, "emptied": 0, "empty": 0}
+	idle, err := pool.Reconcile("localhost", meetings, now, 2*time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"emptied": time.Unix(now.Add(-10*time.Minute).Unix(), 0),
+		"empty":   time.Unix(now.Unix(), 0),
+	}, idle)
+
+	members, _ := mr.ZMembers(key)
+	assert.ElementsMatch(t, []string{"just-created", "running", "emptied", "empty"}, members)
+	assert.Equal(t, "7", mr.HGet(PoolParticipantsKey("localhost"), "running"))
+	assert.Equal(t, "1", mr.HGet(PoolParticipantsKey("localhost"), "just-created"))
+	assert.Equal(t, "", mr.HGet(PoolParticipantsKey("localhost"), "ended"))
+}
+
+func TestRedisPoolConcurrentAcquisitions(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	pool := NewPool(*client)
+	limit := int64(10)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	acquiredCount := 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			acquisition, err := pool.AcquireMeeting("localhost", fmt.Sprintf("meeting-%d", i), limit)
+			assert.Nil(t, err)
+			if acquisition == MeetingAcquired {
+				mutex.Lock()
+				acquiredCount++
+				mutex.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	members, _ := mr.ZMembers(PoolMeetingsKey("localhost"))
+	assert.Equal(t, int(limit), acquiredCount)
+	assert.Equal(t, int(limit), len(members))
+}
+
+func TestConcurrentCreateAtMeetingPoolLimit(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	limit := int64(5)
+	mr.HSet(admin.BBSInstances, instance, test.DefaultSecret())
+
+	server := doGenericInitialization()
+	server.Pool = NewPool(*client)
+	server.Mapper = NewMapper(*client)
+	server.InstanceManager = admin.NewInstanceManager(*client)
+	admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return &admin.Tenant{
+			Spec:      &admin.TenantSpec{Host: "localhost", MeetingsPool: &limit},
+			Instances: []string{instance},
+		}, nil
+	}
+	balancer.BalancerMockProcessFunc = func(instances []string) (string, error) {
+		return instance, nil
+	}
+	restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+		response, err := xml.Marshal(&api.CreateResponse{
+			Response:  api.Response{ReturnCode: api.ReturnCodes().Success},
+			MeetingID: req.URL.Query().Get("meetingID"),
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(response)),
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 30)
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			query := fmt.Sprintf("meetingID=meeting-%d&name=test", i)
+			ctx.Set("logger", newRequestLogger())
+			ctx.Set("api_ctx", &api.Checksum{Secret: test.DefaultSecret(), Params: query, Action: api.Create})
+			request.SetRequestHost(ctx, "localhost")
+			request.SetRequestParams(ctx, query)
+			server.Create(ctx)
+			codes <- recorder.Code
+		}(i)
+	}
+
+	wg.Wait()
+	close(codes)
+	created := 0
+	rejected := 0
+	for code := range codes {
+		switch code {
+		case http.StatusOK:
+			created++
+		case http.StatusForbidden:
+			rejected++
+		}
+	}
+
+	members, _ := mr.ZMembers(PoolMeetingsKey("localhost"))
+	assert.Equal(t, int(limit), created)
+	assert.Equal(t, 30-int(limit), rejected)
+	assert.Equal(t, int(limit), len(members))
+}
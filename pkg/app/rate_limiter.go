@@ -0,0 +1,78 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RateLimiter manages the tenants API rate limits
+type RateLimiter interface {
+	// Allow consumes a token from the bucket. It returns false and the duration before a token is available if the bucket
+	// is empty
+	Allow(key string, limit *admin.RateLimit, now time.Time) (bool, time.Duration, error)
+}
+
+// RedisRateLimiter is the redis implementation of RateLimiter. Buckets are stored in redis hashes and updated in a lua
+// script so the limits are shared by all BigBlueSwarm replicas
+type RedisRateLimiter struct {
+	RDB *redis.Client
+}
+
+// NewRateLimiter creates a new RateLimiter
+func NewRateLimiter(rdb redis.Client) RateLimiter {
+	return &RedisRateLimiter{
+		RDB: &rdb,
+	}
+}
+
+// RateLimitKey returns the key of the tenant action rate limit bucket
+func RateLimitKey(tenant string, action string) string {
+	return fmt.Sprintf("ratelimit:%s:%s", tenant, action)
+}
+
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'timestamp')
+local tokens = tonumber(bucket[1]) or capacity
+local timestamp = tonumber(bucket[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - timestamp) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'timestamp', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+return {allowed, retry}
+`)
+
+// Allow consumes a token from the bucket. It returns false and the duration before a token is available if the bucket
+// is empty
+func (r *RedisRateLimiter) Allow(key string, limit *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+	// the bucket is refilled in tokens per millisecond
+	rate := float64(limit.Requests) / float64(limit.Interval().Milliseconds())
+	result, err := tokenBucketScript.Run(context.Background(), r.RDB, []string{key}, limit.Capacity(), rate, now.UnixMilli()).Int64Slice()
+	if utils.ComputeErr(err) != nil {
+		return false, 0, err
+	}
+
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", result)
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,21 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+)
+
+// RateLimiterMock is a mock implementation of the RateLimiter interface
+type RateLimiterMock struct{}
+
+var (
+	// AllowRateLimiterMockFunc is the function that will be called when the mock rate limiter is used
+	AllowRateLimiterMockFunc func(key string, limit *admin.RateLimit, now time.Time) (bool, time.Duration, error)
+)
+
+// Allow is a mock implementation that consumes a token from the bucket
+func (r *RateLimiterMock) Allow(key string, limit *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+	return AllowRateLimiterMockFunc(key, limit, now)
+}
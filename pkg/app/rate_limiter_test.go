@@ -0,0 +1,47 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRateLimiter(t *testing.T) {
+	mr, client := newMiniRedis(t)
+	limiter := NewRateLimiter(*client)
+	limit := &admin.RateLimit{Requests: 2, Period: "1s", Burst: 3}
+	key := RateLimitKey("localhost", "getMeetings")
+	now := time.Now()
+
+	t.Run("requests should be allowed until the bucket is empty", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			allowed, _, err := limiter.Allow(key, limit, now)
+			assert.Nil(t, err)
+			assert.True(t, allowed)
+		}
+
+		allowed, retryAfter, err := limiter.Allow(key, limit, now)
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 500*time.Millisecond, retryAfter)
+		assert.True(t, mr.TTL(key) > 0)
+	})
+
+	t.Run("the bucket should be refilled over time", func(t *testing.T) {
+		allowed, _, err := limiter.Allow(key, limit, now.Add(500*time.Millisecond))
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+
+		allowed, _, err = limiter.Allow(key, limit, now.Add(500*time.Millisecond))
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("buckets should be isolated by key", func(t *testing.T) {
+		allowed, _, err := limiter.Allow(RateLimitKey("localhost", "join"), limit, now)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+	})
+}
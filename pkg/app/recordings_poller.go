@@ -2,15 +2,15 @@
 package app
 
 import (
+	"context"
 	"time"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/metrics"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
-func (s *Server) clearRecordings() error {
-	return s.Mapper.DeleteAll(RecodingPattern())
-}
-
 func toDuration(value string) time.Duration {
 	duration, err := time.ParseDuration(value)
 	if err != nil {
@@ -21,39 +21,115 @@ func toDuration(value string) time.Duration {
 }
 
 func (s *Server) pollRecordings() {
+	start := time.Now()
+	ctx, span := tracing.Start(context.Background(), "poller.recordings")
+	defer func() {
+		span.End()
+		metrics.RecordingsPollDuration.Observe(time.Since(start).Seconds())
+	}()
+
 	logger := log.WithField("context", "poll_recorder")
 	logger.Info("polling recordings")
-	if err := s.clearRecordings(); err != nil {
-		logger.Errorln("failed to clear recordings.", err)
-		return
-	}
-
 	instances, err := s.InstanceManager.ListInstances()
 	if err != nil {
 		logger.Errorln("failed to retrieve instances.", err)
 		return
 	}
 
-	for _, instance := range instances {
-		iLogger := logger.Dup().WithField("instance", instance.URL)
-		recordings, err := instance.GetRecordings("")
-		if err != nil {
-			iLogger.Errorln("failed to retrieve recordings.", err)
+	known := map[string]string{}
+	polled := map[string]bool{}
+	failed := map[string]bool{}
+	for _, result := range s.getRecordings(ctx, instances, "", s.recordingsPollTimeout()) {
+		iLogger := logger.Dup().WithField("instance", result.instance.URL)
+		if result.err != nil {
+			iLogger.Errorln("failed to retrieve recordings.", result.err)
+			s.keepKnownRecordings(known, result.instance.URL)
+			failed[result.instance.URL] = true
 			continue
 		}
 
-		for _, recording := range recordings.Recordings {
-			if err := s.Mapper.Add(RecordingMapKey(recording.RecordID), instance.URL); err != nil {
+		for _, recording := range result.value.Recordings {
+			s.discoverRecording(known, result.instance.URL, &recording)
+			polled[RecordingMapKey(recording.RecordID)] = true
+			if err := s.mapper(ctx).Add(RecordingMapKey(recording.RecordID), result.instance.URL); err != nil {
 				iLogger.Dup().WithField("record_id", recording).Errorln("failed to store record.", err)
 				continue
 			}
+
+			if tenant := recording.Tenant(); tenant != "" {
+				if err := s.mapper(ctx).SetOwner(RecordingMapKey(recording.RecordID), tenant); err != nil {
+					iLogger.Dup().WithField("record_id", recording.RecordID).Errorln("failed to store record owner.", err)
+				}
+			}
 		}
 	}
+
+	s.knownRecordings = known
+	s.removeStaleRecordings(ctx, logger, polled, failed)
 }
 
-func (s *Server) launchRecordingPoller() {
+// removeStaleRecordings removes the stored recordings that were not returned by the poll. The recordings of the
+// instances that failed to return their recordings are kept until the next poll
+func (s *Server) removeStaleRecordings(ctx context.Context, logger *log.Entry, polled map[string]bool, failed map[string]bool) {
+	recordings, err := s.mapper(ctx).List(RecodingPattern())
+	if err != nil {
+		logger.Errorln("failed to list stored recordings.", err)
+		return
+	}
+
+	for key, instance := range recordings {
+		if polled[key] || failed[instance] {
+			continue
+		}
+
+		if err := s.mapper(ctx).Remove(key); err != nil {
+			logger.Dup().WithField("key", key).Errorln("failed to remove stale record.", err)
+		}
+	}
+}
+
+// recordingsPollTimeout returns the configured deadline of each instance call when polling the recordings
+func (s *Server) recordingsPollTimeout() time.Duration {
+	if s.Config.BigBlueSwarm.RecordingsPollTimeout == "" {
+		return 0
+	}
+
+	return toDuration(s.Config.BigBlueSwarm.RecordingsPollTimeout)
+}
+
+// discoverRecording adds the recording to the known recordings and publishes a recording.discovered event if it was
+// unknown. No event is published on the first poll as all the recordings are unknown
+func (s *Server) discoverRecording(known map[string]string, instance string, recording *api.Recording) {
+	known[recording.RecordID] = instance
+	if s.knownRecordings == nil {
+		return
+	}
+
+	if _, ok := s.knownRecordings[recording.RecordID]; !ok {
+		publishRecordingDiscovered(instance, recording)
+	}
+}
+
+// keepKnownRecordings keeps the known recordings of an instance that failed to return its recordings so they are not
+// discovered again on the next poll
+func (s *Server) keepKnownRecordings(known map[string]string, instance string) {
+	for recordID, url := range s.knownRecordings {
+		if url == instance {
+			known[recordID] = url
+		}
+	}
+}
+
+// launchRecordingPoller polls the recordings until the context is done
+func (s *Server) launchRecordingPoller(ctx context.Context) {
 	ticker := time.NewTicker(toDuration(s.Config.BigBlueSwarm.RecordingsPollInterval))
-	for range ticker.C {
-		s.pollRecordings()
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pollRecordings()
+		}
 	}
 }
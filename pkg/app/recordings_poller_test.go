@@ -36,21 +36,9 @@ func TestPollRecordings(t *testing.T) {
 	logHook := LogTest.NewGlobal()
 	log.AddHook(logHook)
 	tests := []test.Test{
-		{
-			Name: "An error returned by the clear recordings method should be logged",
-			Mock: func() {
-				mock := redisMock.ExpectKeys(RecodingPattern())
-				mock.SetVal([]string{})
-				mock.SetErr(errors.New("redis error"))
-			},
-			Validator: func(t *testing.T, value interface{}, err error) {
-				assert.Equal(t, "failed to clear recordings. redis error", logHook.LastEntry().Message)
-			},
-		},
 		{
 			Name: "An error returned by the list instances method should be logged",
 			Mock: func() {
-				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{})
 				admin.ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
 					return nil, errors.New("admin error")
 				}
@@ -60,9 +48,8 @@ func TestPollRecordings(t *testing.T) {
 			},
 		},
 		{
-			Name: "An error returned by the instance get recordings method should be logged",
+			Name: "An error returned by the instance get recordings method should be logged and the instance recordings kept",
 			Mock: func() {
-				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{})
 				admin.ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
 					return []api.BigBlueButtonInstance{
 						{
@@ -74,6 +61,9 @@ func TestPollRecordings(t *testing.T) {
 				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
 					return nil, errors.New("rest client error")
 				}
+				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{RecordingMapKey("kept"), RecordingMapKey("stale")})
+				redisMock.ExpectMGet(RecordingMapKey("kept"), RecordingMapKey("stale")).SetVal([]interface{}{"http://localhost:8080/bigbluebutton", "http://removed/bigbluebutton"})
+				redisMock.ExpectDel(RecordingMapKey("stale"), OwnerKey(RecordingMapKey("stale"))).SetVal(2)
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, "failed to retrieve recordings. rest client error", logHook.LastEntry().Message)
@@ -82,7 +72,6 @@ func TestPollRecordings(t *testing.T) {
 		{
 			Name: "An error returned by the mapper add method should be logged",
 			Mock: func() {
-				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{})
 				admin.ListInstancesInstanceManagerMockFunc = func() ([]api.BigBlueButtonInstance, error) {
 					return []api.BigBlueButtonInstance{
 						{
@@ -113,11 +102,49 @@ func TestPollRecordings(t *testing.T) {
 				mock := redisMock.ExpectSet(RecordingMapKey("recording-id"), "http://localhost:8080/bigbluebutton", 0)
 				mock.SetVal("")
 				mock.SetErr(errors.New("redis error"))
+				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{})
 			},
 			Validator: func(t *testing.T, value interface{}, err error) {
 				assert.Equal(t, "failed to store record. redis error", logHook.LastEntry().Message)
 			},
 		},
+		{
+			Name: "An error returned while storing the recording owner should be logged",
+			Mock: func() {
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					value := `<response><returncode>SUCCESS</returncode><recordings><recording>
+						<recordID>recording-id</recordID>
+						<metadata><bigblueswarm-tenant>localhost</bigblueswarm-tenant></metadata>
+					</recording></recordings></response>`
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte(value))),
+					}, nil
+				}
+				redisMock.ExpectSet(RecordingMapKey("recording-id"), "http://localhost:8080/bigbluebutton", 0).SetVal("OK")
+				redisMock.ExpectSet(OwnerKey(RecordingMapKey("recording-id")), "localhost", 0).SetErr(errors.New("redis error"))
+				redisMock.ExpectKeys(RecodingPattern()).SetVal([]string{RecordingMapKey("recording-id")})
+				redisMock.ExpectMGet(RecordingMapKey("recording-id")).SetVal([]interface{}{"http://localhost:8080/bigbluebutton"})
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "failed to store record owner. redis error", logHook.LastEntry().Message)
+			},
+		},
+		{
+			Name: "An error returned while listing the stored recordings should be logged",
+			Mock: func() {
+				restclient.RestClientMockDoFunc = func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte("<response><returncode>SUCCESS</returncode></response>"))),
+					}, nil
+				}
+				redisMock.ExpectKeys(RecodingPattern()).SetErr(errors.New("redis error"))
+			},
+			Validator: func(t *testing.T, value interface{}, err error) {
+				assert.Equal(t, "failed to list stored recordings. redis error", logHook.LastEntry().Message)
+			},
+		},
 	}
 	server := doGenericInitialization()
 	server.InstanceManager = &admin.InstanceManagerMock{}
@@ -127,6 +154,7 @@ func TestPollRecordings(t *testing.T) {
 			test.Mock()
 			server.pollRecordings()
 			test.Validator(t, nil, nil)
+			assert.Nil(t, redisMock.ExpectationsWereMet())
 		})
 	}
 }
@@ -2,47 +2,23 @@
 package app
 
 import (
+	"path"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	log "github.com/sirupsen/logrus"
 )
 
-// RequestLogger is a custom request logger initialized with a request identifier
-type RequestLogger struct {
-	*log.Entry
-}
-
-func setLogger(c *gin.Context) {
-	c.Set("logger", newRequestLogger())
-}
-
-func getLogger(c *gin.Context) *RequestLogger {
-	return c.MustGet("logger").(*RequestLogger)
-}
-
-func newRequestLogger() *RequestLogger {
-	return &RequestLogger{
-		log.WithFields(log.Fields{
-			"request_id": uuid.New().String(),
-		}),
-	}
-}
-
-func (rl *RequestLogger) setFields(fields log.Fields) *RequestLogger {
-	for k, v := range fields {
-		rl.Data[k] = v
-	}
-
-	return rl
-}
+// setLogger stores the request logger in the request context. The logged action is the requested BigBlueButton API
+// action
+var setLogger = utils.RequestLoggerMiddleware(func(c *gin.Context) string {
+	return path.Base(c.FullPath())
+})
 
-func (rl *RequestLogger) addField(key string, value string) *RequestLogger {
-	rl.Data[key] = value
-	return rl
+func getLogger(c *gin.Context) *utils.RequestLogger {
+	return utils.GetRequestLogger(c)
 }
 
-func (rl *RequestLogger) dup() *RequestLogger {
-	return &RequestLogger{
-		rl.Dup(),
-	}
+func newRequestLogger() *utils.RequestLogger {
+	return utils.NewRequestLogger(uuid.New().String())
 }
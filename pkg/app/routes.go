@@ -6,19 +6,52 @@ import (
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
 )
 
+// initRoutes loads the routes. If an admin address is configured, the main router only serves the BigBlueButton API
+// and the tenant API, authenticated by the tenant api key, and the other routes are served by the admin router
 func (s *Server) initRoutes() {
-	adm := admin.CreateAdmin(s.InstanceManager, s.TenantManager, s.Balancer, s.Config)
-	routes := append(*s.Routes(), *adm.Routes()...)
-	for _, route := range routes {
+	adm := admin.CreateAdmin(s.InstanceManager, s.TenantManager, s.AuditManager, s.HistoryManager, s.WebhookManager, s.Balancer, s.Config)
+	for _, route := range *s.Routes() {
+		router := s.adminRouter()
+		if route.Path == api.Path(api.BigBlueButton) {
+			router = s.Router
+		}
+
+		route.Load(router.Group(route.Path))
+	}
+
+	for _, route := range *adm.TenantRoutes() {
 		route.Load(s.Router.Group(route.Path))
 	}
+
+	for _, route := range *adm.Routes() {
+		route.Load(s.adminRouter().Group(route.Path, s.adminMiddlewares()...))
+	}
 }
 
 // Routes returns the server routes
 func (s *Server) Routes() *[]api.EndpointGroup {
 	return &[]api.EndpointGroup{
+		{
+			Path: "/healthz",
+			Endpoints: []interface{}{
+				api.Endpoint{
+					Method:  http.MethodGet,
+					Handler: s.Liveness,
+				},
+			},
+		},
+		{
+			Path: "/readyz",
+			Endpoints: []interface{}{
+				api.Endpoint{
+					Method:  http.MethodGet,
+					Handler: s.Readiness,
+				},
+			},
+		},
 		{
 			Path: api.Path(api.BigBlueButton),
 			Endpoints: []interface{}{
@@ -33,6 +66,12 @@ func (s *Server) Routes() *[]api.EndpointGroup {
 							Method:  http.MethodGet,
 							Handler: s.HealthCheck,
 						},
+						api.Endpoint{
+							Handler: tracing.Middleware,
+						},
+						api.Endpoint{
+							Handler: s.collectMetrics,
+						},
 						api.Endpoint{
 							Handler: setLogger,
 						},
@@ -42,6 +81,12 @@ func (s *Server) Routes() *[]api.EndpointGroup {
 						api.Endpoint{
 							Handler: s.ChecksumValidation,
 						},
+						api.Endpoint{
+							Handler: s.checkRateLimit,
+						},
+						api.Endpoint{
+							Handler: s.namespaceMeetingIDs,
+						},
 						api.Endpoint{
 							Method:  http.MethodGet,
 							Handler: s.Create,
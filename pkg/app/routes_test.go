@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/api"
+	"github.com/bigblueswarm/test_utils/pkg/test"
+	log "github.com/sirupsen/logrus"
+	LogTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// signedRequest returns a BigBlueButton API request signed with the default secret
+func signedRequest(action string, params string) *http.Request {
+	checksum := &api.Checksum{Secret: test.DefaultSecret(), Action: action, Params: params}
+	value, _ := checksum.Process()
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/bigbluebutton/api/%s?%s&checksum=%s", action, params, value), nil)
+	req.Host = "localhost"
+	return req
+}
+
+// routedServer returns a server serving the routes for the tenant
+func routedServer(tenant *admin.Tenant) *Server {
+	server := doGenericInitialization()
+	server.Config.BigBlueSwarm.Secret = test.DefaultSecret()
+	server.RateLimiter = &RateLimiterMock{}
+	admin.ResolveTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return tenant, nil
+	}
+	server.initRoutes()
+	return server
+}
+
+func TestRateLimitRoute(t *testing.T) {
+	tenant := &admin.Tenant{Spec: &admin.TenantSpec{
+		Host:       "localhost",
+		RateLimits: map[string]*admin.RateLimit{"*": {Requests: 1}},
+	}}
+
+	t.Run("a request with an invalid checksum should not consume the tenant rate limit", func(t *testing.T) {
+		server := routedServer(tenant)
+		AllowRateLimiterMockFunc = func(key string, limit *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+			panic("rate limiter should not be called")
+		}
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/bigbluebutton/api/getMeetings?checksum=invalid", nil)
+		req.Host = "localhost"
+		server.Router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), api.DefaultChecksumError().MessageKey)
+	})
+
+	t.Run("a request with a valid checksum should consume the tenant rate limit", func(t *testing.T) {
+		server := routedServer(tenant)
+		AllowRateLimiterMockFunc = func(key string, limit *admin.RateLimit, now time.Time) (bool, time.Duration, error) {
+			return false, time.Second, nil
+		}
+
+		w := httptest.NewRecorder()
+		server.Router.ServeHTTP(w, signedRequest(api.GetMeetings, ""))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
+
+func TestNamespaceMeetingIDsRoute(t *testing.T) {
+	tenant := &admin.Tenant{Spec: &admin.TenantSpec{
+		Host:               "localhost",
+		MeetingIDNamespace: admin.MeetingIDNamespacePrefix,
+	}}
+
+	for _, action := range []string{api.Join, api.IsMeetingRunning, api.End} {
+		t.Run(fmt.Sprintf("%s handler should read the namespaced meeting id", action), func(t *testing.T) {
+			server := routedServer(tenant)
+			redisMock.ExpectGet(MeetingMapKey("localhost_1")).SetVal("")
+
+			w := httptest.NewRecorder()
+			server.Router.ServeHTTP(w, signedRequest(action, "meetingID=1"))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), api.MessageKeys().NotFound)
+			assert.Nil(t, redisMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccessLogRoute(t *testing.T) {
+	logHook := LogTest.NewGlobal()
+	log.AddHook(logHook)
+	server := routedServer(&admin.Tenant{Spec: &admin.TenantSpec{Host: "localhost"}})
+	redisMock.ExpectGet(MeetingMapKey("1")).SetVal("")
+
+	w := httptest.NewRecorder()
+	server.Router.ServeHTTP(w, signedRequest(api.Join, "fullName=doe&meetingID=1&password=secret"))
+	assert.Nil(t, redisMock.ExpectationsWereMet())
+	assert.Equal(t, "request processed", logHook.LastEntry().Message)
+	assert.Equal(t, "fullName=doe&meetingID=1&password=REDACTED", logHook.LastEntry().Data["params"])
+}
@@ -0,0 +1,80 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	log "github.com/sirupsen/logrus"
+)
+
+// secretUsageFlushInterval is the delay between two writes of the accumulated secrets usage
+const secretUsageFlushInterval = 10 * time.Second
+
+// secretUsageEntry identifies a tenant secret
+type secretUsageEntry struct {
+	hostname    string
+	fingerprint string
+}
+
+// secretUsageBatch accumulates the tenants secrets usage so the BigBlueButton API requests don't wait for a redis write.
+// The accumulated usage is written by the secret usage poller
+type secretUsageBatch struct {
+	mu    sync.Mutex
+	usage map[secretUsageEntry]*admin.SecretUsage
+}
+
+// track accounts a request authenticated using the secret identified by the fingerprint
+func (b *secretUsageBatch) track(hostname string, fingerprint string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.usage == nil {
+		b.usage = map[secretUsageEntry]*admin.SecretUsage{}
+	}
+
+	entry := secretUsageEntry{hostname: hostname, fingerprint: fingerprint}
+	usage, ok := b.usage[entry]
+	if !ok {
+		usage = &admin.SecretUsage{}
+		b.usage[entry] = usage
+	}
+
+	usage.Count++
+	usage.LastUsed = &now
+}
+
+// take returns the accumulated usage and resets the batch
+func (b *secretUsageBatch) take() map[secretUsageEntry]*admin.SecretUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	usage := b.usage
+	b.usage = nil
+	return usage
+}
+
+// flushSecretUsage writes the accumulated secrets usage. The usage failing to be written is dropped
+func (s *Server) flushSecretUsage() {
+	for entry, usage := range s.secretUsage.take() {
+		if err := s.TenantManager.TrackSecretUsage(entry.hostname, entry.fingerprint, usage.Count, *usage.LastUsed); err != nil {
+			log.WithFields(log.Fields{"tenant": entry.hostname, "secret": entry.fingerprint}).Errorln("tenant manager failed to track secret usage.", err)
+		}
+	}
+}
+
+// launchSecretUsagePoller regularly writes the accumulated secrets usage until the context is done. The remaining usage
+// is written before returning
+func (s *Server) launchSecretUsagePoller(ctx context.Context) {
+	ticker := time.NewTicker(secretUsageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flushSecretUsage()
+		case <-ctx.Done():
+			s.flushSecretUsage()
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushSecretUsage(t *testing.T) {
+	server := &Server{TenantManager: &admin.TenantManagerMock{}}
+	tracked := map[string]int64{}
+	var last time.Time
+	admin.TrackSecretUsageTenantManagerMockFunc = func(hostname string, fingerprint string, count int64, lastUsed time.Time) error {
+		tracked[hostname+"/"+fingerprint] = count
+		if fingerprint == "primary" {
+			last = lastUsed
+		}
+
+		return nil
+	}
+
+	t.Run("the accumulated usage should be written once per secret", func(t *testing.T) {
+		now := time.Now()
+		server.secretUsage.track("localhost", "primary", now.Add(-time.Second))
+		server.secretUsage.track("localhost", "primary", now)
+		server.secretUsage.track("localhost", "secondary", now)
+		server.flushSecretUsage()
+		assert.Equal(t, map[string]int64{"localhost/primary": 2, "localhost/secondary": 1}, tracked)
+		assert.Equal(t, now, last)
+	})
+
+	t.Run("a flushed usage should not be written again", func(t *testing.T) {
+		tracked = map[string]int64{}
+		server.flushSecretUsage()
+		assert.Empty(t, tracked)
+	})
+
+	t.Run("a usage failing to be written should be dropped", func(t *testing.T) {
+		admin.TrackSecretUsageTenantManagerMockFunc = func(hostname string, fingerprint string, count int64, lastUsed time.Time) error {
+			return errors.New("redis error")
+		}
+
+		server.secretUsage.track("localhost", "primary", time.Now())
+		server.flushSecretUsage()
+		assert.Empty(t, server.secretUsage.take())
+	})
+}
+
+func TestLaunchSecretUsagePoller(t *testing.T) {
+	server := &Server{TenantManager: &admin.TenantManagerMock{}}
+	var count int64
+	admin.TrackSecretUsageTenantManagerMockFunc = func(hostname string, fingerprint string, c int64, lastUsed time.Time) error {
+		count = c
+		return nil
+	}
+
+	server.secretUsage.track("localhost", "primary", time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	server.launchSecretUsagePoller(ctx)
+	assert.Equal(t, int64(1), count)
+}
@@ -2,14 +2,23 @@
 package app
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/balancer"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/breaker"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/events"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/restclient"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 
 	sentrygin "github.com/getsentry/sentry-go/gin"
@@ -19,14 +28,34 @@ import (
 // SentryEnabled tells if sentry is enabled or not. If it is, we add a gin hook for performance monitoring
 var SentryEnabled = false
 
+// JSONLogEnabled tells if logs are formatted in JSON. If it is, the gin text access logs are disabled in favor of the
+// request loggers access logs
+var JSONLogEnabled = false
+
 // Server struct represents an object containings the server router and its configuration
 type Server struct {
-	Router          *gin.Engine
+	Router *gin.Engine
+	// AdminRouter serves the admin API on the dedicated admin listener. It is nil if the admin API is served by Router
+	AdminRouter     *gin.Engine
 	Config          *config.Config
 	InstanceManager admin.InstanceManager
 	TenantManager   admin.TenantManager
+	AuditManager    admin.AuditManager
+	HistoryManager  admin.MeetingHistoryManager
+	WebhookManager  admin.WebhookManager
 	Mapper          Mapper
+	Pool            Pool
+	RateLimiter     RateLimiter
 	Balancer        balancer.Balancer
+	// knownRecordings indexes the instance of the recordings found by the previous recordings poll
+	knownRecordings map[string]string
+	secretUsage     secretUsageBatch
+	webhooks        *tenantWebhooks
+	listeners       []*listener
+	tlsConfig       *tls.Config
+	adminNetworks   []*net.IPNet
+	// shuttingDown is set once the shutdown is requested so the readiness probe fails while the requests are drained
+	shuttingDown atomic.Bool
 }
 
 // NewServer creates a new server based on given configuration
@@ -36,33 +65,222 @@ func NewServer(config *config.Config) *Server {
 
 	restclient.Init()
 
-	router := gin.Default()
-	if SentryEnabled {
-		log.Info("Sentry enabled: adding gin middleware")
-		router.Use(sentrygin.New(sentrygin.Options{
-			Repanic: true,
-		}))
+	var adminRouter *gin.Engine
+	if config.Admin.Address != "" {
+		adminRouter = newRouter()
 	}
 
 	return &Server{
-		Router:          router,
+		Router:          newRouter(),
+		AdminRouter:     adminRouter,
 		Config:          config,
 		InstanceManager: admin.NewInstanceManager(*redisClient),
 		TenantManager:   admin.NewTenantManager(*redisClient),
+		AuditManager:    admin.NewAuditManager(*redisClient, &config.Admin.Audit),
+		HistoryManager:  admin.NewMeetingHistoryManager(*redisClient),
+		WebhookManager:  admin.NewWebhookManager(*redisClient, &config.Events.TenantWebhooks),
 		Mapper:          NewMapper(*redisClient),
+		Pool:            NewPool(*redisClient),
+		RateLimiter:     NewRateLimiter(*redisClient),
 		Balancer:        balancer.New(influxClient, &config.Balancer, &config.IDB),
 	}
 }
 
-// Run launches the server
-func (s *Server) Run() error {
-	s.initRoutes()
-	go s.launchRecordingPoller()
-	err := s.Router.Run(fmt.Sprintf(":%d", s.Config.Port))
+// newRouter creates a gin engine with the logger, recovery and sentry middlewares
+func newRouter() *gin.Engine {
+	router := gin.New()
+	if !JSONLogEnabled {
+		router.Use(gin.Logger())
+	}
+
+	router.Use(gin.Recovery())
+	if SentryEnabled {
+		log.Info("Sentry enabled: adding gin middleware")
+		router.Use(sentrygin.New(sentrygin.Options{
+			Repanic: true,
+		}))
+	}
+
+	return router
+}
+
+// Run launches the server and blocks until the context is done or a listener fails. The server is then gracefully
+// shut down: the readiness probe fails during the shutdown delay, then the listeners stop accepting requests and the
+// in-flight requests are drained until the shutdown timeout. The pollers are stopped and the queued events and traces
+// are flushed last, within the shutdown timeout
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.initTrustedProxies(); err != nil {
+		return err
+	}
+
+	if err := s.initAdminAllowlist(); err != nil {
+		return err
+	}
+
+	if err := restclient.InitWithConfig(&s.Config.HTTPClient); err != nil {
+		return fmt.Errorf("failed to initialize http client: %s", err)
+	}
 
+	if err := s.checkTimeouts(); err != nil {
+		return err
+	}
+
+	shutdownTimeout, err := time.ParseDuration(s.Config.BigBlueSwarm.ShutdownTimeout)
 	if err != nil {
+		return fmt.Errorf("failed to parse shutdown timeout: %s", err)
+	}
+
+	shutdownDelay, err := time.ParseDuration(s.Config.BigBlueSwarm.ShutdownDelay)
+	if err != nil {
+		return fmt.Errorf("failed to parse shutdown delay: %s", err)
+	}
+
+	breaker.Init(&s.Config.CircuitBreaker)
+	breaker.Breakers.OnOpen = publishInstanceOffline
+	shutdownTracing, err := tracing.Init(&s.Config.Tracing)
+	if err != nil {
+		return err
+	}
+
+	shutdownEvents, err := events.Init(&s.Config.Events, utils.RedisClient(s.Config))
+	if err != nil {
+		flush(shutdownTimeout, shutdownTracing)
 		return err
 	}
 
+	err = s.run(ctx, shutdownDelay, shutdownTimeout)
+	flush(shutdownTimeout, shutdownEvents, shutdownTracing)
+	return err
+}
+
+// checkTimeouts checks the instance and readiness timeouts, read on each fan-out and readiness probe, can be parsed
+func (s *Server) checkTimeouts() error {
+	for name, value := range map[string]string{
+		"instance timeout":  s.Config.BigBlueSwarm.InstanceTimeout,
+		"readiness timeout": s.Config.BigBlueSwarm.ReadinessTimeout,
+	} {
+		if value == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("failed to parse %s: %s", name, err)
+		}
+	}
+
 	return nil
 }
+
+// run starts the listeners and the pollers and blocks until the context is done or a listener fails, then shuts them
+// down
+func (s *Server) run(ctx context.Context, shutdownDelay time.Duration, shutdownTimeout time.Duration) error {
+	if err := s.initTenantWebhooks(); err != nil {
+		return err
+	}
+
+	if err := s.initMetrics(); err != nil {
+		return err
+	}
+
+	if err := s.initTLS(); err != nil {
+		return err
+	}
+
+	s.initRoutes()
+	s.addSecureListener("bigblueswarm", fmt.Sprintf(":%d", s.Config.Port), s.Router)
+	if s.AdminRouter != nil {
+		s.addSecureListener("admin", s.Config.Admin.Address, s.AdminRouter)
+	}
+
+	pollersCtx, stopPollers := context.WithCancel(context.Background())
+	defer stopPollers()
+	pollers := s.launchPollers(pollersCtx)
+	errs := s.serve()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		s.preStop(shutdownDelay)
+		log.Info("shutdown requested, draining in-flight requests")
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if shutdownErr := s.shutdown(drainCtx); err == nil {
+		err = shutdownErr
+	}
+
+	stopPollers()
+	if !waitGroup(drainCtx, pollers) {
+		log.Warn("pollers did not stop before the shutdown timeout")
+	}
+
+	return err
+}
+
+// launchPollers starts the pollers. The returned wait group is done once all the pollers stopped
+func (s *Server) launchPollers(ctx context.Context) *sync.WaitGroup {
+	pollers := &sync.WaitGroup{}
+	for _, poller := range []func(context.Context){
+		s.launchRecordingPoller,
+		s.launchMeetingsPoller,
+		s.launchTenantWebhooks,
+		s.launchTenantEventsScheduler,
+		s.launchSecretUsagePoller,
+	} {
+		pollers.Add(1)
+		go func(poller func(context.Context)) {
+			defer pollers.Done()
+			poller(ctx)
+		}(poller)
+	}
+
+	return pollers
+}
+
+// flush calls the shutdown functions in order, all of them sharing the shutdown timeout
+func flush(timeout time.Duration, shutdowns ...func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, shutdown := range shutdowns {
+		if err := shutdown(ctx); err != nil {
+			log.Errorln("failed to flush before shutdown.", err)
+		}
+	}
+}
+
+// waitGroup waits for the wait group until the context is done. It returns false if the context is done first
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *Server) initTrustedProxies() error {
+	proxies := s.Config.BigBlueSwarm.TrustedProxies
+	if proxies == nil {
+		proxies = utils.DefaultTrustedProxies
+	}
+
+	if err := utils.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("failed to initialize trusted proxies: %s", err)
+	}
+
+	if s.AdminRouter != nil {
+		if err := s.AdminRouter.SetTrustedProxies(proxies); err != nil {
+			return err
+		}
+	}
+
+	return s.Router.SetTrustedProxies(proxies)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaunchPollers(t *testing.T) {
+	server := &Server{Config: &config.Config{
+		BigBlueSwarm: config.BigBlueSwarm{
+			RecordingsPollInterval: "1h",
+			MeetingsPollInterval:   "1h",
+		},
+	}}
+	server.webhooks = &tenantWebhooks{interval: time.Hour, queue: make(chan *events.Event)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pollers := server.launchPollers(ctx)
+	cancel()
+
+	timeout, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	assert.True(t, waitGroup(timeout, pollers))
+}
+
+func TestCheckTimeouts(t *testing.T) {
+	server := &Server{Config: &config.Config{
+		BigBlueSwarm: config.BigBlueSwarm{
+			InstanceTimeout:  "5s",
+			ReadinessTimeout: "1s",
+		},
+	}}
+	assert.Nil(t, server.checkTimeouts())
+
+	server.Config.BigBlueSwarm.ReadinessTimeout = ""
+	assert.Nil(t, server.checkTimeouts())
+
+	server.Config.BigBlueSwarm.ReadinessTimeout = "soon"
+	assert.NotNil(t, server.checkTimeouts())
+
+	server.Config.BigBlueSwarm.ReadinessTimeout = "1s"
+	server.Config.BigBlueSwarm.InstanceTimeout = "soon"
+	assert.NotNil(t, server.checkTimeouts())
+}
+
+func TestInitRoutes(t *testing.T) {
+	type test struct {
+		name          string
+		address       string
+		path          string
+		expectedMain  int
+		expectedAdmin int
+	}
+
+	tests := []test{
+		{
+			name:          "admin API should be served by the main router if no admin address is configured",
+			path:          "/admin/api/instances",
+			expectedMain:  http.StatusUnauthorized,
+			expectedAdmin: http.StatusNotFound,
+		},
+		{
+			name:          "admin API should only be served by the admin router if an admin address is configured",
+			address:       "127.0.0.1:8091",
+			path:          "/admin/api/instances",
+			expectedMain:  http.StatusNotFound,
+			expectedAdmin: http.StatusUnauthorized,
+		},
+		{
+			name:          "tenant API should be served by the main router if no admin address is configured",
+			path:          "/tenant/api/meetings",
+			expectedMain:  http.StatusUnauthorized,
+			expectedAdmin: http.StatusNotFound,
+		},
+		{
+			name:          "tenant API should only be served by the main router if an admin address is configured",
+			address:       "127.0.0.1:8091",
+			path:          "/tenant/api/meetings",
+			expectedMain:  http.StatusUnauthorized,
+			expectedAdmin: http.StatusNotFound,
+		},
+		{
+			name:          "probes should only be served by the admin router if an admin address is configured",
+			address:       "127.0.0.1:8091",
+			path:          "/healthz",
+			expectedMain:  http.StatusNotFound,
+			expectedAdmin: http.StatusOK,
+		},
+		{
+			name:          "BigBlueButton API should only be served by the main router",
+			address:       "127.0.0.1:8091",
+			path:          "/bigbluebutton",
+			expectedMain:  http.StatusOK,
+			expectedAdmin: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewServer(&config.Config{Admin: config.AdminConfig{APIKey: "admin_key", Address: test.address}})
+			server.initRoutes()
+			adminRouter := server.AdminRouter
+			if adminRouter == nil {
+				adminRouter = newRouter()
+			}
+
+			w := httptest.NewRecorder()
+			server.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+			assert.Equal(t, test.expectedMain, w.Code)
+
+			w = httptest.NewRecorder()
+			adminRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+			assert.Equal(t, test.expectedAdmin, w.Code)
+		})
+	}
+}
@@ -0,0 +1,301 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/events"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// tenantWebhooks holds the tenants webhooks delivery settings parsed from the configuration
+type tenantWebhooks struct {
+	interval     time.Duration
+	maxAttempts  int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	batchSize    int64
+	concurrency  int
+	client       *http.Client
+	queue        chan *events.Event
+}
+
+func (s *Server) initTenantWebhooks() error {
+	conf := &s.Config.Events.TenantWebhooks
+	durations := map[string]time.Duration{}
+	for name, value := range map[string]string{
+		"interval":     conf.Interval,
+		"timeout":      conf.Timeout,
+		"retryBackoff": conf.RetryBackoff,
+		"maxBackoff":   conf.MaxBackoff,
+		"retention":    conf.Retention,
+	} {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse tenant webhooks %s: %s", name, err)
+		}
+
+		durations[name] = duration
+	}
+
+	if durations["timeout"] <= 0 {
+		return fmt.Errorf("tenant webhooks timeout must be positive")
+	}
+
+	concurrency := conf.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	s.webhooks = &tenantWebhooks{
+		interval:     durations["interval"],
+		maxAttempts:  conf.MaxAttempts,
+		retryBackoff: durations["retryBackoff"],
+		maxBackoff:   durations["maxBackoff"],
+		batchSize:    conf.BatchSize,
+		concurrency:  concurrency,
+		client:       &http.Client{Timeout: durations["timeout"]},
+		queue:        make(chan *events.Event, s.Config.Events.BufferSize),
+	}
+
+	events.OnPublish = s.queueTenantEvent
+	return nil
+}
+
+// queueTenantEvent queues a tenant event for its webhooks deliveries scheduling. Events are dropped when the queue is full
+func (s *Server) queueTenantEvent(event *events.Event) {
+	if event.Tenant == "" {
+		return
+	}
+
+	select {
+	case s.webhooks.queue <- event:
+	default:
+		log.WithFields(log.Fields{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+			"tenant":     event.Tenant,
+		}).Warn("tenant webhooks queue is full, event dropped.")
+	}
+}
+
+// scheduleTenantEvent schedules a delivery of the event to each tenant webhook matching the event type
+func (s *Server) scheduleTenantEvent(event *events.Event) {
+	logger := log.WithFields(log.Fields{
+		"context":    "tenant_webhooks",
+		"event_id":   event.ID,
+		"event_type": event.Type,
+		"tenant":     event.Tenant,
+	})
+
+	tenant, err := s.TenantManager.GetTenant(event.Tenant)
+	if err != nil {
+		logger.Errorln("failed to retrieve tenant.", err)
+		return
+	}
+
+	if tenant == nil {
+		return
+	}
+
+	webhooks := tenant.Webhooks(event.Type)
+	if len(webhooks) == 0 {
+		return
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		logger.Errorln("failed to marshal event.", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		delivery := &admin.WebhookDelivery{
+			ID:        uuid.New().String(),
+			Tenant:    event.Tenant,
+			URL:       webhook.URL,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Event:     string(value),
+			Status:    admin.WebhookDeliveryPending,
+			CreatedAt: event.Time,
+		}
+
+		if err := s.WebhookManager.Schedule(delivery); err != nil {
+			logger.Dup().WithField("url", webhook.URL).Errorln("failed to schedule webhook delivery.", err)
+		}
+	}
+}
+
+// retryDelay returns the delay before the next attempt of a delivery that failed the given number of attempts
+func (w *tenantWebhooks) retryDelay(attempts int) time.Duration {
+	delay := w.retryBackoff
+	for i := 1; i < attempts && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > w.maxBackoff {
+		return w.maxBackoff
+	}
+
+	return delay
+}
+
+// lease returns the duration a claimed batch is reserved for. The deliveries are sent by concurrency workers, each
+// delivery taking at most the request timeout, plus one request timeout covering the deliveries updates
+func (w *tenantWebhooks) lease() time.Duration {
+	rounds := (w.batchSize + int64(w.concurrency) - 1) / int64(w.concurrency)
+	return time.Duration(rounds+1) * w.client.Timeout
+}
+
+// sendWebhook posts the delivery event to the tenant webhook. It fails if the webhook is no longer configured
+func (s *Server) sendWebhook(ctx context.Context, tenant *admin.Tenant, delivery *admin.WebhookDelivery) error {
+	if tenant == nil {
+		return fmt.Errorf("tenant %s no longer exists", delivery.Tenant)
+	}
+
+	webhook := tenant.Webhook(delivery.URL)
+	if webhook == nil {
+		return fmt.Errorf("webhook %s is no longer configured", delivery.URL)
+	}
+
+	event := &events.Event{}
+	if err := json.Unmarshal([]byte(delivery.Event), event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %s", err)
+	}
+
+	sink := &events.WebhookSink{
+		URL:    webhook.URL,
+		Secret: webhook.WebhookSecret(tenant, s.Config.BigBlueSwarm.Secret),
+		Client: s.webhooks.client,
+	}
+
+	return sink.Publish(ctx, event)
+}
+
+// deliver sends the delivery and records the attempt. A failed delivery is retried with an exponential backoff until
+// it reaches the maximum number of attempts, then it is moved to the dead letter queue. A delivery interrupted by the
+// poller shutdown is not recorded: it is sent again once its lease expires
+func (s *Server) deliver(ctx context.Context, logger *log.Entry, tenant *admin.Tenant, delivery *admin.WebhookDelivery, now time.Time) {
+	err := s.sendWebhook(ctx, tenant, delivery)
+	if err != nil && ctx.Err() != nil {
+		logger.Infoln("webhook delivery interrupted.", err)
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.NextAttemptAt = nil
+	switch {
+	case err == nil:
+		delivery.Status = admin.WebhookDeliveryDelivered
+		delivery.LastError = ""
+	case delivery.Attempts >= s.webhooks.maxAttempts:
+		delivery.Status = admin.WebhookDeliveryDead
+		delivery.LastError = err.Error()
+		logger.Warnln("webhook delivery moved to the dead letter queue.", err)
+	default:
+		next := now.Add(s.webhooks.retryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+		logger.Infoln("webhook delivery failed, retrying later.", err)
+	}
+
+	if err := s.WebhookManager.Update(delivery); err != nil {
+		logger.Errorln("failed to update webhook delivery.", err)
+	}
+}
+
+// deliverTenantWebhooks sends the due deliveries with at most concurrency deliveries at once. The tenants are
+// retrieved once per poll
+func (s *Server) deliverTenantWebhooks(ctx context.Context, now time.Time) {
+	logger := log.WithField("context", "tenant_webhooks")
+	deliveries, err := s.WebhookManager.Claim(now, s.webhooks.batchSize, s.webhooks.lease())
+	if err != nil {
+		logger.Errorln("failed to claim webhook deliveries.", err)
+		return
+	}
+
+	tenants := map[string]*admin.Tenant{}
+	slots := make(chan struct{}, s.webhooks.concurrency)
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		dLogger := logger.Dup().WithFields(log.Fields{
+			"tenant":      delivery.Tenant,
+			"delivery_id": delivery.ID,
+			"url":         delivery.URL,
+		})
+
+		tenant, ok := tenants[delivery.Tenant]
+		if !ok {
+			tenant, err = s.TenantManager.GetTenant(delivery.Tenant)
+			if err != nil {
+				dLogger.Errorln("failed to retrieve tenant.", err)
+				continue
+			}
+
+			tenants[delivery.Tenant] = tenant
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(tenant *admin.Tenant) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			s.deliver(ctx, dLogger, tenant, delivery, now)
+		}(tenant)
+	}
+
+	wg.Wait()
+}
+
+// launchTenantWebhooks sends the due deliveries until the context is done
+func (s *Server) launchTenantWebhooks(ctx context.Context) {
+	ticker := time.NewTicker(s.webhooks.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.deliverTenantWebhooks(ctx, now)
+		}
+	}
+}
+
+// launchTenantEventsScheduler schedules the queued tenant events deliveries until the context is done. It runs apart
+// from the deliveries so the queue is drained while a slow batch is sent. The events still queued are scheduled before
+// returning so they are delivered after a restart
+func (s *Server) launchTenantEventsScheduler(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.scheduleQueuedTenantEvents()
+			return
+		case event := <-s.webhooks.queue:
+			s.scheduleTenantEvent(event)
+		}
+	}
+}
+
+func (s *Server) scheduleQueuedTenantEvents() {
+	for {
+		select {
+		case event := <-s.webhooks.queue:
+			s.scheduleTenantEvent(event)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func tenantWebhooksServer(t *testing.T) *Server {
+	server := &Server{
+		Config: &config.Config{
+			BigBlueSwarm: config.BigBlueSwarm{Secret: "default"},
+			Events: config.EventsConfig{
+				BufferSize: 1,
+				TenantWebhooks: config.TenantWebhooksConfig{
+					Interval:     "1s",
+					Timeout:      "1s",
+					MaxAttempts:  3,
+					RetryBackoff: "10s",
+					MaxBackoff:   "30s",
+					BatchSize:    10,
+					Concurrency:  5,
+					Retention:    "1h",
+				},
+			},
+		},
+		TenantManager:  &admin.TenantManagerMock{},
+		WebhookManager: &admin.WebhookManagerMock{},
+	}
+
+	assert.Nil(t, server.initTenantWebhooks())
+	t.Cleanup(func() { events.OnPublish = nil })
+	return server
+}
+
+func TestInitTenantWebhooks(t *testing.T) {
+	server := tenantWebhooksServer(t)
+	assert.NotNil(t, events.OnPublish)
+	assert.Equal(t, 10*time.Second, server.webhooks.retryDelay(1))
+	assert.Equal(t, 20*time.Second, server.webhooks.retryDelay(2))
+	assert.Equal(t, 30*time.Second, server.webhooks.retryDelay(5))
+	assert.Equal(t, 3*time.Second, server.webhooks.lease())
+
+	server.Config.Events.TenantWebhooks.Concurrency = 0
+	assert.Nil(t, server.initTenantWebhooks())
+	assert.Equal(t, 11*time.Second, server.webhooks.lease())
+
+	server.Config.Events.TenantWebhooks.Timeout = "soon"
+	assert.NotNil(t, server.initTenantWebhooks())
+
+	server.Config.Events.TenantWebhooks.Timeout = "0s"
+	assert.NotNil(t, server.initTenantWebhooks())
+}
+
+func TestQueueTenantEvent(t *testing.T) {
+	server := tenantWebhooksServer(t)
+	server.queueTenantEvent(events.New(events.InstanceOffline, "", nil))
+	assert.Equal(t, 0, len(server.webhooks.queue))
+
+	server.queueTenantEvent(events.New(events.MeetingCreated, "localhost", nil))
+	server.queueTenantEvent(events.New(events.MeetingCreated, "localhost", nil))
+	assert.Equal(t, 1, len(server.webhooks.queue))
+}
+
+func TestScheduleTenantEvent(t *testing.T) {
+	server := tenantWebhooksServer(t)
+	admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname, Webhooks: []*admin.TenantWebhook{
+			{URL: "https://example.com/meetings", Events: []string{"meeting.*"}},
+			{URL: "https://example.com/recordings", Events: []string{events.RecordingDiscovered}},
+		}}}, nil
+	}
+
+	scheduled := []*admin.WebhookDelivery{}
+	admin.ScheduleWebhookManagerMockFunc = func(delivery *admin.WebhookDelivery) error {
+		scheduled = append(scheduled, delivery)
+		return nil
+	}
+
+	event := events.New(events.MeetingCreated, "localhost", map[string]interface{}{"meeting_id": "meeting"})
+	server.scheduleTenantEvent(event)
+	assert.Equal(t, 1, len(scheduled))
+	assert.Equal(t, "https://example.com/meetings", scheduled[0].URL)
+	assert.Equal(t, admin.WebhookDeliveryPending, scheduled[0].Status)
+	assert.Equal(t, event.ID, scheduled[0].EventID)
+	assert.Contains(t, scheduled[0].Event, `"meeting_id":"meeting"`)
+
+	server.scheduleTenantEvent(events.New(events.PoolLimitReached, "localhost", nil))
+	assert.Equal(t, 1, len(scheduled))
+}
+
+func TestLaunchTenantEventsScheduler(t *testing.T) {
+	server := tenantWebhooksServer(t)
+	admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname, Webhooks: []*admin.TenantWebhook{{URL: "https://example.com/meetings"}}}}, nil
+	}
+
+	scheduled := make(chan *admin.WebhookDelivery, 1)
+	admin.ScheduleWebhookManagerMockFunc = func(delivery *admin.WebhookDelivery) error {
+		scheduled <- delivery
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.launchTenantEventsScheduler(ctx)
+		close(done)
+	}()
+
+	event := events.New(events.MeetingCreated, "localhost", nil)
+	server.queueTenantEvent(event)
+	select {
+	case delivery := <-scheduled:
+		assert.Equal(t, event.ID, delivery.EventID)
+	case <-time.After(time.Second):
+		assert.Fail(t, "queued event should be scheduled without waiting for the deliveries")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestDeliverTenantWebhooks(t *testing.T) {
+	server := tenantWebhooksServer(t)
+	status := http.StatusOK
+	var signature string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(events.SignatureHeader)
+		w.WriteHeader(status)
+	}))
+	defer hook.Close()
+
+	admin.GetTenantTenantManagerMockFunc = func(hostname string) (*admin.Tenant, error) {
+		return &admin.Tenant{Spec: &admin.TenantSpec{Host: hostname, Webhooks: []*admin.TenantWebhook{{URL: hook.URL}}}}, nil
+	}
+
+	var updated *admin.WebhookDelivery
+	admin.UpdateWebhookManagerMockFunc = func(delivery *admin.WebhookDelivery) error {
+		updated = delivery
+		return nil
+	}
+
+	event := `{"id":"event","type":"meeting.created","time":"2022-03-17T15:00:00Z","tenant":"localhost","data":{}}`
+	claim := func(delivery admin.WebhookDelivery) {
+		admin.ClaimWebhookManagerMockFunc = func(now time.Time, limit int64, lease time.Duration) ([]admin.WebhookDelivery, error) {
+			assert.Equal(t, int64(10), limit)
+			assert.Equal(t, 3*time.Second, lease)
+			return []admin.WebhookDelivery{delivery}, nil
+		}
+	}
+
+	now := time.Now().UTC()
+	t.Run("a successful delivery should be delivered", func(t *testing.T) {
+		claim(admin.WebhookDelivery{ID: "delivery", Tenant: "localhost", URL: hook.URL, Event: event, Status: admin.WebhookDeliveryPending})
+		server.deliverTenantWebhooks(context.Background(), now)
+		assert.Equal(t, admin.WebhookDeliveryDelivered, updated.Status)
+		assert.Equal(t, 1, updated.Attempts)
+		assert.Contains(t, signature, "sha256=")
+	})
+
+	t.Run("a failed delivery should be retried with a backoff", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		claim(admin.WebhookDelivery{ID: "delivery", Tenant: "localhost", URL: hook.URL, Event: event, Status: admin.WebhookDeliveryPending, Attempts: 1})
+		server.deliverTenantWebhooks(context.Background(), now)
+		assert.Equal(t, admin.WebhookDeliveryPending, updated.Status)
+		assert.Equal(t, 2, updated.Attempts)
+		assert.Equal(t, now.Add(20*time.Second), *updated.NextAttemptAt)
+		assert.NotEmpty(t, updated.LastError)
+	})
+
+	t.Run("the last failed attempt should move the delivery to the dead letter queue", func(t *testing.T) {
+		claim(admin.WebhookDelivery{ID: "delivery", Tenant: "localhost", URL: hook.URL, Event: event, Status: admin.WebhookDeliveryPending, Attempts: 2})
+		server.deliverTenantWebhooks(context.Background(), now)
+		assert.Equal(t, admin.WebhookDeliveryDead, updated.Status)
+		assert.Nil(t, updated.NextAttemptAt)
+	})
+
+	t.Run("a delivery to a removed webhook should fail", func(t *testing.T) {
+		claim(admin.WebhookDelivery{ID: "delivery", Tenant: "localhost", URL: "https://example.com/removed", Event: event, Status: admin.WebhookDeliveryPending})
+		server.deliverTenantWebhooks(context.Background(), now)
+		assert.Equal(t, admin.WebhookDeliveryPending, updated.Status)
+		assert.Contains(t, updated.LastError, "no longer configured")
+	})
+
+	t.Run("a delivery interrupted by the poller shutdown should not be recorded", func(t *testing.T) {
+		updated = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		claim(admin.WebhookDelivery{ID: "delivery", Tenant: "localhost", URL: hook.URL, Event: event, Status: admin.WebhookDeliveryPending})
+		server.deliverTenantWebhooks(ctx, now)
+		assert.Nil(t, updated)
+	})
+}
@@ -0,0 +1,58 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/certificate"
+)
+
+// initTLS creates the listeners TLS configuration from the configured certificate provider and registers the HTTP to
+// HTTPS redirect listener if configured. The redirect listener also answers the provider HTTP challenges
+func (s *Server) initTLS() error {
+	conf := &s.Config.TLS
+	if !conf.Enabled {
+		return nil
+	}
+
+	provider, err := certificate.New(conf)
+	if err != nil {
+		return fmt.Errorf("failed to initialize certificate provider: %s", err)
+	}
+
+	tlsConfig, err := certificate.TLSConfig(conf, provider)
+	if err != nil {
+		return err
+	}
+
+	s.tlsConfig = tlsConfig
+	if conf.RedirectPort == 0 {
+		return nil
+	}
+
+	var handler http.Handler = s.redirectHTTPS()
+	if challenge, ok := provider.(certificate.ChallengeHandler); ok {
+		handler = challenge.HTTPHandler(handler)
+	}
+
+	s.addListener("redirect", fmt.Sprintf(":%d", conf.RedirectPort), handler)
+	return nil
+}
+
+// redirectHTTPS permanently redirects the requests to the HTTPS listener, keeping the method and the body
+func (s *Server) redirectHTTPS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+
+		if s.Config.Port != 443 {
+			host = net.JoinHostPort(host, fmt.Sprint(s.Config.Port))
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitTLS(t *testing.T) {
+	t.Run("TLS disabled should not configure the listeners", func(t *testing.T) {
+		server := &Server{Config: &config.Config{}}
+		assert.Nil(t, server.initTLS())
+		assert.Nil(t, server.tlsConfig)
+		assert.Equal(t, 0, len(server.listeners))
+	})
+
+	t.Run("an invalid provider configuration should return an error", func(t *testing.T) {
+		server := &Server{Config: &config.Config{TLS: config.TLSConfig{Enabled: true, Provider: "file", ReloadInterval: "1m", MinVersion: "1.2"}}}
+		err := server.initTLS()
+		assert.Equal(t, "failed to initialize certificate provider: file certificate provider requires a certificate and a key file", err.Error())
+	})
+}
+
+func TestRedirectHTTPS(t *testing.T) {
+	type test struct {
+		name     string
+		port     config.Port
+		host     string
+		target   string
+		expected string
+	}
+
+	tests := []test{
+		{
+			name:     "a request should be redirected to the HTTPS port",
+			port:     8443,
+			host:     "bigblueswarm.localhost:8080",
+			target:   "/bigbluebutton/api/create?name=meeting",
+			expected: "https://bigblueswarm.localhost:8443/bigbluebutton/api/create?name=meeting",
+		},
+		{
+			name:     "the default HTTPS port should be omitted",
+			port:     443,
+			host:     "bigblueswarm.localhost",
+			target:   "/bigbluebutton/api",
+			expected: "https://bigblueswarm.localhost/bigbluebutton/api",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &Server{Config: &config.Config{Port: test.port}}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			req.Host = test.host
+			server.redirectHTTPS().ServeHTTP(w, req)
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, test.expected, w.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package app is the bigblueswarm core
+package app
+
+import (
+	"context"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/admin"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/tracing"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// resolveTenant resolves the request tenant in a traced span
+func (s *Server) resolveTenant(c *gin.Context) (*admin.Tenant, error) {
+	hostname := utils.GetHost(c)
+	_, span := tracing.Start(c.Request.Context(), "tenant.resolve", tracing.Attributes("tenant", hostname))
+	tenant, err := s.TenantManager.ResolveTenant(hostname)
+	tracing.End(span, err)
+	return tenant, err
+}
+
+// mapper returns the server mapper tracing its operations as children of the context span
+func (s *Server) mapper(ctx context.Context) Mapper {
+	return &tracedMapper{mapper: s.Mapper, ctx: ctx}
+}
+
+type tracedMapper struct {
+	mapper Mapper
+	ctx    context.Context
+}
+
+func (m *tracedMapper) trace(operation string, key string, call func() error) error {
+	_, span := tracing.Start(m.ctx, "mapper."+operation, tracing.Attributes("mapper.key", key))
+	err := call()
+	tracing.End(span, err)
+	return err
+}
+
+func (m *tracedMapper) Add(key string, host string) error {
+	return m.trace("add", key, func() error {
+		return m.mapper.Add(key, host)
+	})
+}
+
+func (m *tracedMapper) Get(key string) (value string, err error) {
+	err = m.trace("get", key, func() error {
+		value, err = m.mapper.Get(key)
+		return err
+	})
+
+	return value, err
+}
+
+func (m *tracedMapper) SetOwner(key string, tenant string) error {
+	return m.trace("set_owner", key, func() error {
+		return m.mapper.SetOwner(key, tenant)
+	})
+}
+
+func (m *tracedMapper) GetOwner(key string) (value string, err error) {
+	err = m.trace("get_owner", key, func() error {
+		value, err = m.mapper.GetOwner(key)
+		return err
+	})
+
+	return value, err
+}
+
+func (m *tracedMapper) ClaimOwner(key string, tenant string) (claimed bool, err error) {
+	err = m.trace("claim_owner", key, func() error {
+		claimed, err = m.mapper.ClaimOwner(key, tenant)
+		return err
+	})
+
+	return claimed, err
+}
+
+func (m *tracedMapper) RemoveOwner(key string) error {
+	return m.trace("remove_owner", key, func() error {
+		return m.mapper.RemoveOwner(key)
+	})
+}
+
+func (m *tracedMapper) Remove(key string) error {
+	return m.trace("remove", key, func() error {
+		return m.mapper.Remove(key)
+	})
+}
+
+func (m *tracedMapper) DeleteAll(pattern string) error {
+	return m.trace("delete_all", pattern, func() error {
+		return m.mapper.DeleteAll(pattern)
+	})
+}
+
+func (m *tracedMapper) List(pattern string) (sessions map[string]string, err error) {
+	err = m.trace("list", pattern, func() error {
+		sessions, err = m.mapper.List(pattern)
+		return err
+	})
+
+	return sessions, err
+}
+
+func (m *tracedMapper) Count(pattern string) (count int64, err error) {
+	err = m.trace("count", pattern, func() error {
+		count, err = m.mapper.Count(pattern)
+		return err
+	})
+
+	return count, err
+}
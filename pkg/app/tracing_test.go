@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracedMapper(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+	mr, client := newMiniRedis(t)
+	server := &Server{Mapper: NewMapper(*client)}
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	m := server.mapper(ctx)
+	assert.Nil(t, m.Add(MeetingMapKey(id), host))
+	value, err := m.Get(MeetingMapKey(id))
+	assert.Nil(t, err)
+	assert.Equal(t, host, value)
+
+	mr.SetError("redis error")
+	_, err = m.GetOwner(MeetingMapKey(id))
+	assert.NotNil(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 4, len(spans))
+	for i, name := range []string{"mapper.add", "mapper.get", "mapper.get_owner"} {
+		assert.Equal(t, name, spans[i].Name)
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[i].Parent.SpanID())
+	}
+
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	assert.Equal(t, codes.Error, spans[2].Status.Code)
+}
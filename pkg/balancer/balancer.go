@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/breaker"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 	influxdb "github.com/influxdata/influxdb-client-go/v2/api"
@@ -68,6 +69,11 @@ func (b *InfluxDBBalancer) filterOnlineInstances(instances []string) ([]string,
 
 // Process compute data to find a bigbluebutton server
 func (b *InfluxDBBalancer) Process(instances []string) (string, error) {
+	instances = breaker.Breakers.Available(instances)
+	if len(instances) == 0 {
+		return "", errors.New("no instance online to process a balancer request")
+	}
+
 	instances, err := b.filterOnlineInstances(instances)
 	if err != nil {
 		return "", err
@@ -165,7 +171,20 @@ func (b *InfluxDBBalancer) ClusterStatus(instances []string) ([]InstanceStatus,
 		return nil, err
 	}
 
-	return parseClusterStatusResult(result), nil
+	return withCircuitBreakers(parseClusterStatusResult(result)), nil
+}
+
+// withCircuitBreakers set the instances circuit breaker state. An instance with an open circuit breaker is down
+func withCircuitBreakers(instances []InstanceStatus) []InstanceStatus {
+	for i := range instances {
+		state := breaker.Breakers.State(instances[i].Host)
+		instances[i].CircuitBreaker = string(state)
+		if state == breaker.Open {
+			instances[i].APIStatus = apiStatusToString(0)
+		}
+	}
+
+	return instances
 }
 
 func parseClusterStatusResult(result *influxdb.QueryTableResult) []InstanceStatus {
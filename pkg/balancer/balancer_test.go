@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/breaker"
 	"github.com/bigblueswarm/bigblueswarm/v3/pkg/utils"
 	"github.com/bigblueswarm/test_utils/pkg/test"
 
@@ -109,6 +110,19 @@ func TestProcess(t *testing.T) {
 				assert.Equal(t, "http://localhost:8080", result)
 			},
 		},
+		{
+			Name: "Instances with an open circuit breaker should not be balanced",
+			Mock: func() {
+				breaker.Init(&config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: "1m"})
+				breaker.Breakers.Failure("http://localhost:8080")
+				breaker.Breakers.Failure("http://localhost:8081")
+				statusCode = http.StatusInternalServerError
+			},
+			Validator: func(t *testing.T, result interface{}, err error) {
+				assert.Equal(t, "no instance online to process a balancer request", err.Error())
+				breaker.Init(&config.CircuitBreakerConfig{})
+			},
+		},
 	}
 
 	balancer := &InfluxDBBalancer{
@@ -200,6 +214,21 @@ func TestClusterStatus(t *testing.T) {
 				assert.Equal(t, "Up", status.APIStatus)
 				assert.Equal(t, int64(0), status.Meetings)
 				assert.Equal(t, int64(0), status.Participants)
+				assert.Equal(t, string(breaker.Closed), status.CircuitBreaker)
+			},
+		},
+		{
+			Name: "An instance with an open circuit breaker should be down",
+			Mock: func() {
+				breaker.Init(&config.CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: "1m"})
+				breaker.Breakers.Failure("http://localhost/bigbluebutton")
+			},
+			Validator: func(t *testing.T, result interface{}, err error) {
+				assert.Nil(t, err)
+				status := result.([]InstanceStatus)[0]
+				assert.Equal(t, "Down", status.APIStatus)
+				assert.Equal(t, string(breaker.Open), status.CircuitBreaker)
+				breaker.Init(&config.CircuitBreakerConfig{})
 			},
 		},
 	}
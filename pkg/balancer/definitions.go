@@ -9,4 +9,6 @@ type InstanceStatus struct {
 	Meetings     int64   `json:"meetings"`
 	Participants int64   `json:"participants"`
 	APIStatus    string  `json:"api_status"`
+	// CircuitBreaker is the instance circuit breaker state
+	CircuitBreaker string `json:"circuit_breaker"`
 }
@@ -0,0 +1,154 @@
+// Package breaker manages the BigBlueButton instances circuit breakers
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+)
+
+// State is a circuit breaker state
+type State string
+
+const (
+	// Closed is the state of a circuit breaker letting the calls through
+	Closed State = "closed"
+	// Open is the state of a circuit breaker short-circuiting the calls
+	Open State = "open"
+	// HalfOpen is the state of an open circuit breaker letting a probe call through
+	HalfOpen State = "half-open"
+)
+
+// ErrOpen is returned when a call is short-circuited by an open circuit breaker
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breakers is the circuit breakers registry used for instance calls
+var Breakers = NewRegistry(&config.CircuitBreakerConfig{})
+
+type breaker struct {
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// Registry holds a circuit breaker per key. A circuit breaker opens after FailureThreshold consecutive failures and
+// lets a single probe call through once OpenTimeout is elapsed. The probe result closes or reopens the circuit breaker
+type Registry struct {
+	mutex            sync.Mutex
+	breakers         map[string]*breaker
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	Now              func() time.Time
+	// OnOpen is called when a closed circuit breaker opens. A reopening half-open circuit breaker does not call it
+	OnOpen func(key string)
+}
+
+// NewRegistry creates a circuit breakers registry from the configuration. A threshold lower or equal to 0 disables
+// the circuit breakers
+func NewRegistry(conf *config.CircuitBreakerConfig) *Registry {
+	openTimeout, err := time.ParseDuration(conf.OpenTimeout)
+	if err != nil {
+		openTimeout = 0
+	}
+
+	return &Registry{
+		breakers:         map[string]*breaker{},
+		FailureThreshold: conf.FailureThreshold,
+		OpenTimeout:      openTimeout,
+		Now:              time.Now,
+	}
+}
+
+// Init replaces the circuit breakers registry by a registry built from the configuration
+func Init(conf *config.CircuitBreakerConfig) {
+	Breakers = NewRegistry(conf)
+}
+
+func (r *Registry) state(b *breaker) State {
+	if b == nil || r.FailureThreshold <= 0 || b.failures < r.FailureThreshold {
+		return Closed
+	}
+
+	if r.Now().Sub(b.openedAt) >= r.OpenTimeout && !b.probing {
+		return HalfOpen
+	}
+
+	return Open
+}
+
+// State returns the key circuit breaker state
+func (r *Registry) State(key string) State {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.state(r.breakers[key])
+}
+
+// Allow check if a call is allowed. It returns an error wrapping ErrOpen if the call is short-circuited.
+// A half-open circuit breaker allows the call and waits for its result
+func (r *Registry) Allow(key string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	b := r.breakers[key]
+	switch r.state(b) {
+	case Open:
+		return fmt.Errorf("%s: %w", key, ErrOpen)
+	case HalfOpen:
+		b.probing = true
+	}
+
+	return nil
+}
+
+// Success records a successful call, closing the circuit breaker
+func (r *Registry) Success(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.breakers, key)
+}
+
+// Release records a call whose result does not tell the key health, like a canceled call. A half-open circuit breaker
+// lets another probe call through
+func (r *Registry) Release(key string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if b, ok := r.breakers[key]; ok {
+		b.probing = false
+	}
+}
+
+// Failure records a failing call. The circuit breaker opens when the failure threshold is reached
+func (r *Registry) Failure(key string) {
+	r.mutex.Lock()
+	b, ok := r.breakers[key]
+	if !ok {
+		b = &breaker{}
+		r.breakers[key] = b
+	}
+
+	b.failures++
+	b.probing = false
+	if r.FailureThreshold > 0 && b.failures >= r.FailureThreshold {
+		b.openedAt = r.Now()
+	}
+
+	opened := r.FailureThreshold > 0 && b.failures == r.FailureThreshold
+	r.mutex.Unlock()
+	if opened && r.OnOpen != nil {
+		r.OnOpen(key)
+	}
+}
+
+// Available returns the keys whose circuit breaker is not open
+func (r *Registry) Available(keys []string) []string {
+	available := []string{}
+	for _, key := range keys {
+		if r.State(key) != Open {
+			available = append(available, key)
+		}
+	}
+
+	return available
+}
@@ -0,0 +1,79 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	now := time.Date(2022, time.March, 17, 15, 4, 5, 0, time.UTC)
+	registry := NewRegistry(&config.CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: "30s"})
+	registry.Now = func() time.Time { return now }
+	opened := []string{}
+	registry.OnOpen = func(key string) {
+		opened = append(opened, key)
+	}
+	key := "http://localhost/bigbluebutton"
+
+	t.Run("a circuit breaker should stay closed under the failure threshold", func(t *testing.T) {
+		registry.Failure(key)
+		assert.Equal(t, Closed, registry.State(key))
+		assert.Nil(t, registry.Allow(key))
+	})
+
+	t.Run("a success should reset the consecutive failures", func(t *testing.T) {
+		registry.Success(key)
+		registry.Failure(key)
+		assert.Equal(t, Closed, registry.State(key))
+	})
+
+	t.Run("a circuit breaker should open once the failure threshold is reached", func(t *testing.T) {
+		registry.Failure(key)
+		assert.Equal(t, Open, registry.State(key))
+		assert.True(t, errors.Is(registry.Allow(key), ErrOpen))
+		assert.Equal(t, []string{"http://other/bigbluebutton"}, registry.Available([]string{key, "http://other/bigbluebutton"}))
+		assert.Equal(t, []string{key}, opened)
+	})
+
+	t.Run("an open circuit breaker should let a single probe through once the timeout is elapsed", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+		assert.Equal(t, HalfOpen, registry.State(key))
+		assert.Nil(t, registry.Allow(key))
+		assert.True(t, errors.Is(registry.Allow(key), ErrOpen))
+	})
+
+	t.Run("a released probe should let another probe through", func(t *testing.T) {
+		registry.Release(key)
+		assert.Equal(t, HalfOpen, registry.State(key))
+		assert.Nil(t, registry.Allow(key))
+		assert.True(t, errors.Is(registry.Allow(key), ErrOpen))
+	})
+
+	t.Run("a failing probe should reopen the circuit breaker", func(t *testing.T) {
+		registry.Failure(key)
+		assert.Equal(t, Open, registry.State(key))
+		now = now.Add(29 * time.Second)
+		assert.Equal(t, Open, registry.State(key))
+		assert.Equal(t, []string{key}, opened)
+	})
+
+	t.Run("a successful probe should close the circuit breaker", func(t *testing.T) {
+		now = now.Add(time.Second)
+		assert.Nil(t, registry.Allow(key))
+		registry.Success(key)
+		assert.Equal(t, Closed, registry.State(key))
+	})
+
+	t.Run("a threshold of 0 should disable the circuit breakers", func(t *testing.T) {
+		disabled := NewRegistry(&config.CircuitBreakerConfig{})
+		for i := 0; i < 10; i++ {
+			disabled.Failure(key)
+		}
+
+		assert.Equal(t, Closed, disabled.State(key))
+	})
+}
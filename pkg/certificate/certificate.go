@@ -0,0 +1,168 @@
+// Package certificate provides the TLS certificates served by the bigblueswarm listeners
+package certificate
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// Provider provides the certificate served for a TLS handshake
+type Provider interface {
+	GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+}
+
+// ChallengeHandler is implemented by the providers answering HTTP challenges, like ACME http-01 providers. The plain
+// HTTP listener requests are handled by the returned handler
+type ChallengeHandler interface {
+	HTTPHandler(fallback http.Handler) http.Handler
+}
+
+// Factory creates a provider from the TLS configuration
+type Factory func(conf *config.TLSConfig) (Provider, error)
+
+var factories = map[string]Factory{
+	"file": func(conf *config.TLSConfig) (Provider, error) {
+		return NewFileProvider(conf)
+	},
+}
+
+// Register registers a provider factory. The provider is used when the TLS configuration provider matches the name
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// New creates the configured provider
+func New(conf *config.TLSConfig) (Provider, error) {
+	factory, ok := factories[conf.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown certificate provider %s", conf.Provider)
+	}
+
+	return factory(conf)
+}
+
+// TLSConfig returns the listeners TLS configuration using the provider certificates
+func TLSConfig(conf *config.TLSConfig, provider Provider) (*tls.Config, error) {
+	tlsConfig := &tls.Config{GetCertificate: provider.GetCertificate}
+	switch conf.MinVersion {
+	case "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min version %s", conf.MinVersion)
+	}
+
+	return tlsConfig, nil
+}
+
+// FileProvider serves a certificate loaded from PEM encoded files. The files are checked for modification at most
+// once per reload interval and the certificate is reloaded when they change, so renewed certificates are served
+// without restart
+type FileProvider struct {
+	CertFile       string
+	KeyFile        string
+	ReloadInterval time.Duration
+	mutex          sync.Mutex
+	certificate    *tls.Certificate
+	modTime        time.Time
+	checkedAt      time.Time
+}
+
+// NewFileProvider creates a FileProvider from the TLS configuration and loads its certificate
+func NewFileProvider(conf *config.TLSConfig) (*FileProvider, error) {
+	if conf.CertFile == "" || conf.KeyFile == "" {
+		return nil, fmt.Errorf("file certificate provider requires a certificate and a key file")
+	}
+
+	interval, err := time.ParseDuration(conf.ReloadInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate reload interval: %s", err)
+	}
+
+	provider := &FileProvider{
+		CertFile:       conf.CertFile,
+		KeyFile:        conf.KeyFile,
+		ReloadInterval: interval,
+	}
+
+	if err := provider.load(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// filesModTime returns the most recent modification time of the certificate and key files
+func (p *FileProvider) filesModTime() (time.Time, error) {
+	modTime := time.Time{}
+	for _, file := range []string{p.CertFile, p.KeyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modTime, fmt.Errorf("failed to stat %s: %s", file, err)
+		}
+
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	return modTime, nil
+}
+
+func (p *FileProvider) load(now time.Time) error {
+	modTime, err := p.filesModTime()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %s", err)
+	}
+
+	p.certificate = &certificate
+	p.modTime = modTime
+	p.checkedAt = now
+	return nil
+}
+
+// reload reloads the certificate if the files changed since the last load. The current certificate is kept if the
+// new one can not be loaded
+func (p *FileProvider) reload(now time.Time) {
+	if now.Sub(p.checkedAt) < p.ReloadInterval {
+		return
+	}
+
+	p.checkedAt = now
+	modTime, err := p.filesModTime()
+	if err == nil && !modTime.After(p.modTime) {
+		return
+	}
+
+	if err == nil {
+		err = p.load(now)
+	}
+
+	if err != nil {
+		log.WithField("context", "certificate").Errorln("failed to reload certificate, keeping the current one.", err)
+		return
+	}
+
+	log.WithField("context", "certificate").Info("certificate reloaded")
+}
+
+// GetCertificate returns the certificate, reloaded if the files changed
+func (p *FileProvider) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.reload(time.Now())
+	return p.certificate, nil
+}
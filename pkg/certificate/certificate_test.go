@@ -0,0 +1,162 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCertificate writes a self signed certificate for the common name and returns the certificate and key files
+func writeCertificate(t *testing.T, dir string, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, certificate *tls.Certificate) string {
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	assert.Nil(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestNew(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir(), "bigblueswarm.localhost")
+
+	t.Run("an unknown provider should return an error", func(t *testing.T) {
+		provider, err := New(&config.TLSConfig{Provider: "unknown"})
+		assert.Nil(t, provider)
+		assert.Equal(t, "unknown certificate provider unknown", err.Error())
+	})
+
+	t.Run("the file provider should load the certificate", func(t *testing.T) {
+		provider, err := New(&config.TLSConfig{Provider: "file", CertFile: certFile, KeyFile: keyFile, ReloadInterval: "1m"})
+		assert.Nil(t, err)
+		certificate, err := provider.GetCertificate(&tls.ClientHelloInfo{})
+		assert.Nil(t, err)
+		assert.Equal(t, "bigblueswarm.localhost", commonName(t, certificate))
+	})
+
+	t.Run("a registered provider should be created", func(t *testing.T) {
+		expected := &FileProvider{}
+		Register("test", func(conf *config.TLSConfig) (Provider, error) {
+			return expected, nil
+		})
+		defer delete(factories, "test")
+
+		provider, err := New(&config.TLSConfig{Provider: "test"})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, provider)
+	})
+}
+
+func TestTLSConfig(t *testing.T) {
+	provider := &FileProvider{}
+	tlsConfig, err := TLSConfig(&config.TLSConfig{MinVersion: "1.3"}, provider)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+	assert.NotNil(t, tlsConfig.GetCertificate)
+
+	_, err = TLSConfig(&config.TLSConfig{MinVersion: "1.0"}, provider)
+	assert.Equal(t, "unsupported TLS min version 1.0", err.Error())
+}
+
+func TestNewFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "bigblueswarm.localhost")
+
+	type test struct {
+		name     string
+		conf     *config.TLSConfig
+		expected string
+	}
+
+	tests := []test{
+		{
+			name:     "a missing key file should return an error",
+			conf:     &config.TLSConfig{CertFile: certFile, ReloadInterval: "1m"},
+			expected: "file certificate provider requires a certificate and a key file",
+		},
+		{
+			name:     "an invalid reload interval should return an error",
+			conf:     &config.TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: "invalid"},
+			expected: `failed to parse certificate reload interval: time: invalid duration "invalid"`,
+		},
+		{
+			name:     "a nonexistent file should return an error",
+			conf:     &config.TLSConfig{CertFile: filepath.Join(dir, "nonexistent.pem"), KeyFile: keyFile, ReloadInterval: "1m"},
+			expected: "failed to stat " + filepath.Join(dir, "nonexistent.pem"),
+		},
+		{
+			name:     "an invalid certificate should return an error",
+			conf:     &config.TLSConfig{CertFile: keyFile, KeyFile: keyFile, ReloadInterval: "1m"},
+			expected: "failed to load certificate",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider, err := NewFileProvider(test.conf)
+			assert.Nil(t, provider)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
+
+func TestFileProviderReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "first.localhost")
+	provider, err := NewFileProvider(&config.TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: "1m"})
+	assert.Nil(t, err)
+	loadedAt := provider.checkedAt
+
+	writeCertificate(t, dir, "second.localhost")
+	later := time.Now().Add(time.Hour)
+	assert.Nil(t, os.Chtimes(certFile, later, later))
+
+	t.Run("the certificate should not be reloaded before the reload interval", func(t *testing.T) {
+		provider.reload(loadedAt.Add(time.Second))
+		assert.Equal(t, "first.localhost", commonName(t, provider.certificate))
+	})
+
+	t.Run("the certificate should be reloaded once the files changed", func(t *testing.T) {
+		provider.reload(loadedAt.Add(2 * time.Minute))
+		assert.Equal(t, "second.localhost", commonName(t, provider.certificate))
+	})
+
+	t.Run("the current certificate should be kept if the new one is invalid", func(t *testing.T) {
+		assert.Nil(t, os.WriteFile(certFile, []byte("invalid"), 0600))
+		evenLater := later.Add(time.Hour)
+		assert.Nil(t, os.Chtimes(certFile, evenLater, evenLater))
+		provider.reload(loadedAt.Add(4 * time.Minute))
+		assert.Equal(t, "second.localhost", commonName(t, provider.certificate))
+	})
+}
@@ -12,8 +12,26 @@ const ConsulPrefix string = "consul:"
 
 // BigBlueSwarm configuration mapping
 type BigBlueSwarm struct {
-	Secret                 string `yaml:"secret" json:"secret"`
-	RecordingsPollInterval string `yaml:"recordingsPollInterval" json:"recordingsPollInterval"`
+	Secret                 string   `yaml:"secret" json:"secret"`
+	RecordingsPollInterval string   `yaml:"recordingsPollInterval" json:"recordingsPollInterval"`
+	MeetingsPollInterval   string   `yaml:"meetingsPollInterval" json:"meetingsPollInterval"`
+	TrustedProxies         []string `yaml:"trustedProxies,omitempty" json:"trustedProxies,omitempty"`
+	// InstanceTimeout is the deadline of each instance call when requesting all the instances
+	InstanceTimeout string `yaml:"instanceTimeout" json:"instanceTimeout"`
+	// RecordingsPollTimeout is the deadline of each instance call when polling the recordings
+	RecordingsPollTimeout string `yaml:"recordingsPollTimeout" json:"recordingsPollTimeout"`
+	// InstanceConcurrency is the maximum number of instances requested at once
+	InstanceConcurrency int `yaml:"instanceConcurrency" json:"instanceConcurrency"`
+	// ReportSkippedInstances adds the instances that failed to respond to the getMeetings and getRecordings responses headers
+	ReportSkippedInstances bool `yaml:"reportSkippedInstances,omitempty" json:"reportSkippedInstances,omitempty"`
+	// MeetingsHistoryRetention is the duration the meetings history records are kept. Records are kept forever if 0
+	MeetingsHistoryRetention string `yaml:"meetingsHistoryRetention" json:"meetingsHistoryRetention"`
+	// ShutdownTimeout is the duration the in-flight requests have to complete once a shutdown is requested
+	ShutdownTimeout string `yaml:"shutdownTimeout" json:"shutdownTimeout"`
+	// ShutdownDelay is the duration the readiness probe fails before the listeners are shut down
+	ShutdownDelay string `yaml:"shutdownDelay" json:"shutdownDelay"`
+	// ReadinessTimeout is the deadline of the metrics backend check of the readiness probe
+	ReadinessTimeout string `yaml:"readinessTimeout" json:"readinessTimeout"`
 }
 
 // RDB represents redis database configuration mapping
@@ -43,6 +61,21 @@ type IDB struct {
 // AdminConfig represents the admin configuration
 type AdminConfig struct {
 	APIKey string `yaml:"apiKey" json:"apiKey"`
+	// Audit is the administrative changes audit log configuration
+	Audit AuditConfig `yaml:"audit,omitempty" json:"audit,omitempty"`
+	// Address is the dedicated admin API listener address, like `127.0.0.1:8091`. The admin API is served by the main
+	// listener if empty
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+	// AllowedIPs is the list of CIDR or IP addresses allowed to consume the admin API. All addresses are allowed if empty
+	AllowedIPs []string `yaml:"allowedIPs,omitempty" json:"allowedIPs,omitempty"`
+}
+
+// AuditConfig represents the administrative changes audit log configuration
+type AuditConfig struct {
+	// MaxRecords is the number of records kept in the audit store. All records are kept if lower or equal to 0
+	MaxRecords int64 `yaml:"maxRecords,omitempty" json:"maxRecords,omitempty"`
+	// File is the path of the file the audit records are appended to, one JSON record per line
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
 }
 
 // BalancerConfig represents the balancer configuration
@@ -73,20 +106,307 @@ func (bbs *BigBlueSwarm) SetDefaultValues() {
 	if bbs.RecordingsPollInterval == "" {
 		bbs.RecordingsPollInterval = "15m"
 	}
+
+	if bbs.MeetingsPollInterval == "" {
+		bbs.MeetingsPollInterval = "1m"
+	}
+
+	if bbs.InstanceTimeout == "" {
+		bbs.InstanceTimeout = "5s"
+	}
+
+	if bbs.RecordingsPollTimeout == "" {
+		bbs.RecordingsPollTimeout = "1m"
+	}
+
+	if bbs.InstanceConcurrency == 0 {
+		bbs.InstanceConcurrency = 10
+	}
+
+	if bbs.MeetingsHistoryRetention == "" {
+		bbs.MeetingsHistoryRetention = "2160h"
+	}
+
+	if bbs.ShutdownTimeout == "" {
+		bbs.ShutdownTimeout = "30s"
+	}
+
+	if bbs.ShutdownDelay == "" {
+		bbs.ShutdownDelay = "5s"
+	}
+
+	if bbs.ReadinessTimeout == "" {
+		bbs.ReadinessTimeout = "1s"
+	}
+}
+
+// HTTPClientConfig represents the http client configuration used to call the BigBlueButton instances
+type HTTPClientConfig struct {
+	// ConnectTimeout is the connection and TLS handshake timeout
+	ConnectTimeout string `yaml:"connectTimeout" json:"connectTimeout"`
+	// ReadTimeout is the time to wait for the response headers once the request is sent
+	ReadTimeout string `yaml:"readTimeout" json:"readTimeout"`
+	// Retries is the number of retries of a failing read-only request
+	Retries int `yaml:"retries" json:"retries"`
+	// RetryBackoff is the delay before the first retry. The delay is doubled on each retry
+	RetryBackoff string `yaml:"retryBackoff" json:"retryBackoff"`
+	// CAFile is the PEM encoded CA bundle used to verify the instances certificates. System CAs are used if empty
+	CAFile string `yaml:"caFile,omitempty" json:"caFile,omitempty"`
+	// CertFile and KeyFile are the PEM encoded client certificate and key used to authenticate to the instances
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+}
+
+// SetDefaultValues initialize HTTPClientConfig default values
+func (hc *HTTPClientConfig) SetDefaultValues() {
+	if hc.ConnectTimeout == "" {
+		hc.ConnectTimeout = "5s"
+	}
+
+	if hc.ReadTimeout == "" {
+		hc.ReadTimeout = "30s"
+	}
+
+	if hc.RetryBackoff == "" {
+		hc.RetryBackoff = "200ms"
+	}
+}
+
+// CircuitBreakerConfig represents the instances circuit breakers configuration
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failing calls opening an instance circuit breaker
+	FailureThreshold int `yaml:"failureThreshold" json:"failureThreshold"`
+	// OpenTimeout is the duration after which an open circuit breaker lets a probe call through
+	OpenTimeout string `yaml:"openTimeout" json:"openTimeout"`
+}
+
+// SetDefaultValues initialize CircuitBreakerConfig default values
+func (cb *CircuitBreakerConfig) SetDefaultValues() {
+	if cb.FailureThreshold == 0 {
+		cb.FailureThreshold = 5
+	}
+
+	if cb.OpenTimeout == "" {
+		cb.OpenTimeout = "30s"
+	}
 }
 
 // Port represents the BigBlueSwarm port configuration
 type Port int
 
+// TLSConfig represents the listeners TLS configuration. HTTP/2 is served over TLS unless disabled
+type TLSConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Provider is the certificates provider: `file` or a provider registered in the certificate package. Default is `file`
+	Provider string `yaml:"provider" json:"provider"`
+	// CertFile and KeyFile are the PEM encoded certificate and key used by the file provider
+	CertFile string `yaml:"certFile,omitempty" json:"certFile,omitempty"`
+	KeyFile  string `yaml:"keyFile,omitempty" json:"keyFile,omitempty"`
+	// ReloadInterval is the delay between two checks of the certificate files modification by the file provider
+	ReloadInterval string `yaml:"reloadInterval" json:"reloadInterval"`
+	// MinVersion is the minimum TLS version: `1.2` or `1.3`
+	MinVersion string `yaml:"minVersion" json:"minVersion"`
+	// DisableHTTP2 serves HTTP/1.1 only
+	DisableHTTP2 bool `yaml:"disableHTTP2,omitempty" json:"disableHTTP2,omitempty"`
+	// RedirectPort is the plain HTTP port redirecting the requests to HTTPS. Requests are not redirected if empty
+	RedirectPort Port `yaml:"redirectPort,omitempty" json:"redirectPort,omitempty"`
+}
+
+// SetDefaultValues initialize TLSConfig default values
+func (tc *TLSConfig) SetDefaultValues() {
+	if tc.Provider == "" {
+		tc.Provider = "file"
+	}
+
+	if tc.ReloadInterval == "" {
+		tc.ReloadInterval = "1m"
+	}
+
+	if tc.MinVersion == "" {
+		tc.MinVersion = "1.2"
+	}
+}
+
+// TracingConfig represents the OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Exporter is the spans exporter: `otlp` or `stdout`
+	Exporter string `yaml:"exporter" json:"exporter"`
+	// Endpoint is the OTLP HTTP collector endpoint
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	// Insecure disables the TLS connection to the OTLP collector
+	Insecure bool `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+	// SampleRatio is the ratio of sampled traces, between 0 and 1
+	SampleRatio float64 `yaml:"sampleRatio" json:"sampleRatio"`
+}
+
+// SetDefaultValues initialize TracingConfig default values
+func (tc *TracingConfig) SetDefaultValues() {
+	if tc.Exporter == "" {
+		tc.Exporter = "otlp"
+	}
+
+	if tc.Endpoint == "" {
+		tc.Endpoint = "localhost:4318"
+	}
+
+	if tc.SampleRatio == 0 {
+		tc.SampleRatio = 1
+	}
+}
+
+// EventsConfig represents the events publication configuration
+type EventsConfig struct {
+	// Sink is the events sink: `redis`, `nats` or `webhook`. Events are not published if empty
+	Sink string `yaml:"sink,omitempty" json:"sink,omitempty"`
+	// BufferSize is the number of events waiting for publication. Events are dropped when the buffer is full
+	BufferSize int `yaml:"bufferSize" json:"bufferSize"`
+	// Retries is the number of retries of a failing publication
+	Retries int `yaml:"retries" json:"retries"`
+	// RetryBackoff is the delay before the first retry. The delay is doubled on each retry
+	RetryBackoff string `yaml:"retryBackoff" json:"retryBackoff"`
+	// Redis is the redis streams sink configuration. The events are added to the stream of the BigBlueSwarm redis
+	Redis RedisStreamConfig `yaml:"redis,omitempty" json:"redis,omitempty"`
+	// NATS is the NATS sink configuration
+	NATS NATSConfig `yaml:"nats,omitempty" json:"nats,omitempty"`
+	// Webhook is the HTTP webhook sink configuration
+	Webhook WebhookConfig `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	// TenantWebhooks is the tenants webhooks delivery configuration
+	TenantWebhooks TenantWebhooksConfig `yaml:"tenantWebhooks" json:"tenantWebhooks"`
+}
+
+// TenantWebhooksConfig represents the tenants webhooks delivery configuration. Deliveries are stored in redis and
+// retried with an exponential backoff until they reach the maximum number of attempts
+type TenantWebhooksConfig struct {
+	// Interval is the delay between two polls of the due deliveries
+	Interval string `yaml:"interval" json:"interval"`
+	// Timeout is the delivery request timeout
+	Timeout string `yaml:"timeout" json:"timeout"`
+	// MaxAttempts is the number of attempts after which a failing delivery is moved to the dead letter queue
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
+	// RetryBackoff is the delay before the first retry. The delay is doubled on each retry up to MaxBackoff
+	RetryBackoff string `yaml:"retryBackoff" json:"retryBackoff"`
+	MaxBackoff   string `yaml:"maxBackoff" json:"maxBackoff"`
+	// BatchSize is the maximum number of deliveries sent on each poll
+	BatchSize int64 `yaml:"batchSize" json:"batchSize"`
+	// Concurrency is the maximum number of deliveries sent at once
+	Concurrency int `yaml:"concurrency" json:"concurrency"`
+	// Retention is the duration the delivered and dead deliveries are kept
+	Retention string `yaml:"retention" json:"retention"`
+}
+
+// RedisStreamConfig represents the redis streams events sink configuration
+type RedisStreamConfig struct {
+	Stream string `yaml:"stream" json:"stream"`
+	// MaxLen is the approximate maximum number of events kept in the stream. All events are kept if lower or equal to 0
+	MaxLen int64 `yaml:"maxLen" json:"maxLen"`
+}
+
+// NATSConfig represents the NATS events sink configuration. Events are published on the `<subject>.<event type>` subject
+type NATSConfig struct {
+	URL     string `yaml:"url" json:"url"`
+	Subject string `yaml:"subject" json:"subject"`
+}
+
+// WebhookConfig represents the HTTP webhook events sink configuration. Events are signed with the secret
+type WebhookConfig struct {
+	URL     string `yaml:"url" json:"url"`
+	Secret  string `yaml:"secret" json:"secret"`
+	Timeout string `yaml:"timeout" json:"timeout"`
+}
+
+// SetDefaultValues initialize EventsConfig default values
+func (ec *EventsConfig) SetDefaultValues() {
+	if ec.BufferSize == 0 {
+		ec.BufferSize = 1000
+	}
+
+	if ec.Retries == 0 {
+		ec.Retries = 3
+	}
+
+	if ec.RetryBackoff == "" {
+		ec.RetryBackoff = "1s"
+	}
+
+	if ec.Redis.Stream == "" {
+		ec.Redis.Stream = "bigblueswarm:events"
+	}
+
+	if ec.Redis.MaxLen == 0 {
+		ec.Redis.MaxLen = 100000
+	}
+
+	if ec.NATS.Subject == "" {
+		ec.NATS.Subject = "bigblueswarm.events"
+	}
+
+	if ec.Webhook.Timeout == "" {
+		ec.Webhook.Timeout = "5s"
+	}
+
+	ec.TenantWebhooks.SetDefaultValues()
+}
+
+// SetDefaultValues initialize TenantWebhooksConfig default values
+func (tw *TenantWebhooksConfig) SetDefaultValues() {
+	if tw.Interval == "" {
+		tw.Interval = "5s"
+	}
+
+	if tw.Timeout == "" {
+		tw.Timeout = "5s"
+	}
+
+	if tw.MaxAttempts == 0 {
+		tw.MaxAttempts = 8
+	}
+
+	if tw.RetryBackoff == "" {
+		tw.RetryBackoff = "30s"
+	}
+
+	if tw.MaxBackoff == "" {
+		tw.MaxBackoff = "1h"
+	}
+
+	if tw.BatchSize == 0 {
+		tw.BatchSize = 100
+	}
+
+	if tw.Concurrency == 0 {
+		tw.Concurrency = 10
+	}
+
+	if tw.Retention == "" {
+		tw.Retention = "168h"
+	}
+}
+
+// MetricsConfig represents the prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Port is the dedicated metrics listener port. Metrics are served by the main listener if empty
+	Port Port `yaml:"port,omitempty" json:"port,omitempty"`
+	// APIKey protects the metrics endpoint. The key is expected in the Authorization header
+	APIKey string `yaml:"apiKey,omitempty" json:"apiKey,omitempty"`
+}
+
 // Config represents main configuration mapping
 type Config struct {
-	BigBlueSwarm BigBlueSwarm   `yaml:"bigblueswarm" json:"bigblueswarm"`
-	Admin        AdminConfig    `yaml:"admin" json:"admin"`
-	Balancer     BalancerConfig `yaml:"balancer" json:"balancer"`
-	Port         Port           `yaml:"port" json:"port"`
-	RDB          RDB            `yaml:"redis" json:"redis"`
-	IDB          IDB            `yaml:"influxdb" json:"influxdb"`
-	PG           PG             `yaml:"postgres" json:"postgres"`
+	BigBlueSwarm   BigBlueSwarm         `yaml:"bigblueswarm" json:"bigblueswarm"`
+	Admin          AdminConfig          `yaml:"admin" json:"admin"`
+	Balancer       BalancerConfig       `yaml:"balancer" json:"balancer"`
+	HTTPClient     HTTPClientConfig     `yaml:"httpClient" json:"httpClient"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker" json:"circuitBreaker"`
+	Metrics        MetricsConfig        `yaml:"metrics" json:"metrics"`
+	Tracing        TracingConfig        `yaml:"tracing" json:"tracing"`
+	Events         EventsConfig         `yaml:"events" json:"events"`
+	Port           Port                 `yaml:"port" json:"port"`
+	TLS            TLSConfig            `yaml:"tls" json:"tls"`
+	RDB            RDB                  `yaml:"redis" json:"redis"`
+	IDB            IDB                  `yaml:"influxdb" json:"influxdb"`
+	PG             PG                   `yaml:"postgres" json:"postgres"`
 }
 
 const defaultConfigFileName = "bigblueswarm.yaml"
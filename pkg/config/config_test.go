@@ -176,10 +176,55 @@ func TestConsulConfigLoad(t *testing.T) {
 						AggregationInterval: "10s",
 					},
 					BigBlueSwarm: BigBlueSwarm{
-						Secret:                 "0ol5t44UR21rrP0xL5ou7IBFumWF3GENebgW1RyTfbU",
-						RecordingsPollInterval: "1m",
+						Secret:                   "0ol5t44UR21rrP0xL5ou7IBFumWF3GENebgW1RyTfbU",
+						RecordingsPollInterval:   "1m",
+						MeetingsPollInterval:     "1m",
+						InstanceTimeout:          "5s",
+						RecordingsPollTimeout:    "1m",
+						InstanceConcurrency:      10,
+						MeetingsHistoryRetention: "2160h",
+						ShutdownTimeout:          "30s",
+						ShutdownDelay:            "5s",
+						ReadinessTimeout:         "1s",
+					},
+					HTTPClient: HTTPClientConfig{
+						ConnectTimeout: "5s",
+						ReadTimeout:    "30s",
+						RetryBackoff:   "200ms",
+					},
+					CircuitBreaker: CircuitBreakerConfig{
+						FailureThreshold: 5,
+						OpenTimeout:      "30s",
+					},
+					Tracing: TracingConfig{
+						Exporter:    "otlp",
+						Endpoint:    "localhost:4318",
+						SampleRatio: 1,
+					},
+					Events: EventsConfig{
+						BufferSize:   1000,
+						Retries:      3,
+						RetryBackoff: "1s",
+						Redis:        RedisStreamConfig{Stream: "bigblueswarm:events", MaxLen: 100000},
+						NATS:         NATSConfig{Subject: "bigblueswarm.events"},
+						Webhook:      WebhookConfig{Timeout: "5s"},
+						TenantWebhooks: TenantWebhooksConfig{
+							Interval:     "5s",
+							Timeout:      "5s",
+							MaxAttempts:  8,
+							RetryBackoff: "30s",
+							MaxBackoff:   "1h",
+							BatchSize:    100,
+							Concurrency:  10,
+							Retention:    "168h",
+						},
 					},
 					Port: 8090,
+					TLS: TLSConfig{
+						Provider:       "file",
+						ReloadInterval: "1m",
+						MinVersion:     "1.2",
+					},
 					IDB: IDB{
 						Address:      "http://localhost:8086",
 						Token:        "Zq9wLsmhnW5UtOiPJApUv1cTVJfwXsTgl_pCkiTikQ3g2YGPtS5HqsXef-Wf5pUU3wjY3nVWTYRI-Wc8LjbDfg==",
@@ -259,6 +304,44 @@ func TestFormalizeConfigPath(t *testing.T) {
 	}
 }
 
+func TestHTTPClientConfigSetDefaultValues(t *testing.T) {
+	conf := &HTTPClientConfig{ReadTimeout: "10s"}
+	conf.SetDefaultValues()
+	assert.Equal(t, &HTTPClientConfig{ConnectTimeout: "5s", ReadTimeout: "10s", RetryBackoff: "200ms"}, conf)
+}
+
+func TestCircuitBreakerConfigSetDefaultValues(t *testing.T) {
+	conf := &CircuitBreakerConfig{}
+	conf.SetDefaultValues()
+	assert.Equal(t, &CircuitBreakerConfig{FailureThreshold: 5, OpenTimeout: "30s"}, conf)
+}
+
+func TestTracingConfigSetDefaultValues(t *testing.T) {
+	conf := &TracingConfig{Exporter: "stdout"}
+	conf.SetDefaultValues()
+	assert.Equal(t, &TracingConfig{Exporter: "stdout", Endpoint: "localhost:4318", SampleRatio: 1}, conf)
+}
+
+func TestEventsConfigSetDefaultValues(t *testing.T) {
+	conf := &EventsConfig{Sink: "redis", Retries: 1}
+	conf.SetDefaultValues()
+	assert.Equal(t, "redis", conf.Sink)
+	assert.Equal(t, 1, conf.Retries)
+	assert.Equal(t, 1000, conf.BufferSize)
+	assert.Equal(t, "bigblueswarm:events", conf.Redis.Stream)
+	assert.Equal(t, "bigblueswarm.events", conf.NATS.Subject)
+	assert.Equal(t, "5s", conf.Webhook.Timeout)
+	assert.Equal(t, 8, conf.TenantWebhooks.MaxAttempts)
+	assert.Equal(t, "30s", conf.TenantWebhooks.RetryBackoff)
+	assert.Equal(t, "168h", conf.TenantWebhooks.Retention)
+}
+
+func TestTLSConfigSetDefaultValues(t *testing.T) {
+	conf := &TLSConfig{Enabled: true, MinVersion: "1.3"}
+	conf.SetDefaultValues()
+	assert.Equal(t, &TLSConfig{Enabled: true, Provider: "file", ReloadInterval: "1m", MinVersion: "1.3"}, conf)
+}
+
 func TestBalancerConfigSetDefaultValues(t *testing.T) {
 	config := &BalancerConfig{}
 	tests := []test.Test{
@@ -79,6 +79,11 @@ func LoadConfigFromConsul(path string) (*Config, error) {
 
 	conf.Balancer.SetDefaultValues()
 	conf.BigBlueSwarm.SetDefaultValues()
+	conf.HTTPClient.SetDefaultValues()
+	conf.CircuitBreaker.SetDefaultValues()
+	conf.Tracing.SetDefaultValues()
+	conf.Events.SetDefaultValues()
+	conf.TLS.SetDefaultValues()
 
 	return conf, nil
 }
@@ -0,0 +1,208 @@
+// Package events publishes the bigblueswarm events to an external sink
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MeetingCreated is the event type of a meeting created on an instance
+	MeetingCreated = "meeting.created"
+	// MeetingEnded is the event type of a meeting ended by an end call, by the tenant policy or found no longer running
+	MeetingEnded = "meeting.ended"
+	// InstanceOffline is the event type of an instance whose circuit breaker opened
+	InstanceOffline = "instance.offline"
+	// PoolLimitReached is the event type of a request rejected because the tenant reached its meetings or users pool
+	PoolLimitReached = "pool.limit_reached"
+	// RecordingDiscovered is the event type of a recording found by the recordings poller
+	RecordingDiscovered = "recording.discovered"
+)
+
+// Event represents a bigblueswarm event. Tenant is empty for the events not related to a tenant
+type Event struct {
+	ID     string                 `json:"id"`
+	Type   string                 `json:"type"`
+	Time   time.Time              `json:"time"`
+	Tenant string                 `json:"tenant,omitempty"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+// New creates a new event
+func New(eventType string, tenant string, data map[string]interface{}) *Event {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	return &Event{
+		ID:     uuid.New().String(),
+		Type:   eventType,
+		Time:   time.Now().UTC(),
+		Tenant: tenant,
+		Data:   data,
+	}
+}
+
+// Sink publishes the events out of bigblueswarm
+type Sink interface {
+	Publish(ctx context.Context, event *Event) error
+	Close() error
+}
+
+// Publisher publishes the events asynchronously to a sink so the publication never delays the requests. Failing
+// publications are retried with an exponential backoff and events are dropped when the buffer is full
+type Publisher struct {
+	sink    Sink
+	queue   chan *Event
+	retries int
+	backoff time.Duration
+	done    chan struct{}
+	mutex   sync.RWMutex
+	closed  bool
+}
+
+// NewPublisher creates a publisher and starts publishing the events to the sink
+func NewPublisher(sink Sink, bufferSize int, retries int, backoff time.Duration) *Publisher {
+	p := &Publisher{
+		sink:    sink,
+		queue:   make(chan *Event, bufferSize),
+		retries: retries,
+		backoff: backoff,
+		done:    make(chan struct{}),
+	}
+
+	go p.run()
+	return p
+}
+
+func (p *Publisher) run() {
+	defer close(p.done)
+	for event := range p.queue {
+		p.publish(event)
+	}
+}
+
+func (p *Publisher) publish(event *Event) {
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		err := p.sink.Publish(context.Background(), event)
+		if err == nil {
+			return
+		}
+
+		if attempt >= p.retries {
+			log.WithFields(log.Fields{
+				"event_id":   event.ID,
+				"event_type": event.Type,
+			}).Errorln("failed to publish event.", err)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Publish queues the event for publication. It returns false if the event is dropped because the buffer is full or
+// the publisher is closed
+func (p *Publisher) Publish(event *Event) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.queue <- event:
+		return true
+	default:
+		log.WithFields(log.Fields{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+		}).Warn("events buffer is full, event dropped.")
+		return false
+	}
+}
+
+// Close stops accepting events, waits for the queued events publication until the context is done then closes the sink
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mutex.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+
+	p.mutex.Unlock()
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to publish the queued events: %s", ctx.Err())
+	}
+
+	return p.sink.Close()
+}
+
+// DefaultPublisher is the global events publisher. Events are not published if nil
+var DefaultPublisher *Publisher
+
+// NewSink creates the configured sink. The redis client is used by the redis streams sink
+func NewSink(conf *config.EventsConfig, rdb *redis.Client) (Sink, error) {
+	switch conf.Sink {
+	case "redis":
+		return NewRedisStreamSink(rdb, &conf.Redis), nil
+	case "nats":
+		return NewNATSSink(&conf.NATS)
+	case "webhook":
+		return NewWebhookSink(&conf.Webhook)
+	default:
+		return nil, fmt.Errorf("unknown events sink %s", conf.Sink)
+	}
+}
+
+// Init configures the global events publisher. It returns the function publishing the queued events and closing the
+// sink. Events are not published if no sink is configured
+func Init(conf *config.EventsConfig, rdb *redis.Client) (func(context.Context) error, error) {
+	if conf.Sink == "" {
+		DefaultPublisher = nil
+		return func(context.Context) error { return nil }, nil
+	}
+
+	backoff, err := time.ParseDuration(conf.RetryBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse events retry backoff: %s", err)
+	}
+
+	sink, err := NewSink(conf, rdb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events sink: %s", err)
+	}
+
+	DefaultPublisher = NewPublisher(sink, conf.BufferSize, conf.Retries, backoff)
+	return DefaultPublisher.Close, nil
+}
+
+// OnPublish is called with every published event, whether a sink is configured or not. It should not block
+var OnPublish func(event *Event)
+
+// Publish publishes a new event using the global events publisher
+func Publish(eventType string, tenant string, data map[string]interface{}) {
+	if DefaultPublisher == nil && OnPublish == nil {
+		return
+	}
+
+	event := New(eventType, tenant, data)
+	if DefaultPublisher != nil {
+		DefaultPublisher.Publish(event)
+	}
+
+	if OnPublish != nil {
+		OnPublish(event)
+	}
+}
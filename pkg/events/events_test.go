@@ -0,0 +1,133 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type sinkMock struct {
+	mutex    sync.Mutex
+	failures int
+	attempts int
+	events   []*Event
+	closed   bool
+	block    chan struct{}
+}
+
+func (s *sinkMock) Publish(ctx context.Context, event *Event) error {
+	if s.block != nil {
+		<-s.block
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("sink error")
+	}
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *sinkMock) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestPublisher(t *testing.T) {
+	t.Run("a failing publication should be retried", func(t *testing.T) {
+		sink := &sinkMock{failures: 2}
+		publisher := NewPublisher(sink, 10, 2, time.Millisecond)
+		assert.True(t, publisher.Publish(New(MeetingCreated, "localhost", nil)))
+		assert.Nil(t, publisher.Close(context.Background()))
+		assert.Equal(t, 3, sink.attempts)
+		assert.Equal(t, 1, len(sink.events))
+		assert.True(t, sink.closed)
+	})
+
+	t.Run("an event should be dropped once the retries are exhausted", func(t *testing.T) {
+		sink := &sinkMock{failures: 2}
+		publisher := NewPublisher(sink, 10, 1, time.Millisecond)
+		publisher.Publish(New(MeetingCreated, "localhost", nil))
+		publisher.Close(context.Background())
+		assert.Equal(t, 2, sink.attempts)
+		assert.Empty(t, sink.events)
+	})
+
+	t.Run("an event should be dropped when the buffer is full", func(t *testing.T) {
+		sink := &sinkMock{block: make(chan struct{})}
+		publisher := NewPublisher(sink, 1, 0, 0)
+		publisher.Publish(New(MeetingCreated, "localhost", nil))
+		assert.Eventually(t, func() bool { return len(publisher.queue) == 0 }, time.Second, time.Millisecond)
+		assert.True(t, publisher.Publish(New(MeetingCreated, "localhost", nil)))
+		assert.False(t, publisher.Publish(New(MeetingCreated, "localhost", nil)))
+		close(sink.block)
+		publisher.Close(context.Background())
+		assert.Equal(t, 2, len(sink.events))
+	})
+
+	t.Run("a closed publisher should not accept events", func(t *testing.T) {
+		publisher := NewPublisher(&sinkMock{}, 1, 0, 0)
+		publisher.Close(context.Background())
+		assert.False(t, publisher.Publish(New(MeetingCreated, "localhost", nil)))
+	})
+
+	t.Run("close should return an error if the events are not published before the context is done", func(t *testing.T) {
+		sink := &sinkMock{block: make(chan struct{})}
+		publisher := NewPublisher(sink, 1, 0, 0)
+		publisher.Publish(New(MeetingCreated, "localhost", nil))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.NotNil(t, publisher.Close(ctx))
+		close(sink.block)
+	})
+}
+
+func TestInit(t *testing.T) {
+	t.Run("no sink should disable the events publication", func(t *testing.T) {
+		shutdown, err := Init(&config.EventsConfig{}, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, DefaultPublisher)
+		assert.Nil(t, shutdown(context.Background()))
+		Publish(MeetingCreated, "localhost", nil)
+	})
+
+	t.Run("an unknown sink should return an error", func(t *testing.T) {
+		_, err := Init(&config.EventsConfig{Sink: "kafka", RetryBackoff: "1s"}, nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("a configured sink should initialize the global publisher", func(t *testing.T) {
+		shutdown, err := Init(&config.EventsConfig{
+			Sink:         "webhook",
+			BufferSize:   10,
+			RetryBackoff: "1s",
+			Webhook:      config.WebhookConfig{URL: "http://localhost/events", Secret: "secret", Timeout: "1s"},
+		}, nil)
+		assert.Nil(t, err)
+		assert.NotNil(t, DefaultPublisher)
+		assert.Nil(t, shutdown(context.Background()))
+		DefaultPublisher = nil
+	})
+}
+
+func TestPublish(t *testing.T) {
+	published := []*Event{}
+	OnPublish = func(event *Event) {
+		published = append(published, event)
+	}
+
+	defer func() { OnPublish = nil }()
+	Publish(MeetingCreated, "localhost", map[string]interface{}{"meeting_id": "meeting"})
+	assert.Equal(t, 1, len(published))
+	assert.Equal(t, MeetingCreated, published[0].Type)
+	assert.Equal(t, "localhost", published[0].Tenant)
+	assert.Equal(t, "meeting", published[0].Data["meeting_id"])
+}
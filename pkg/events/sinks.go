@@ -0,0 +1,199 @@
+// Package events publishes the bigblueswarm events to an external sink
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// EventIDHeader is the webhook request header carrying the event identifier
+	EventIDHeader = "X-BigBlueSwarm-Event-ID"
+	// EventTypeHeader is the webhook request header carrying the event type
+	EventTypeHeader = "X-BigBlueSwarm-Event"
+	// TimestampHeader is the webhook request header carrying the signature unix timestamp
+	TimestampHeader = "X-BigBlueSwarm-Timestamp"
+	// SignatureHeader is the webhook request header carrying the request signature
+	SignatureHeader = "X-BigBlueSwarm-Signature"
+)
+
+// RedisStreamSink adds the events to a redis stream
+type RedisStreamSink struct {
+	RDB    *redis.Client
+	Stream string
+	MaxLen int64
+}
+
+// NewRedisStreamSink creates a redis streams sink
+func NewRedisStreamSink(rdb *redis.Client, conf *config.RedisStreamConfig) Sink {
+	return &RedisStreamSink{
+		RDB:    rdb,
+		Stream: conf.Stream,
+		MaxLen: conf.MaxLen,
+	}
+}
+
+// Publish adds the event to the stream. The stream entry contains the event type and the JSON encoded event
+func (s *RedisStreamSink) Publish(ctx context.Context, event *Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: s.Stream,
+		Values: map[string]interface{}{
+			"type":  event.Type,
+			"event": string(value),
+		},
+	}
+
+	if s.MaxLen > 0 {
+		args.MaxLen = s.MaxLen
+		args.Approx = true
+	}
+
+	return s.RDB.XAdd(ctx, args).Err()
+}
+
+// Close does nothing as the redis client is shared
+func (s *RedisStreamSink) Close() error {
+	return nil
+}
+
+// NATSSink publishes the events on a NATS subject per event type
+type NATSSink struct {
+	Conn    *nats.Conn
+	Subject string
+}
+
+// NewNATSSink connects to the NATS server and creates a NATS sink
+func NewNATSSink(conf *config.NATSConfig) (Sink, error) {
+	conn, err := nats.Connect(conf.URL, nats.Name("bigblueswarm"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats server: %s", err)
+	}
+
+	return &NATSSink{
+		Conn:    conn,
+		Subject: conf.Subject,
+	}, nil
+}
+
+// Publish publishes the JSON encoded event on the `<subject>.<event type>` subject
+func (s *NATSSink) Publish(ctx context.Context, event *Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.Conn.Publish(fmt.Sprintf("%s.%s", s.Subject, event.Type), value)
+}
+
+// Close flushes the published events and closes the connection
+func (s *NATSSink) Close() error {
+	err := s.Conn.Flush()
+	s.Conn.Close()
+	return err
+}
+
+// Sign returns the HMAC-SHA256 signature of the timestamp and the body, formatted as `sha256=<hex signature>`.
+// The signed payload is `<timestamp>.<body>` so a captured request can not be replayed with another timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSink posts the JSON encoded events to an HTTP endpoint. Requests are signed with the webhook secret
+type WebhookSink struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewWebhookSink creates an HTTP webhook sink
+func NewWebhookSink(conf *config.WebhookConfig) (Sink, error) {
+	if conf.URL == "" {
+		return nil, fmt.Errorf("webhook url is empty")
+	}
+
+	if conf.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is empty")
+	}
+
+	timeout, err := time.ParseDuration(conf.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook timeout: %s", err)
+	}
+
+	return &WebhookSink{
+		URL:    conf.URL,
+		Secret: conf.Secret,
+		Client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// NewWebhookRequest creates the signed webhook request posting the event
+func NewWebhookRequest(ctx context.Context, url string, secret string, event *Event) (*http.Request, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, event.ID)
+	request.Header.Set(EventTypeHeader, event.Type)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	if secret != "" {
+		request.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	}
+
+	return request, nil
+}
+
+// Publish posts the event. Any response status other than 2xx is an error
+func (s *WebhookSink) Publish(ctx context.Context, event *Event) error {
+	request, err := NewWebhookRequest(ctx, s.URL, s.Secret, event)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Close closes the idle connections
+func (s *WebhookSink) Close() error {
+	s.Client.CloseIdleConnections()
+	return nil
+}
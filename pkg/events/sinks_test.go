@@ -0,0 +1,140 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStreamSink(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	sink := NewRedisStreamSink(rdb, &config.RedisStreamConfig{Stream: "events", MaxLen: 10})
+	event := New(MeetingCreated, "localhost", map[string]interface{}{"meeting_id": "meeting"})
+	assert.Nil(t, sink.Publish(context.Background(), event))
+
+	messages, err := rdb.XRange(context.Background(), "events", "-", "+").Result()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(messages))
+	assert.Equal(t, MeetingCreated, messages[0].Values["type"])
+
+	published := &Event{}
+	json.Unmarshal([]byte(messages[0].Values["event"].(string)), published)
+	assert.Equal(t, event.ID, published.ID)
+	assert.Equal(t, "meeting", published.Data["meeting_id"])
+	assert.Nil(t, sink.Close())
+}
+
+func TestWebhookSink(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	_, err := NewWebhookSink(&config.WebhookConfig{Timeout: "1s"})
+	assert.Equal(t, "webhook url is empty", err.Error())
+
+	_, err = NewWebhookSink(&config.WebhookConfig{URL: server.URL, Timeout: "1s"})
+	assert.Equal(t, "webhook secret is empty", err.Error())
+
+	sink, err := NewWebhookSink(&config.WebhookConfig{URL: server.URL, Secret: "secret", Timeout: "1s"})
+	assert.Nil(t, err)
+	event := New(InstanceOffline, "", map[string]interface{}{"instance": "http://localhost/bigbluebutton"})
+
+	t.Run("the event should be posted with its signature", func(t *testing.T) {
+		assert.Nil(t, sink.Publish(context.Background(), event))
+		assert.Equal(t, http.MethodPost, request.Method)
+		assert.Equal(t, event.ID, request.Header.Get(EventIDHeader))
+		assert.Equal(t, InstanceOffline, request.Header.Get(EventTypeHeader))
+		timestamp, _ := strconv.ParseInt(request.Header.Get(TimestampHeader), 10, 64)
+		assert.Equal(t, Sign("secret", timestamp, body), request.Header.Get(SignatureHeader))
+	})
+
+	t.Run("a non 2xx response should return an error", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		assert.NotNil(t, sink.Publish(context.Background(), event))
+	})
+
+	assert.Nil(t, sink.Close())
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "sha256=842b24d9575dee4b6ec460b7e3af7d683501d84fe6279102340787c79d2e2bab", Sign("secret", 1, []byte("body")))
+	assert.NotEqual(t, Sign("secret", 1, []byte("body")), Sign("secret", 2, []byte("body")))
+}
+
+// natsServer is a minimal NATS server accepting a single connection and sending the published messages to the channel
+func natsServer(t *testing.T, messages chan<- string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"version\":\"2.10.0\",\"max_payload\":1048576}\r\n")
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+
+			switch fields[0] {
+			case "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			case "PUB":
+				size, _ := strconv.Atoi(fields[len(fields)-1])
+				payload := make([]byte, size+2)
+				io.ReadFull(reader, payload)
+				messages <- fields[1] + " " + string(payload[:size])
+			}
+		}
+	}()
+
+	return "nats://" + listener.Addr().String()
+}
+
+func TestNATSSink(t *testing.T) {
+	messages := make(chan string, 1)
+	url := natsServer(t, messages)
+	sink, err := NewNATSSink(&config.NATSConfig{URL: url, Subject: "bigblueswarm.events"})
+	assert.Nil(t, err)
+
+	event := New(PoolLimitReached, "localhost", map[string]interface{}{"pool": "meetings"})
+	assert.Nil(t, sink.Publish(context.Background(), event))
+	assert.Nil(t, sink.Close())
+
+	message := <-messages
+	assert.True(t, strings.HasPrefix(message, "bigblueswarm.events.pool.limit_reached {"))
+	assert.Contains(t, message, event.ID)
+}
@@ -0,0 +1,91 @@
+// Package metrics exposes the bigblueswarm prometheus metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bigblueswarm"
+
+var (
+	// Registry is the registry holding the bigblueswarm metrics
+	Registry = prometheus.NewRegistry()
+
+	// Requests counts the BigBlueButton API requests by action, tenant and status code
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "BigBlueButton API requests by action, tenant and status code.",
+	}, []string{"action", "tenant", "status"})
+
+	// RequestDuration observes the BigBlueButton API requests latency by action and tenant
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "BigBlueButton API requests latency by action and tenant.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"action", "tenant"})
+
+	// BalancerDecisions counts the meetings balanced on each instance
+	BalancerDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "balancer_decisions_total",
+		Help:      "Meetings balanced on each instance.",
+	}, []string{"instance"})
+
+	// BalancerFailures counts the balancer failures to find an instance
+	BalancerFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "balancer_failures_total",
+		Help:      "Balancer failures to find an instance.",
+	})
+
+	// InstanceCallDuration observes the instances API calls latency by instance and action
+	InstanceCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "instance_call_duration_seconds",
+		Help:      "BigBlueButton instances API calls latency by instance and action.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"instance", "action"})
+
+	// InstanceCallErrors counts the failing instances API calls by instance and action
+	InstanceCallErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "instance_call_errors_total",
+		Help:      "Failing BigBlueButton instances API calls by instance and action.",
+	}, []string{"instance", "action"})
+
+	// RecordingsPollDuration observes the recordings poller duration
+	RecordingsPollDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "recordings_poll_duration_seconds",
+		Help:      "Recordings poller duration.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300},
+	})
+
+	// MapperSizeDesc describes the number of sessions stored by the mapper by kind. It is collected on scrape
+	MapperSizeDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "mapper", "sessions"), "Sessions stored by the mapper by kind.", []string{"kind"}, nil)
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		Requests,
+		RequestDuration,
+		BalancerDecisions,
+		BalancerFailures,
+		InstanceCallDuration,
+		InstanceCallErrors,
+		RecordingsPollDuration,
+	)
+}
+
+// Handler returns the http handler exposing the metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,171 @@
+// Package restclient is an abstration that perform http requests
+package restclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+)
+
+// InitWithConfig initializes the restclient package with a client built from the configuration.
+func InitWithConfig(conf *config.HTTPClientConfig) error {
+	client, err := NewClient(conf)
+	if err != nil {
+		return err
+	}
+
+	Client = client
+	return nil
+}
+
+func parseDuration(name string, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s: %s", name, value, err)
+	}
+
+	return duration, nil
+}
+
+func tlsConfig(conf *config.HTTPClientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if conf.CAFile != "" {
+		bundle, err := ioutil.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %s", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("CA bundle %s does not contain any PEM certificate", conf.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %s", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewClient creates an http client from the configuration. The client retries the failing requests marked as
+// retryable if retries are configured
+func NewClient(conf *config.HTTPClientConfig) (HTTPClient, error) {
+	connectTimeout, err := parseDuration("connect timeout", conf.ConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	readTimeout, err := parseDuration("read timeout", conf.ReadTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff, err := parseDuration("retry backoff", conf.RetryBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := tlsConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = readTimeout
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{Transport: transport}
+
+	if conf.Retries <= 0 {
+		return client, nil
+	}
+
+	return &RetryClient{Client: client, Retries: conf.Retries, Backoff: backoff}, nil
+}
+
+// RetryClient is an HTTP client retrying the requests marked as retryable failing with a network error or a temporary
+// unavailability status. The delay between two attempts is doubled on each retry
+type RetryClient struct {
+	Client  HTTPClient
+	Retries int
+	Backoff time.Duration
+}
+
+type retryableKey struct{}
+
+// WithRetry marks the requests performed with the returned context as retryable. The HTTP method does not tell if a
+// request is safe to retry since the BigBlueButton API changes the instances state on GET requests, so the callers
+// must explicitly mark the requests that can be performed twice
+func WithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableKey{}, true)
+}
+
+func isRetryAllowed(req *http.Request) bool {
+	allowed, _ := req.Context().Value(retryableKey{}).(bool)
+	return allowed
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Do performs the request, retrying it if it is marked as retryable and failed
+func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.Client.Do(req)
+		if attempt >= c.Retries || !isRetryAllowed(req) || !isRetryable(resp, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		if resp != nil && resp.Body != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(c.Backoff << attempt):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = body
+		}
+	}
+}
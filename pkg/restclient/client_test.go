@@ -0,0 +1,130 @@
+package restclient
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingClient struct {
+	calls     int
+	responses []*http.Response
+	errors    []error
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	i := c.calls
+	c.calls++
+	return c.responses[i], c.errors[i]
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("invalid durations should return an error", func(t *testing.T) {
+		_, err := NewClient(&config.HTTPClientConfig{ConnectTimeout: "five seconds"})
+		assert.NotNil(t, err)
+		_, err = NewClient(&config.HTTPClientConfig{ReadTimeout: "1x"})
+		assert.NotNil(t, err)
+		_, err = NewClient(&config.HTTPClientConfig{RetryBackoff: "1x"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("a missing client certificate should return an error", func(t *testing.T) {
+		_, err := NewClient(&config.HTTPClientConfig{CertFile: "missing.pem", KeyFile: "missing.key"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("a CA bundle without certificate should return an error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		assert.Nil(t, os.WriteFile(path, []byte("not a certificate"), 0600))
+		_, err := NewClient(&config.HTTPClientConfig{CAFile: path})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("a client should trust the instances signed by the CA bundle", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		untrusted, err := NewClient(&config.HTTPClientConfig{})
+		assert.Nil(t, err)
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		_, err = untrusted.Do(req)
+		assert.NotNil(t, err)
+
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		assert.Nil(t, os.WriteFile(path, bundle, 0600))
+		client, err := NewClient(&config.HTTPClientConfig{CAFile: path, ConnectTimeout: "1s", ReadTimeout: "1s"})
+		assert.Nil(t, err)
+		resp, err := client.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("retries should wrap the client in a retry client", func(t *testing.T) {
+		client, err := NewClient(&config.HTTPClientConfig{Retries: 2, RetryBackoff: "10ms"})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, client.(*RetryClient).Retries)
+		assert.Equal(t, 10*time.Millisecond, client.(*RetryClient).Backoff)
+	})
+}
+
+func TestRetryClient(t *testing.T) {
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}
+	ok := &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}
+
+	t.Run("a failing retryable request should be retried", func(t *testing.T) {
+		mock := &countingClient{responses: []*http.Response{nil, unavailable, ok}, errors: []error{errors.New("connection reset"), nil, nil}}
+		client := &RetryClient{Client: mock, Retries: 2, Backoff: time.Millisecond}
+		req, _ := http.NewRequestWithContext(WithRetry(context.Background()), http.MethodGet, "http://localhost", nil)
+		resp, err := client.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, ok, resp)
+		assert.Equal(t, 3, mock.calls)
+	})
+
+	t.Run("the last response should be returned once retries are exhausted", func(t *testing.T) {
+		mock := &countingClient{responses: []*http.Response{unavailable, unavailable}, errors: []error{nil, nil}}
+		client := &RetryClient{Client: mock, Retries: 1, Backoff: time.Millisecond}
+		req, _ := http.NewRequestWithContext(WithRetry(context.Background()), http.MethodGet, "http://localhost", nil)
+		resp, err := client.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, 2, mock.calls)
+	})
+
+	t.Run("a request not marked as retryable should not be retried", func(t *testing.T) {
+		mock := &countingClient{responses: []*http.Response{unavailable}, errors: []error{nil}}
+		client := &RetryClient{Client: mock, Retries: 2, Backoff: time.Millisecond}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+		_, err := client.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, mock.calls)
+	})
+
+	t.Run("a client error status should not be retried", func(t *testing.T) {
+		mock := &countingClient{responses: []*http.Response{{StatusCode: http.StatusNotFound, Body: http.NoBody}}, errors: []error{nil}}
+		client := &RetryClient{Client: mock, Retries: 2, Backoff: time.Millisecond}
+		req, _ := http.NewRequestWithContext(WithRetry(context.Background()), http.MethodGet, "http://localhost", nil)
+		resp, _ := client.Do(req)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, 1, mock.calls)
+	})
+
+	t.Run("a canceled request should not be retried", func(t *testing.T) {
+		mock := &countingClient{responses: []*http.Response{nil}, errors: []error{context.Canceled}}
+		client := &RetryClient{Client: mock, Retries: 2, Backoff: time.Millisecond}
+		req, _ := http.NewRequestWithContext(WithRetry(context.Background()), http.MethodGet, "http://localhost", nil)
+		_, err := client.Do(req)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, mock.calls)
+	})
+}
@@ -3,7 +3,11 @@ package restclient
 
 import (
 	"bytes"
+	"context"
 	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 var (
@@ -23,39 +27,45 @@ type HTTPClient interface {
 
 // Get performs an HTTP GET request.
 func Get(url string) (*http.Response, error) {
-	return perform(http.MethodGet, url, map[string]string{}, nil)
+	return perform(context.Background(), http.MethodGet, url, map[string]string{}, nil)
+}
+
+// GetWithContext performs an HTTP GET request canceled when the context is done.
+func GetWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return perform(ctx, http.MethodGet, url, map[string]string{}, nil)
 }
 
-func perform(method string, url string, headers map[string]string, body []byte) (*http.Response, error) {
-	request, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+func perform(ctx context.Context, method string, url string, headers map[string]string, body []byte) (*http.Response, error) {
+	request, _ := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
 	for k, v := range headers {
 		request.Header.Add(k, v)
 	}
 
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
 	return Client.Do(request)
 }
 
 // GetWithHeaders performs an HTTP GET request with headers.
 func GetWithHeaders(url string, headers map[string]string) (*http.Response, error) {
-	return perform(http.MethodGet, url, headers, nil)
+	return perform(context.Background(), http.MethodGet, url, headers, nil)
 }
 
 // Post performs an HTTP POST request.
 func Post(url string, body []byte) (*http.Response, error) {
-	return perform(http.MethodPost, url, map[string]string{}, body)
+	return perform(context.Background(), http.MethodPost, url, map[string]string{}, body)
 }
 
 // PostWithHeaders performs an HTTP POST request with headers.
 func PostWithHeaders(url string, headers map[string]string, body []byte) (*http.Response, error) {
-	return perform(http.MethodPost, url, headers, body)
+	return perform(context.Background(), http.MethodPost, url, headers, body)
 }
 
 // Delete performs an HTTP DELETE request.
 func Delete(url string) (*http.Response, error) {
-	return perform(http.MethodDelete, url, map[string]string{}, nil)
+	return perform(context.Background(), http.MethodDelete, url, map[string]string{}, nil)
 }
 
 // DeleteWithHeaders performs an HTTP DELETE request with headers.
 func DeleteWithHeaders(url string, headers map[string]string) (*http.Response, error) {
-	return perform(http.MethodDelete, url, headers, nil)
+	return perform(context.Background(), http.MethodDelete, url, headers, nil)
 }
@@ -1,13 +1,18 @@
 package restclient
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/bigblueswarm/test_utils/pkg/test"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type HTTPTest struct {
@@ -54,6 +59,40 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestGetWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := GetWithContext(ctx, fmt.Sprintf("%s/test_get_with_context", server.URL))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGetWithContextPropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+	_, err := GetWithContext(ctx, fmt.Sprintf("%s/test_get_with_context", server.URL))
+	assert.Nil(t, err)
+	assert.Equal(t, "00-01000000000000000000000000000000-0200000000000000-01", traceparent)
+}
+
 func TestGetWithHeaders(t *testing.T) {
 	tests := []HTTPTest{
 		{
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigblueswarm/bigblueswarm/v3/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func initTestProvider() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return exporter
+}
+
+func TestInit(t *testing.T) {
+	shutdown, err := Init(&config.TracingConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	_, err = Init(&config.TracingConfig{Enabled: true, Exporter: "unknown"})
+	assert.NotNil(t, err)
+
+	shutdown, err = Init(&config.TracingConfig{Enabled: true, Exporter: "stdout", SampleRatio: 1})
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+}
+
+func TestEnd(t *testing.T) {
+	exporter := initTestProvider()
+	_, span := Start(context.Background(), "success")
+	End(span, nil)
+	_, span = Start(context.Background(), "failure")
+	End(span, errors.New("failure"))
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, 1, len(spans[1].Events))
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := initTestProvider()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var child trace.SpanContext
+	router.GET("/bigbluebutton/api/create", Middleware, func(c *gin.Context) {
+		_, span := Start(c.Request.Context(), "child")
+		child = span.SpanContext()
+		span.End()
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/bigbluebutton/api/create", nil)
+	req.Header.Set("traceparent", "00-01000000000000000000000000000000-0200000000000000-01")
+	router.ServeHTTP(w, req)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	server := spans[1]
+	assert.Equal(t, "GET /bigbluebutton/api/create", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "01000000000000000000000000000000", server.SpanContext.TraceID().String())
+	assert.Equal(t, "0200000000000000", server.Parent.SpanID().String())
+	assert.Equal(t, codes.Error, server.Status.Code)
+	assert.Equal(t, server.SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, server.SpanContext.TraceID(), child.TraceID())
+}
@@ -1,12 +1,156 @@
 // Package utils provide few utilies functions
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"fmt"
+	"net"
+	"strings"
 
-// GetHost get the gin request host. It returns the X-Forwarded-Host header if present however it returns the request host
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultTrustedProxies is the trusted proxies list used when none is configured. Only loopback proxies are trusted
+var DefaultTrustedProxies = []string{"127.0.0.0/8", "::1/128"}
+
+var trustedProxies = mustParseProxies(DefaultTrustedProxies)
+
+// ParseNetworks parse a list of CIDR or IP addresses. An IP address is considered as a single host network
+func ParseNetworks(addresses []string) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if !strings.Contains(address, "/") {
+			ip := net.ParseIP(address)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %s", address)
+			}
+
+			if ip.To4() != nil {
+				address += "/32"
+			} else {
+				address += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %s: %s", address, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// ParseTrustedProxies parse a list of CIDR or IP addresses. An IP address is considered as a single host network
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks, err := ParseNetworks(proxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxy: %s", err)
+	}
+
+	return networks, nil
+}
+
+// NetworksContain check if the address belongs to one of the networks. The address may contain a port
+func NetworksContain(networks []*net.IPNet, address string) bool {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+
+	ip := net.ParseIP(strings.Trim(address, "[]"))
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mustParseProxies(proxies []string) []*net.IPNet {
+	networks, err := ParseTrustedProxies(proxies)
+	if err != nil {
+		panic(err)
+	}
+
+	return networks
+}
+
+// SetTrustedProxies set the proxies allowed to forward the request host. A nil list restores the default trusted proxies
+// and an empty list disables forwarded headers
+func SetTrustedProxies(proxies []string) error {
+	if proxies == nil {
+		proxies = DefaultTrustedProxies
+	}
+
+	networks, err := ParseTrustedProxies(proxies)
+	if err != nil {
+		return err
+	}
+
+	trustedProxies = networks
+	return nil
+}
+
+// IsTrustedProxy check if the address is a trusted proxy address. The address may contain a port
+func IsTrustedProxy(address string) bool {
+	return NetworksContain(trustedProxies, address)
+}
+
+func parseForwardedElement(element string) map[string]string {
+	pairs := map[string]string{}
+	for _, pair := range strings.Split(element, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			continue
+		}
+
+		pairs[strings.ToLower(key)] = strings.Trim(value, "\"")
+	}
+
+	return pairs
+}
+
+// forwardedHost returns the host from a Forwarded header (RFC 7239). Elements are read from the closest proxy to the
+// client and the lookup stops on the first element added for an untrusted address, so a client can't spoof the host
+func forwardedHost(header string) string {
+	elements := strings.Split(header, ",")
+	for i := len(elements) - 1; i >= 0; i-- {
+		pairs := parseForwardedElement(elements[i])
+		if host := pairs["host"]; host != "" {
+			return host
+		}
+
+		if !IsTrustedProxy(pairs["for"]) {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// GetHost get the gin request host. If the request comes from a trusted proxy, it returns the host provided by the
+// Forwarded header or the X-Forwarded-Host header. Otherwise it returns the request host
 func GetHost(ctx *gin.Context) string {
-	if host := ctx.Request.Header.Get("X-Forwarded-Host"); host != "" {
-		return host
+	if !IsTrustedProxy(ctx.Request.RemoteAddr) {
+		return ctx.Request.Host
+	}
+
+	if header := strings.Join(ctx.Request.Header.Values("Forwarded"), ","); header != "" {
+		if host := forwardedHost(header); host != "" {
+			return host
+		}
+	}
+
+	if header := ctx.Request.Header.Get("X-Forwarded-Host"); header != "" {
+		hosts := strings.Split(header, ",")
+		return strings.TrimSpace(hosts[len(hosts)-1])
 	}
 
 	return ctx.Request.Host
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"net"
 	"testing"
 
 	"github.com/bigblueswarm/test_utils/pkg/request"
@@ -8,18 +9,95 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestParseTrustedProxies(t *testing.T) {
+	networks, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "::1"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(networks))
+	assert.Equal(t, "192.168.1.1/32", networks[1].String())
+	assert.Equal(t, "::1/128", networks[2].String())
+
+	_, err = ParseTrustedProxies([]string{"not_an_ip"})
+	assert.NotNil(t, err)
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.NotNil(t, err)
+}
+
+func TestNetworksContain(t *testing.T) {
+	networks, err := ParseNetworks([]string{"10.0.0.0/8", "2001:db8::1"})
+	assert.Nil(t, err)
+
+	assert.True(t, NetworksContain(networks, "10.1.2.3"))
+	assert.True(t, NetworksContain(networks, "[2001:db8::1]:4567"))
+	assert.False(t, NetworksContain(networks, "192.168.1.1"))
+	assert.False(t, NetworksContain(networks, "not_an_ip"))
+	assert.False(t, NetworksContain([]*net.IPNet{}, "10.1.2.3"))
+}
+
+func TestIsTrustedProxy(t *testing.T) {
+	defer SetTrustedProxies(nil)
+	assert.Nil(t, SetTrustedProxies([]string{"10.0.0.0/8", "2001:db8::/32"}))
+
+	assert.True(t, IsTrustedProxy("10.1.2.3"))
+	assert.True(t, IsTrustedProxy("10.1.2.3:4567"))
+	assert.True(t, IsTrustedProxy("[2001:db8::1]:4567"))
+	assert.False(t, IsTrustedProxy("127.0.0.1:4567"))
+	assert.False(t, IsTrustedProxy("unknown"))
+	assert.False(t, IsTrustedProxy(""))
+}
+
 func TestGetHost(t *testing.T) {
-	c, _ := gin.CreateTestContext(nil)
+	var c *gin.Context
+	defer SetTrustedProxies(nil)
+	assert.Nil(t, SetTrustedProxies([]string{"10.0.0.0/8"}))
+
+	reset := func(remoteAddr string) {
+		c, _ = gin.CreateTestContext(nil)
+		request.SetRequestHost(c, "localhost")
+		c.Request.RemoteAddr = remoteAddr
+	}
 
-	t.Run("No X-Forwarded-Host host should return the host request", func(t *testing.T) {
-		host := "localhost"
-		request.SetRequestHost(c, host)
-		assert.Equal(t, host, GetHost(c))
+	t.Run("No forwarded header should return the host request", func(t *testing.T) {
+		reset("10.0.0.1:1234")
+		assert.Equal(t, "localhost", GetHost(c))
 	})
 
 	t.Run("A X-Forwarded-Host header should be returned instead of request host", func(t *testing.T) {
-		headerValue := "mydummyheaderhostname"
-		request.SetRequestHeader(c, "X-Forwarded-Host", headerValue)
-		assert.Equal(t, headerValue, GetHost(c))
+		reset("10.0.0.1:1234")
+		request.SetRequestHeader(c, "X-Forwarded-Host", "mydummyheaderhostname")
+		assert.Equal(t, "mydummyheaderhostname", GetHost(c))
+	})
+
+	t.Run("The last X-Forwarded-Host value should be returned", func(t *testing.T) {
+		reset("10.0.0.1:1234")
+		request.SetRequestHeader(c, "X-Forwarded-Host", "spoofed, mydummyheaderhostname")
+		assert.Equal(t, "mydummyheaderhostname", GetHost(c))
+	})
+
+	t.Run("Forwarded headers from an untrusted source should be ignored", func(t *testing.T) {
+		reset("192.168.1.1:1234")
+		request.SetRequestHeader(c, "X-Forwarded-Host", "mydummyheaderhostname")
+		request.SetRequestHeader(c, "Forwarded", "host=mydummyheaderhostname")
+		assert.Equal(t, "localhost", GetHost(c))
+	})
+
+	t.Run("A Forwarded header should take precedence over X-Forwarded-Host", func(t *testing.T) {
+		reset("10.0.0.1:1234")
+		request.SetRequestHeader(c, "X-Forwarded-Host", "xforwarded")
+		request.SetRequestHeader(c, "Forwarded", `for=192.168.1.1;proto=https;host="forwarded"`)
+		assert.Equal(t, "forwarded", GetHost(c))
+	})
+
+	t.Run("A Forwarded host spoofed by the client should be ignored", func(t *testing.T) {
+		reset("10.0.0.1:1234")
+		request.SetRequestHeader(c, "Forwarded", "host=spoofed, for=192.168.1.1")
+		assert.Equal(t, "localhost", GetHost(c))
+	})
+
+	t.Run("A Forwarded host set by a trusted proxy chain should be returned", func(t *testing.T) {
+		reset("10.0.0.1:1234")
+		c.Request.Header.Add("Forwarded", "for=192.168.1.1;host=spoofed")
+		c.Request.Header.Add("Forwarded", "for=192.168.1.2;host=edge, for=10.0.0.2")
+		assert.Equal(t, "edge", GetHost(c))
 	})
 }
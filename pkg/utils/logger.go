@@ -0,0 +1,135 @@
+// Package utils provide few utilies functions
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader is the header carrying the request identifier. A valid provided identifier is honoured and the
+// request identifier is always echoed in the response
+const RequestIDHeader = "X-Request-ID"
+
+const loggerKey = "logger"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// redactedParams are the BigBlueButton API parameters whose values are never logged
+var redactedParams = []string{"attendeePW", "moderatorPW", "password"}
+
+// Redacted replaces the redacted parameters values in the logs
+const Redacted = "REDACTED"
+
+// RequestLogger is a request scoped logger initialized with a request identifier. Its fields are reused by the request
+// access log so the log entries of a request share the same schema
+type RequestLogger struct {
+	*log.Entry
+}
+
+// NewRequestLogger creates a request logger for the request identifier
+func NewRequestLogger(requestID string) *RequestLogger {
+	return &RequestLogger{
+		log.WithFields(log.Fields{
+			"request_id": requestID,
+		}),
+	}
+}
+
+// RequestID returns the identifier provided in the request X-Request-ID header or a new identifier if the header is
+// missing or invalid
+func RequestID(c *gin.Context) string {
+	if c.Request != nil {
+		if id := c.GetHeader(RequestIDHeader); requestIDPattern.MatchString(id) {
+			return id
+		}
+	}
+
+	return uuid.New().String()
+}
+
+// RequestLoggerMiddleware returns a middleware storing a request logger in the request context and logging the
+// request status and latency once processed. The action function names the requested action
+func RequestLoggerMiddleware(action func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := RequestID(c)
+		c.Header(RequestIDHeader, id)
+		logger := NewRequestLogger(id).AddField("action", action(c))
+		c.Set(loggerKey, logger)
+
+		c.Next()
+
+		logger.SetFields(log.Fields{
+			"status":  c.Writer.Status(),
+			"latency": float64(time.Since(start)) / float64(time.Millisecond),
+		})
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			logger.Error("request failed")
+		} else {
+			logger.Info("request processed")
+		}
+	}
+}
+
+// GetRequestLogger returns the request logger. A new logger is stored in the request context if none exists
+func GetRequestLogger(c *gin.Context) *RequestLogger {
+	if logger, exists := c.Get(loggerKey); exists {
+		return logger.(*RequestLogger)
+	}
+
+	logger := NewRequestLogger(RequestID(c))
+	c.Set(loggerKey, logger)
+	return logger
+}
+
+// SetFields adds the fields to the request logger
+func (rl *RequestLogger) SetFields(fields log.Fields) *RequestLogger {
+	for k, v := range fields {
+		rl.Data[k] = v
+	}
+
+	return rl
+}
+
+// AddField adds a field to the request logger
+func (rl *RequestLogger) AddField(key string, value string) *RequestLogger {
+	rl.Data[key] = value
+	return rl
+}
+
+// RedactParams returns the query parameters with the passwords values redacted so they can be logged
+func RedactParams(params string) string {
+	if params == "" {
+		return params
+	}
+
+	values := strings.Split(params, "&")
+	for i, param := range values {
+		key, _, found := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+
+		if found && ArrayContainsString(redactedParams, key) {
+			values[i] = fmt.Sprintf("%s=%s", key, Redacted)
+		}
+	}
+
+	return strings.Join(values, "&")
+}
+
+// Dup duplicates the request logger. Fields added to the duplicate are not added to the request logger
+func (rl *RequestLogger) Dup() *RequestLogger {
+	return &RequestLogger{
+		rl.Entry.Dup(),
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set(RequestIDHeader, "my-request.id:1")
+	assert.Equal(t, "my-request.id:1", RequestID(c))
+
+	c.Request.Header.Set(RequestIDHeader, "invalid request id\n")
+	assert.Len(t, RequestID(c), 36)
+
+	c.Request.Header.Del(RequestIDHeader)
+	assert.Len(t, RequestID(c), 36)
+
+	c.Request = nil
+	assert.Len(t, RequestID(c), 36)
+}
+
+func TestRequestLoggerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hook := test.NewGlobal()
+	defer log.StandardLogger().ReplaceHooks(log.LevelHooks{})
+
+	router := gin.New()
+	router.GET("/test", RequestLoggerMiddleware(func(c *gin.Context) string {
+		return "test"
+	}), func(c *gin.Context) {
+		GetRequestLogger(c).AddField("tenant", "localhost")
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, "request-id")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "request-id", w.Header().Get(RequestIDHeader))
+	entry := hook.LastEntry()
+	assert.Equal(t, log.ErrorLevel, entry.Level)
+	assert.Equal(t, "request-id", entry.Data["request_id"])
+	assert.Equal(t, "test", entry.Data["action"])
+	assert.Equal(t, "localhost", entry.Data["tenant"])
+	assert.Equal(t, http.StatusInternalServerError, entry.Data["status"])
+	assert.Contains(t, entry.Data, "latency")
+}
+
+func TestRequestLoggerDup(t *testing.T) {
+	logger := NewRequestLogger("request-id")
+	logger.Dup().AddField("tenant", "localhost")
+	assert.NotContains(t, logger.Data, "tenant")
+	logger.SetFields(log.Fields{"tenant": "localhost"})
+	assert.Equal(t, "localhost", logger.Data["tenant"])
+}
+
+func TestRedactParams(t *testing.T) {
+	assert.Equal(t, "", RedactParams(""))
+	assert.Equal(t, "name=doe&meetingID=id", RedactParams("name=doe&meetingID=id"))
+	assert.Equal(t,
+		"name=doe&attendeePW=REDACTED&moderatorPW=REDACTED&fullName=john&password=REDACTED&meta_password=value",
+		RedactParams("name=doe&attendeePW=ap&moderatorPW=mp&fullName=john&password=secret&meta_password=value"),
+	)
+	assert.Equal(t, "password=REDACTED", RedactParams("pass%77ord=secret"))
+}